@@ -94,6 +94,65 @@ type Resource struct {
 	ReadyWhen []string `json:"readyWhen,omitempty"`
 	// +kubebuilder:validation:Optional
 	IncludeWhen []string `json:"includeWhen,omitempty"`
+	// DeletionPolicy decides what happens to the resource when the instance
+	// is deleted: Delete (default), Orphan or Retain. It can embed
+	// expressions, e.g "${schema.spec.env == 'prod' ? 'Retain' : 'Delete'}".
+	//
+	// +kubebuilder:validation:Optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// Priority breaks the ties between the resources of a topological level,
+	// the resources with the highest priority are created first. It's an
+	// integer, or expressions computing it, e.g "${schema.spec.critical ? 10 : 0}".
+	//
+	// +kubebuilder:validation:Optional
+	Priority string `json:"priority,omitempty"`
+	// ReadinessDependencies are the ids of the resources that need to be
+	// ready, not only created, before the resource is processed.
+	//
+	// +kubebuilder:validation:Optional
+	ReadinessDependencies []string `json:"readinessDependencies,omitempty"`
+	// ReadyWhenMessages are the messages reported when a readyWhen condition
+	// isn't met, keyed by condition. The messages can embed expressions, e.g
+	// "waiting for ${deployment.status.readyReplicas} replicas".
+	//
+	// +kubebuilder:validation:Optional
+	ReadyWhenMessages map[string]string `json:"readyWhenMessages,omitempty"`
+	// SkipReadiness makes the resource ready as soon as it's created, its
+	// readiness isn't checked.
+	//
+	// +kubebuilder:validation:Optional
+	SkipReadiness bool `json:"skipReadiness,omitempty"`
+	// IgnoreUpdateErrors reports the failures to update the resource without
+	// failing the reconciliation of the instance.
+	//
+	// +kubebuilder:validation:Optional
+	IgnoreUpdateErrors bool `json:"ignoreUpdateErrors,omitempty"`
+	// Fields configures how the expressions of the template fields are
+	// resolved.
+	//
+	// +kubebuilder:validation:Optional
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Field configures how the expressions of a template field are resolved.
+type Field struct {
+	// Path is the path of the field in the template, e.g "spec.replicas".
+	//
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+	// PatchType makes the (standalone) expression of the field produce a
+	// patch, applied to the observed value of the field: "json" for a JSON
+	// patch, "merge" for a JSON merge patch.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=json;merge
+	PatchType string `json:"patchType,omitempty"`
+	// RequiredFeatures are the feature flags the expressions of the field opt
+	// in to. They get access to the functions gated behind the flags, and
+	// fail if one of them is disabled.
+	//
+	// +kubebuilder:validation:Optional
+	RequiredFeatures []string `json:"requiredFeatures,omitempty"`
 }
 
 // ResourceGraphDefinitionState defines the state of the resource graph definition.
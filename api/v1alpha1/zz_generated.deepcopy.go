@@ -65,6 +65,26 @@ func (in *Dependency) DeepCopy() *Dependency {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Field) DeepCopyInto(out *Field) {
+	*out = *in
+	if in.RequiredFeatures != nil {
+		in, out := &in.RequiredFeatures, &out.RequiredFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Field.
+func (in *Field) DeepCopy() *Field {
+	if in == nil {
+		return nil
+	}
+	out := new(Field)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
@@ -79,6 +99,25 @@ func (in *Resource) DeepCopyInto(out *Resource) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReadinessDependencies != nil {
+		in, out := &in.ReadinessDependencies, &out.ReadinessDependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadyWhenMessages != nil {
+		in, out := &in.ReadyWhenMessages, &out.ReadyWhenMessages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]Field, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resource.
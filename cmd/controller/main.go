@@ -16,7 +16,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"go.uber.org/zap/zapcore"
@@ -74,6 +76,7 @@ func main() {
 	var logLevel int
 	var qps float64
 	var burst int
+	var clusterInfo string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8078", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8079", "The address the probe endpoint binds to.")
@@ -103,6 +106,11 @@ func main() {
 	flag.IntVar(&burst, "client-burst", 150,
 		"The number of requests that can be stored for processing before the server starts enforcing the QPS limit")
 
+	// cluster identity
+	flag.StringVar(&clusterInfo, "cluster-info", "",
+		"Comma separated key=value pairs identifying the cluster, e.g name=prod,region=eu-west-1. "+
+			"They are exposed to the expressions as the kroCluster variable")
+
 	flag.Parse()
 
 	opts := zap.Options{
@@ -114,6 +122,12 @@ func main() {
 
 	ctrl.SetLogger(rootLogger)
 
+	clusterInfoMap, err := parseClusterInfo(clusterInfo)
+	if err != nil {
+		setupLog.Error(err, "invalid cluster info")
+		os.Exit(1)
+	}
+
 	set, err := kroclient.NewSet(kroclient.Config{
 		QPS:   float32(qps),
 		Burst: burst,
@@ -172,6 +186,7 @@ func main() {
 		allowCRDDeletion,
 		dc,
 		resourceGraphDefinitionGraphBuilder,
+		clusterInfoMap,
 	)
 	err = ctrl.NewControllerManagedBy(
 		mgr,
@@ -213,3 +228,20 @@ func main() {
 	<-ctx.Done()
 
 }
+
+// parseClusterInfo parses comma separated key=value pairs, e.g
+// "name=prod,region=eu-west-1".
+func parseClusterInfo(value string) (map[string]interface{}, error) {
+	info := map[string]interface{}{}
+	if value == "" {
+		return info, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		info[key] = val
+	}
+	return info, nil
+}
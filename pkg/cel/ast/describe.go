@@ -0,0 +1,166 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+)
+
+// ASTInfo is the full analysis of a CEL expression, as computed by the
+// environment during compilation. It's meant for tooling, e.g expression
+// debuggers highlighting the syntax and showing the dependencies.
+type ASTInfo struct {
+	// Expression is the described expression.
+	Expression string
+	// Root is the root node of the checked AST.
+	Root *ASTNode
+	// OutputType is the type of the expression result, e.g "int" or
+	// "list(dyn)".
+	OutputType string
+	// References are the variables referenced by the expression, with the
+	// paths accessed on them.
+	References []ResourceDependency
+	// FunctionCalls are the function calls of the expression.
+	FunctionCalls []FunctionCall
+	// Cost is the estimated evaluation cost range of the expression.
+	Cost checker.CostEstimate
+}
+
+// ASTNode is a node of the checked AST of an expression.
+type ASTNode struct {
+	// ID is the id of the node, as assigned by the CEL parser.
+	ID int64
+	// Kind is the kind of the node: "constant", "ident", "select", "call",
+	// "list", "map" or "comprehension".
+	Kind string
+	// Text is the node rendered back as an expression.
+	Text string
+	// Type is the checked type of the node.
+	Type string
+	// Children are the sub expressions of the node.
+	Children []*ASTNode
+}
+
+// Describe compiles the expression in the default environment, configured
+// with the given options, and returns its analysis.
+func Describe(expression string, opts ...krocel.EnvOption) (*ASTInfo, error) {
+	env, err := krocel.DefaultEnvironment(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	checked, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression: %w", iss.Err())
+	}
+	checkedExpr, err := cel.AstToCheckedExpr(checked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert checked expression: %w", err)
+	}
+	cost, err := env.EstimateCost(checked, noopCostEstimator{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate expression cost: %w", err)
+	}
+
+	// The expression is checked, so every identifier the inspector doesn't
+	// know about (and isn't a loop variable) is a declared variable.
+	inspector := NewInspectorWithEnv(env, nil, nil)
+	inspection, err := inspector.Inspect(expression)
+	if err != nil {
+		return nil, err
+	}
+	references := make([]ResourceDependency, 0, len(inspection.UnknownResources))
+	for _, resource := range inspection.UnknownResources {
+		references = append(references, ResourceDependency{ID: resource.ID, Path: resource.Path})
+	}
+
+	return &ASTInfo{
+		Expression:    expression,
+		Root:          describeNode(inspector, checkedExpr.GetExpr(), checkedExpr.GetTypeMap()),
+		OutputType:    checked.OutputType().String(),
+		References:    references,
+		FunctionCalls: inspection.FunctionCalls,
+		Cost:          cost,
+	}, nil
+}
+
+// describeNode converts the expression, and its sub expressions, to ASTNodes.
+func describeNode(inspector *Inspector, expr *exprpb.Expr, types map[int64]*exprpb.Type) *ASTNode {
+	if expr == nil {
+		return nil
+	}
+	node := &ASTNode{
+		ID:   expr.GetId(),
+		Text: inspector.exprToString(expr),
+	}
+	if t, ok := types[expr.GetId()]; ok {
+		if celType, err := cel.ExprTypeToType(t); err == nil {
+			node.Type = celType.String()
+		}
+	}
+
+	var children []*exprpb.Expr
+	switch e := expr.ExprKind.(type) {
+	case *exprpb.Expr_ConstExpr:
+		node.Kind = "constant"
+	case *exprpb.Expr_IdentExpr:
+		node.Kind = "ident"
+	case *exprpb.Expr_SelectExpr:
+		node.Kind = "select"
+		children = append(children, e.SelectExpr.GetOperand())
+	case *exprpb.Expr_CallExpr:
+		node.Kind = "call"
+		if target := e.CallExpr.GetTarget(); target != nil {
+			children = append(children, target)
+		}
+		children = append(children, e.CallExpr.GetArgs()...)
+	case *exprpb.Expr_ListExpr:
+		node.Kind = "list"
+		children = append(children, e.ListExpr.GetElements()...)
+	case *exprpb.Expr_StructExpr:
+		node.Kind = "map"
+		for _, entry := range e.StructExpr.GetEntries() {
+			if key := entry.GetMapKey(); key != nil {
+				children = append(children, key)
+			}
+			children = append(children, entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		node.Kind = "comprehension"
+		node.Text = fmt.Sprintf("<comprehension over %s>", inspector.exprToString(e.ComprehensionExpr.GetIterRange()))
+		c := e.ComprehensionExpr
+		children = append(children, c.GetIterRange(), c.GetAccuInit(), c.GetLoopCondition(), c.GetLoopStep(), c.GetResult())
+	}
+	for _, child := range children {
+		node.Children = append(node.Children, describeNode(inspector, child, types))
+	}
+	return node
+}
+
+// noopCostEstimator doesn't provide any size or cost estimate, leaving the
+// defaults of the CEL cost model.
+type noopCostEstimator struct{}
+
+func (noopCostEstimator) EstimateSize(checker.AstNode) *checker.SizeEstimate {
+	return nil
+}
+
+func (noopCostEstimator) EstimateCallCost(string, string, *checker.AstNode, []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
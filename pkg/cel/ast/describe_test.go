@@ -0,0 +1,104 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ast
+
+import (
+	"reflect"
+	"testing"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name           string
+		resources      []string
+		expression     string
+		wantOutputType string
+		wantReferences []ResourceDependency
+		wantRootKind   string
+		wantErr        bool
+	}{
+		{
+			name:           "literal",
+			expression:     `1 + 2`,
+			wantOutputType: "int",
+			wantReferences: []ResourceDependency{},
+			wantRootKind:   "call",
+		},
+		{
+			name:           "resource field comparison",
+			resources:      []string{"deployment", "schema"},
+			expression:     `deployment.status.readyReplicas == schema.spec.replicas`,
+			wantOutputType: "bool",
+			wantReferences: []ResourceDependency{
+				{ID: "deployment", Path: "deployment.status.readyReplicas"},
+				{ID: "schema", Path: "schema.spec.replicas"},
+			},
+			wantRootKind: "call",
+		},
+		{
+			name:           "comprehension",
+			resources:      []string{"schema"},
+			expression:     `schema.spec.ports.map(p, p.port)`,
+			wantOutputType: "list(dyn)",
+			wantReferences: []ResourceDependency{
+				{ID: "schema", Path: "schema.spec.ports"},
+			},
+			wantRootKind: "comprehension",
+		},
+		{
+			name:           "string function",
+			resources:      []string{"schema"},
+			expression:     `"prefix-" + schema.spec.name`,
+			wantOutputType: "string",
+			wantReferences: []ResourceDependency{
+				{ID: "schema", Path: "schema.spec.name"},
+			},
+			wantRootKind: "call",
+		},
+		{
+			name:       "undeclared variable",
+			expression: `deployment.spec`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Describe(tt.expression, krocel.WithResourceIDs(tt.resources))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Describe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if info.OutputType != tt.wantOutputType {
+				t.Errorf("OutputType = %q, want %q", info.OutputType, tt.wantOutputType)
+			}
+			if !reflect.DeepEqual(info.References, tt.wantReferences) {
+				t.Errorf("References = %v, want %v", info.References, tt.wantReferences)
+			}
+			if info.Root.Kind != tt.wantRootKind {
+				t.Errorf("Root.Kind = %q, want %q", info.Root.Kind, tt.wantRootKind)
+			}
+			if info.Root.Type != tt.wantOutputType {
+				t.Errorf("Root.Type = %q, want %q", info.Root.Type, tt.wantOutputType)
+			}
+			if info.Cost.Max < info.Cost.Min || info.Cost.Max == 0 {
+				t.Errorf("Cost = %+v, want a non empty range", info.Cost)
+			}
+		})
+	}
+}
@@ -209,6 +209,19 @@ func (a *Inspector) inspectCall(call *exprpb.Expr_Call, currentPath string) Expr
 		inspection.UnknownFunctions = append(inspection.UnknownFunctions, argInspection.UnknownFunctions...)
 	}
 
+	// Namespaced library functions, e.g `cidr.contains(a, b)`, are parsed as
+	// method calls on an identifier. Handle them as plain function calls.
+	if name, ok := a.libraryFunctionName(call); ok {
+		functionCall := FunctionCall{
+			Name: name,
+		}
+		for _, arg := range call.Args {
+			functionCall.Arguments = append(functionCall.Arguments, a.exprToString(arg))
+		}
+		inspection.FunctionCalls = append(inspection.FunctionCalls, functionCall)
+		return inspection
+	}
+
 	// Handle the current function - only if it's not part of a chain
 	if _, isFunction := a.functions[call.Function]; isFunction && call.Target == nil {
 		functionCall := FunctionCall{
@@ -232,14 +245,33 @@ func (a *Inspector) inspectCall(call *exprpb.Expr_Call, currentPath string) Expr
 		inspection.FunctionCalls = append(inspection.FunctionCalls, FunctionCall{
 			Name: fmt.Sprintf("%s.%s", a.exprToString(call.Target), call.Function),
 		})
-	} else if !isInternalFunction(call.Function) {
-		// This is an unknown function, but not an internal one
+	} else if !isInternalFunction(call.Function) && !krocel.IsLibraryFunction(call.Function) && !a.env.HasFunction(call.Function) {
+		// This is an unknown function, but not an internal one, nor one
+		// declared by the environment, e.g behind a feature flag.
 		inspection.UnknownFunctions = append(inspection.UnknownFunctions, UnknownFunction{Name: call.Function})
 	}
 
 	return inspection
 }
 
+// libraryFunctionName returns the qualified name of the call if it's a call to
+// a namespaced kro library function, e.g `cidr.contains`. Calls on identifiers
+// that are declared resources are never considered library function calls.
+func (a *Inspector) libraryFunctionName(call *exprpb.Expr_Call) (string, bool) {
+	if call.Target == nil {
+		return "", false
+	}
+	ident, ok := call.Target.ExprKind.(*exprpb.Expr_IdentExpr)
+	if !ok {
+		return "", false
+	}
+	if _, isResource := a.resources[ident.IdentExpr.Name]; isResource {
+		return "", false
+	}
+	name := ident.IdentExpr.Name + "." + call.Function
+	return name, krocel.IsLibraryFunction(name)
+}
+
 // inspectIdent analyzes identifier expressions in CEL and determines if they are known resources
 // or unknown references. It handles the base identifiers in field access chains and distinguishes
 // between declared resources and unknown/internal identifiers.
@@ -526,6 +558,7 @@ func isInternalFunction(name string) bool {
 		"timestamp": true,
 		"duration":  true,
 		"type":      true,
+		"dyn":       true,
 
 		// Collection Functions
 		"filter":     true,
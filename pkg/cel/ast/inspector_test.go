@@ -433,6 +433,21 @@ func TestInspector_UnknownResourcesAndCalls(t *testing.T) {
 				{Name: "processItems(bucket).validate"},
 			},
 		},
+		{
+			name:       "namespaced library functions",
+			resources:  []string{"schema", "subnet"},
+			expression: `cidr.contains(cidr.subnet(schema.spec.cidr, 24, 1), ip.increment(subnet.status.ip, 1))`,
+			wantResources: []ResourceDependency{
+				{ID: "schema", Path: "schema.spec.cidr"},
+				{ID: "subnet", Path: "subnet.status.ip"},
+			},
+			wantFunctions: []FunctionCall{
+				{Name: "cidr.contains"},
+				{Name: "cidr.subnet"},
+				{Name: "ip.increment"},
+			},
+			wantUnknownRes: nil,
+		},
 		{
 			name:          "test unknown function with target",
 			resources:     []string{},
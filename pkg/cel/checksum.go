@@ -0,0 +1,75 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// Checksum returns a CEL library hashing values, e.g to annotate a
+// Deployment with the hash of its ConfigMap, so that its pods are restarted
+// when the configuration changes.
+//
+//	checksum(dyn) -> string
+//	  e.g checksum(configmap.data) == "5c6e...", the hex encoded sha256
+//
+// Values are hashed in their canonical JSON form, with sorted map keys, so
+// semantically equal values always have the same checksum.
+func Checksum() cel.EnvOption {
+	return cel.Lib(checksumLib{})
+}
+
+type checksumLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (checksumLib) LibraryName() string {
+	return "kro.checksum"
+}
+
+// CompileOptions implements cel.Library.
+func (checksumLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("checksum",
+			cel.Overload("checksum_dyn",
+				[]*cel.Type{cel.DynType}, cel.StringType,
+				cel.UnaryBinding(checksum),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (checksumLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func checksum(value ref.Val) ref.Val {
+	native, err := GoNativeType(value)
+	if err != nil {
+		return invalidArgument("checksum", err)
+	}
+	// encoding/json sorts the map keys, which makes the encoding canonical.
+	data, err := json.Marshal(native)
+	if err != nil {
+		return invalidArgument("checksum", err)
+	}
+	sum := sha256.Sum256(data)
+	return types.String(hex.EncodeToString(sum[:]))
+}
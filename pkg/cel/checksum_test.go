@@ -0,0 +1,77 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"configmap"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	eval := func(expression string, context map[string]interface{}) string {
+		t.Helper()
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			t.Fatalf("Compile(%q) error = %v", expression, issues.Err())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("Program(%q) error = %v", expression, err)
+		}
+		out, _, err := program.Eval(context)
+		if err != nil {
+			t.Fatalf("Eval(%q) error = %v", expression, err)
+		}
+		return out.Value().(string)
+	}
+
+	// The hex encoded sha256 of {"a":1,"b":"x"}, which must not change
+	// across runs nor releases, or every workload would be restarted.
+	const want = "ecf9e98ec0641e23113ff3ce8bdc78d0ddd249886517fd4a7f68cc83d4e65667"
+	for _, expression := range []string{
+		`checksum({"a": 1, "b": "x"})`,
+		`checksum({"b": "x", "a": 1})`,
+	} {
+		if got := eval(expression, nil); got != want {
+			t.Errorf("%s = %s, want %s", expression, got, want)
+		}
+	}
+
+	// Maps built in different orders, as read from the cluster.
+	data := []map[string]interface{}{{}, {}}
+	keys := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}
+	for i, key := range keys {
+		data[0][key] = key
+		data[1][keys[len(keys)-1-i]] = keys[len(keys)-1-i]
+	}
+	first := eval("checksum(configmap.data)", map[string]interface{}{
+		"configmap": map[string]interface{}{"data": data[0]},
+	})
+	for i := 0; i < 10; i++ {
+		got := eval("checksum(configmap.data)", map[string]interface{}{
+			"configmap": map[string]interface{}{"data": data[i%2]},
+		})
+		if got != first {
+			t.Errorf("checksum(configmap.data) = %s, want %s", got, first)
+		}
+	}
+
+	// Different values have different checksums.
+	if eval(`checksum({"a": 1, "b": "y"})`, nil) == want {
+		t.Errorf("checksum of different values must differ")
+	}
+}
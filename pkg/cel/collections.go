@@ -0,0 +1,219 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/parser"
+)
+
+// Collections returns a CEL library providing macros and functions operating
+// on lists.
+//
+//	countWhere(list, x, predicate) -> int
+//	  e.g countWhere(resources, r, r.status.phase == "Running")
+//	list.sum() -> int or double
+//	  e.g [1, 2, 3].sum() == 6, [].sum() == 0
+//	list.avg() -> double
+//	  e.g [1, 2].avg() == 1.5, fails on an empty list
+//	list.min() -> int or double
+//	  e.g [3, 1, 2].min() == 1, fails on an empty list
+//	list.max() -> int or double
+//	  e.g [3, 1, 2].max() == 3, fails on an empty list
+//
+// The aggregates operate on lists of numbers: integers are summed as
+// integers, and as soon as a list contains a double, as doubles.
+func Collections() cel.EnvOption {
+	return cel.Lib(collectionsLib{})
+}
+
+type collectionsLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (collectionsLib) LibraryName() string {
+	return "kro.collections"
+}
+
+// CompileOptions implements cel.Library.
+func (collectionsLib) CompileOptions() []cel.EnvOption {
+	listType := cel.ListType(cel.DynType)
+	return []cel.EnvOption{
+		cel.Macros(
+			cel.GlobalMacro("countWhere", 3, makeCountWhere),
+		),
+		cel.Function("sum",
+			cel.MemberOverload("list_sum", []*cel.Type{listType}, cel.DynType,
+				cel.UnaryBinding(listSum),
+			),
+		),
+		cel.Function("avg",
+			cel.MemberOverload("list_avg", []*cel.Type{listType}, cel.DoubleType,
+				cel.UnaryBinding(listAvg),
+			),
+		),
+		cel.Function("min",
+			cel.MemberOverload("list_min", []*cel.Type{listType}, cel.DynType,
+				cel.UnaryBinding(func(list ref.Val) ref.Val { return listExtremum("list.min", list, -1) }),
+			),
+		),
+		cel.Function("max",
+			cel.MemberOverload("list_max", []*cel.Type{listType}, cel.DynType,
+				cel.UnaryBinding(func(list ref.Val) ref.Val { return listExtremum("list.max", list, 1) }),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (collectionsLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// errEmptyList is returned by the aggregates undefined on empty lists.
+var errEmptyList = errors.New("empty list")
+
+// numbers returns the numbers of the list, as int64 values if they're all
+// integers, or as float64 values otherwise.
+func numbers(function string, list ref.Val) ([]int64, []float64, ref.Val) {
+	lister, ok := list.(traits.Lister)
+	if !ok {
+		return nil, nil, invalidArgument(function, fmt.Errorf("expected a list, got %v", list.Type()))
+	}
+	var ints []int64
+	var doubles []float64
+	isDouble := false
+	for it := lister.Iterator(); it.HasNext() == types.True; {
+		switch v := it.Next().(type) {
+		case types.Int:
+			ints = append(ints, int64(v))
+			doubles = append(doubles, float64(v))
+		case types.Uint:
+			if uint64(v) > math.MaxInt64 {
+				return nil, nil, invalidArgument(function, fmt.Errorf("%d overflows int64", uint64(v)))
+			}
+			ints = append(ints, int64(v))
+			doubles = append(doubles, float64(v))
+		case types.Double:
+			isDouble = true
+			doubles = append(doubles, float64(v))
+		default:
+			return nil, nil, invalidArgument(function, fmt.Errorf("expected a list of numbers, got a %v element", v.Type()))
+		}
+	}
+	if isDouble {
+		return nil, doubles, nil
+	}
+	return ints, nil, nil
+}
+
+func listSum(list ref.Val) ref.Val {
+	ints, doubles, err := numbers("list.sum", list)
+	if err != nil {
+		return err
+	}
+	if doubles != nil {
+		sum := 0.0
+		for _, d := range doubles {
+			sum += d
+		}
+		return types.Double(sum)
+	}
+	sum := int64(0)
+	for _, i := range ints {
+		if (i > 0 && sum > math.MaxInt64-i) || (i < 0 && sum < math.MinInt64-i) {
+			return types.WrapErr(fmt.Errorf("list.sum: integer overflow"))
+		}
+		sum += i
+	}
+	return types.Int(sum)
+}
+
+func listAvg(list ref.Val) ref.Val {
+	ints, doubles, err := numbers("list.avg", list)
+	if err != nil {
+		return err
+	}
+	if doubles == nil {
+		for _, i := range ints {
+			doubles = append(doubles, float64(i))
+		}
+	}
+	if len(doubles) == 0 {
+		return invalidArgument("list.avg", errEmptyList)
+	}
+	sum := 0.0
+	for _, d := range doubles {
+		sum += d
+	}
+	return types.Double(sum / float64(len(doubles)))
+}
+
+// listExtremum returns the minimum (sign -1) or the maximum (sign 1) of the
+// list.
+func listExtremum(function string, list ref.Val, sign int) ref.Val {
+	ints, doubles, err := numbers(function, list)
+	if err != nil {
+		return err
+	}
+	if doubles != nil {
+		extremum := doubles[0]
+		for _, d := range doubles[1:] {
+			if (sign < 0 && d < extremum) || (sign > 0 && d > extremum) {
+				extremum = d
+			}
+		}
+		return types.Double(extremum)
+	}
+	if len(ints) == 0 {
+		return invalidArgument(function, errEmptyList)
+	}
+	extremum := ints[0]
+	for _, i := range ints[1:] {
+		if (sign < 0 && i < extremum) || (sign > 0 && i > extremum) {
+			extremum = i
+		}
+	}
+	return types.Int(extremum)
+}
+
+// makeCountWhere expands countWhere(list, x, predicate) into a comprehension
+// counting the elements of the list matching the predicate, the same way
+// the exists_one macro does.
+func makeCountWhere(eh cel.MacroExprFactory, _ ast.Expr, args []ast.Expr) (ast.Expr, *common.Error) {
+	if args[1].Kind() != ast.IdentKind {
+		return nil, eh.NewError(args[1].ID(), "argument must be a simple name")
+	}
+	iterVar := args[1].AsIdent()
+
+	init := eh.NewLiteral(types.Int(0))
+	condition := eh.NewLiteral(types.True)
+	step := eh.NewCall(operators.Conditional, args[2],
+		eh.NewCall(operators.Add, eh.NewAccuIdent(), eh.NewLiteral(types.Int(1))),
+		eh.NewAccuIdent(),
+	)
+	// Resources are declared with the 'any' type, which can't be iterated
+	// over, unlike 'dyn'.
+	iterRange := eh.NewCall("dyn", args[0])
+	return eh.NewComprehension(iterRange, iterVar, parser.AccumulatorName, init, condition, step, eh.NewAccuIdent()), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountWhere(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"pods"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"pods": []interface{}{
+			map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}},
+			map[string]interface{}{"status": map[string]interface{}{"phase": "Pending"}},
+			map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{
+			name:       "count matching elements",
+			expression: `countWhere(pods, p, p.status.phase == "Running")`,
+			want:       int64(2),
+		},
+		{
+			name:       "no matching element",
+			expression: `countWhere(pods, p, p.status.phase == "Failed")`,
+			want:       int64(0),
+		},
+		{
+			name:       "empty list",
+			expression: `countWhere([], p, true)`,
+			want:       int64(0),
+		},
+		{
+			name:       "invalid variable",
+			expression: `countWhere(pods, p.status, true)`,
+			wantErr:    "argument must be a simple name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if tt.wantErr != "" {
+				if issues == nil || !strings.Contains(issues.Err().Error(), tt.wantErr) {
+					t.Fatalf("Compile() error = %v, want %q", issues.Err(), tt.wantErr)
+				}
+				return
+			}
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if val.Value() != tt.want {
+				t.Errorf("Eval() = %v, want %v", val.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestListAggregates(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"pods"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"pods": []interface{}{
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"replicas": int64(1)},
+			map[string]interface{}{"replicas": int64(2)},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{name: "sum of ints", expression: `[1, 2, 3].sum()`, want: int64(6)},
+		{name: "sum of doubles", expression: `[1.5, 2.5].sum()`, want: 4.0},
+		{name: "sum of mixed numbers", expression: `dyn([1, 2.5]).sum()`, want: 3.5},
+		{name: "sum of empty list", expression: `[].sum()`, want: int64(0)},
+		{name: "sum of resource fields", expression: `dyn(pods).map(p, p.replicas).sum()`, want: int64(6)},
+		{name: "sum overflow", expression: `[9223372036854775807, 1].sum()`, wantErr: "integer overflow"},
+		{name: "sum of strings", expression: `["a"].sum()`, wantErr: "expected a list of numbers"},
+		{name: "avg of ints", expression: `[1, 2].avg()`, want: 1.5},
+		{name: "avg of doubles", expression: `[1.0, 2.0, 6.0].avg()`, want: 3.0},
+		{name: "avg of empty list", expression: `[].avg()`, wantErr: "empty list"},
+		{name: "min of ints", expression: `[3, 1, 2].min()`, want: int64(1)},
+		{name: "min of doubles", expression: `[3.5, -1.5].min()`, want: -1.5},
+		{name: "min of resource fields", expression: `dyn(pods).map(p, p.replicas).min()`, want: int64(1)},
+		{name: "min of empty list", expression: `[].min()`, wantErr: "empty list"},
+		{name: "max of ints", expression: `[3, 1, 2].max()`, want: int64(3)},
+		{name: "max of doubles", expression: `[3.5, -1.5].max()`, want: 3.5},
+		{name: "max of empty list", expression: `[].max()`, wantErr: "empty list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if val.Value() != tt.want {
+				t.Errorf("Eval() = %v (%T), want %v", val.Value(), val.Value(), tt.want)
+			}
+		})
+	}
+}
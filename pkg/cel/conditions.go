@@ -0,0 +1,100 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// Conditions returns a CEL library reading the conditions of Kubernetes
+// objects.
+//
+//	condition(object, type) -> dyn
+//	  e.g condition(deployment, "Available").status == "True"
+//
+// condition returns the condition of the given type from the
+// status.conditions list of the object, or null if the object has no such
+// condition, e.g because its controller didn't report it yet.
+func Conditions() cel.EnvOption {
+	return cel.Lib(conditionsLib{})
+}
+
+type conditionsLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (conditionsLib) LibraryName() string {
+	return "kro.conditions"
+}
+
+// CompileOptions implements cel.Library.
+func (conditionsLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("condition",
+			cel.Overload("condition_dyn_string",
+				[]*cel.Type{cel.DynType, cel.StringType}, cel.DynType,
+				cel.BinaryBinding(findCondition),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (conditionsLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func findCondition(object, conditionType ref.Val) ref.Val {
+	t, ok := conditionType.(types.String)
+	if !ok {
+		return invalidArgument("condition", errors.New("expected a string type"))
+	}
+	if t == "" {
+		return invalidArgument("condition", errors.New("type must not be empty"))
+	}
+
+	status, ok := field(object, "status")
+	if !ok {
+		return types.NullValue
+	}
+	value, ok := field(status, "conditions")
+	if !ok {
+		return types.NullValue
+	}
+	conditions, ok := value.(traits.Lister)
+	if !ok {
+		return types.NullValue
+	}
+	for it := conditions.Iterator(); it.HasNext() == types.True; {
+		condition := it.Next()
+		if value, ok := field(condition, "type"); ok && value.Equal(t) == types.True {
+			return condition
+		}
+	}
+	return types.NullValue
+}
+
+// field returns the value of the field of a map value, false if the value
+// isn't a map or doesn't have the field.
+func field(value ref.Val, name string) (ref.Val, bool) {
+	mapper, ok := value.(traits.Mapper)
+	if !ok {
+		return nil, false
+	}
+	return mapper.Find(types.String(name))
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConditionFunction(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"deployment", "configmap", "pending"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"deployment": map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Progressing", "status": "True"},
+					map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable"},
+				},
+			},
+		},
+		"configmap": map[string]interface{}{
+			"data": map[string]interface{}{"key": "value"},
+		},
+		"pending": map[string]interface{}{
+			"status": map[string]interface{}{},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{
+			name:       "present condition",
+			expression: `condition(deployment, "Available")`,
+			want:       map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable"},
+		},
+		{
+			name:       "present condition status",
+			expression: `condition(deployment, "Progressing").status == "True"`,
+			want:       true,
+		},
+		{name: "absent condition", expression: `condition(deployment, "ReplicaFailure")`, want: nil},
+		{
+			name:       "absent condition check",
+			expression: `condition(deployment, "ReplicaFailure") == null`,
+			want:       true,
+		},
+		{name: "object without status", expression: `condition(configmap, "Ready")`, want: nil},
+		{name: "status without conditions", expression: `condition(pending, "Ready")`, want: nil},
+		{name: "empty type", expression: `condition(deployment, "")`, wantErr: "condition: invalid argument: type must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			got, err := GoNativeType(val)
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoNativeType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
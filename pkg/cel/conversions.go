@@ -20,6 +20,8 @@ import (
 
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 var (
@@ -27,8 +29,15 @@ var (
 	ErrUnsupportedType = errors.New("unsupported type")
 )
 
-// GoNativeType transforms CEL output into corresponding Go types
+// GoNativeType transforms CEL output into corresponding Go types. Lists and
+// maps are converted recursively, so lists/maps built (or concatenated) in an
+// expression, e.g `schema.spec.args + ["--default"]`, only contain Go native
+// values, even when they're nested or hold values of different types. The
+// values returned by explicitNull() are converted to ExplicitNull.
 func GoNativeType(v ref.Val) (interface{}, error) {
+	if _, ok := v.(explicitNullVal); ok {
+		return ExplicitNull, nil
+	}
 	switch v.Type() {
 	case types.BoolType:
 		return v.Value().(bool), nil
@@ -41,17 +50,92 @@ func GoNativeType(v ref.Val) (interface{}, error) {
 	case types.StringType:
 		return v.Value().(string), nil
 	case types.ListType:
-		return v.ConvertToNative(reflect.TypeOf([]interface{}{}))
+		return goNativeList(v)
 	case types.MapType:
-		return v.ConvertToNative(reflect.TypeOf(map[string]interface{}{}))
+		return goNativeMap(v)
 	case types.NullType:
 		return nil, nil
 	default:
+		// Go structs (see WithNativeTypes) are converted to their
+		// unstructured representation.
+		if native, ok := goNativeStruct(v.Value()); ok {
+			return native, nil
+		}
 		// For types we can't convert, return as is with an error
 		return v.Value(), fmt.Errorf("unsupported type: %v", v.Type())
 	}
 }
 
+// goNativeStruct converts a Go struct, or a pointer to a struct, to its
+// unstructured representation.
+func goNativeStruct(value interface{}) (map[string]interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if rv.CanAddr() {
+		value = rv.Addr().Interface()
+	} else {
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		value = ptr.Interface()
+	}
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(value)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// goNativeList converts a CEL list into a []interface{}, converting each of
+// its elements.
+func goNativeList(v ref.Val) (interface{}, error) {
+	lister, ok := v.(traits.Lister)
+	if !ok {
+		return nil, fmt.Errorf("%w: list of type %T", ErrUnsupportedType, v)
+	}
+	size, ok := lister.Size().(types.Int)
+	if !ok {
+		return nil, fmt.Errorf("failed getting the size of list %v", v)
+	}
+	list := make([]interface{}, 0, size)
+	for i := types.Int(0); i < size; i++ {
+		elem, err := GoNativeType(lister.Get(i))
+		if err != nil {
+			return nil, fmt.Errorf("failed converting list element %d: %w", i, err)
+		}
+		list = append(list, elem)
+	}
+	return list, nil
+}
+
+// goNativeMap converts a CEL map into a map[string]interface{}, converting
+// each of its values. Only string keys are supported.
+func goNativeMap(v ref.Val) (interface{}, error) {
+	mapper, ok := v.(traits.Mapper)
+	if !ok {
+		return nil, fmt.Errorf("%w: map of type %T", ErrUnsupportedType, v)
+	}
+	out := make(map[string]interface{})
+	it := mapper.Iterator()
+	for it.HasNext() == types.True {
+		key := it.Next()
+		k, ok := key.Value().(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: map key of type %v", ErrUnsupportedType, key.Type())
+		}
+		value, err := GoNativeType(mapper.Get(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed converting map value %q: %w", k, err)
+		}
+		out[k] = value
+	}
+	return out, nil
+}
+
 // IsBoolType checks if the given ref.Val is of type BoolType
 func IsBoolType(v ref.Val) bool {
 	return v.Type() == types.BoolType
@@ -0,0 +1,128 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGoNativeType(t *testing.T) {
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"extraArgs": []interface{}{"--verbose", "--port=8080"},
+				"env": []interface{}{
+					map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+				},
+				"labels": map[string]interface{}{"app": "myapp"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    error
+	}{
+		{
+			name:       "spec list concatenated with a literal list",
+			expression: `schema.spec.extraArgs + ["--default"]`,
+			want:       []interface{}{"--verbose", "--port=8080", "--default"},
+		},
+		{
+			name:       "spec list concatenated with an empty list",
+			expression: `schema.spec.extraArgs + []`,
+			want:       []interface{}{"--verbose", "--port=8080"},
+		},
+		{
+			name:       "spec list of maps concatenated with literal maps",
+			expression: `schema.spec.env + [{"name": "REGION", "value": "us-west-2"}]`,
+			want: []interface{}{
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+				map[string]interface{}{"name": "REGION", "value": "us-west-2"},
+			},
+		},
+		{
+			name:       "heterogeneous list",
+			expression: `[1, "a", true, 2.5, null]`,
+			want:       []interface{}{int64(1), "a", true, 2.5, nil},
+		},
+		{
+			name:       "nested lists",
+			expression: `[[1, 2], [3]] + [[4]]`,
+			want: []interface{}{
+				[]interface{}{int64(1), int64(2)},
+				[]interface{}{int64(3)},
+				[]interface{}{int64(4)},
+			},
+		},
+		{
+			name:       "nested maps",
+			expression: `{"a": {"b": [1, {"c": "d"}]}}`,
+			want: map[string]interface{}{
+				"a": map[string]interface{}{
+					"b": []interface{}{int64(1), map[string]interface{}{"c": "d"}},
+				},
+			},
+		},
+		{
+			name:       "spec map",
+			expression: `schema.spec.labels`,
+			want:       map[string]interface{}{"app": "myapp"},
+		},
+		{
+			name:       "map with non string keys",
+			expression: `{1: "a"}`,
+			wantErr:    ErrUnsupportedType,
+		},
+	}
+
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+
+			got, err := GoNativeType(val)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GoNativeType() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoNativeType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,8 +14,15 @@
 package cel
 
 import (
+	"fmt"
+	"reflect"
+	"time"
+
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/ext"
+	apiservercel "k8s.io/apiserver/pkg/cel"
+	"k8s.io/apiserver/pkg/cel/openapi"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 // EnvOption is a function that modifies the environment options.
@@ -28,8 +35,28 @@ type envOptions struct {
 	//
 	// TODO(a-hilaly): Add support for custom types.
 	resourceIDs []string
+	// resourceLists will be converted to CEL variable declarations of
+	// type 'list(dyn)'.
+	resourceLists []string
 	// customDeclarations will be added to the CEL environment.
 	customDeclarations []cel.EnvOption
+	// strict makes the resources with a known schema declared with
+	// concrete types, instead of 'any'.
+	strict bool
+	// schemas are the known schemas of the resources, keyed by resource id.
+	schemas map[string]*spec.Schema
+	// container is the CEL container (namespace) used to resolve the
+	// unqualified names of the expressions.
+	container string
+	// nativeTypes are the Go struct types exposed to the expressions.
+	nativeTypes []reflect.Type
+	// featureFlags are the enabled feature flags.
+	featureFlags map[string]bool
+	// requiredFeatures are the feature flags the expressions opted in to.
+	requiredFeatures []string
+	// timeZone is the name of the time zone the time functions format the
+	// timestamps in, UTC if empty.
+	timeZone string
 }
 
 // WithResourceIDs adds resource ids that will be declared as CEL variables.
@@ -39,6 +66,16 @@ func WithResourceIDs(ids []string) EnvOption {
 	}
 }
 
+// WithResourceLists adds names that will be declared as CEL list variables,
+// e.g the list of all the resources. Unlike the 'any' variables declared with
+// WithResourceIDs, they can be the range of the comprehension macros, e.g
+// `resources.filter(r, r.kind == "Pod")`.
+func WithResourceLists(names []string) EnvOption {
+	return func(opts *envOptions) {
+		opts.resourceLists = append(opts.resourceLists, names...)
+	}
+}
+
 // WithCustomDeclarations adds custom declarations to the CEL environment.
 func WithCustomDeclarations(declarations []cel.EnvOption) EnvOption {
 	return func(opts *envOptions) {
@@ -46,6 +83,72 @@ func WithCustomDeclarations(declarations []cel.EnvOption) EnvOption {
 	}
 }
 
+// WithStrict sets the unknown field policy of the environment. In strict
+// mode, the resources with a schema (see WithResourceSchema) are declared with
+// concrete types, so that CEL rejects the references to unknown fields at
+// compile time. Otherwise, every resource is dynamically typed and unknown
+// fields are only detected when evaluating the expressions.
+func WithStrict(strict bool) EnvOption {
+	return func(opts *envOptions) {
+		opts.strict = strict
+	}
+}
+
+// WithResourceSchema sets the schema of a resource declared with
+// WithResourceIDs. The schema is only used in strict mode.
+func WithResourceSchema(id string, schema *spec.Schema) EnvOption {
+	return func(opts *envOptions) {
+		if opts.schemas == nil {
+			opts.schemas = make(map[string]*spec.Schema)
+		}
+		opts.schemas[id] = schema
+	}
+}
+
+// WithContainer sets the CEL container (a.k.a namespace) of the environment.
+// Unqualified names are resolved in the container first, then as is. e.g in
+// the "acme" container, "settings.replicas" refers to the "acme.settings"
+// variable if declared, and to the "settings" variable otherwise.
+func WithContainer(container string) EnvOption {
+	return func(opts *envOptions) {
+		opts.container = container
+	}
+}
+
+// WithNativeTypes exposes Go struct types to the expressions, so that
+// variables holding typed objects (e.g *appsv1.Deployment) can be evaluated.
+// Fields are named after their json tags, like in the unstructured objects.
+func WithNativeTypes(types ...reflect.Type) EnvOption {
+	return func(opts *envOptions) {
+		opts.nativeTypes = append(opts.nativeTypes, types...)
+	}
+}
+
+// WithFeatureFlags sets the enabled feature flags, keyed by flag name.
+func WithFeatureFlags(flags map[string]bool) EnvOption {
+	return func(opts *envOptions) {
+		opts.featureFlags = flags
+	}
+}
+
+// WithRequiredFeatures opts the environment in to the declarations gated
+// behind the given feature flags (see RegisterFeature). Creating the
+// environment fails if one of them isn't enabled with WithFeatureFlags.
+func WithRequiredFeatures(flags ...string) EnvOption {
+	return func(opts *envOptions) {
+		opts.requiredFeatures = append(opts.requiredFeatures, flags...)
+	}
+}
+
+// WithTimeZone sets the time zone the time functions format the timestamps
+// in, as an IANA time zone name, e.g "Europe/Paris". Defaults to UTC. Creating
+// the environment fails if the time zone is unknown.
+func WithTimeZone(name string) EnvOption {
+	return func(opts *envOptions) {
+		opts.timeZone = name
+	}
+}
+
 // DefaultEnvironment returns the default CEL environment.
 func DefaultEnvironment(options ...EnvOption) (*cel.Env, error) {
 	opts := &envOptions{}
@@ -53,14 +156,87 @@ func DefaultEnvironment(options ...EnvOption) (*cel.Env, error) {
 		opt(opts)
 	}
 
+	location := time.UTC
+	if opts.timeZone != "" {
+		var err error
+		location, err = time.LoadLocation(opts.timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", opts.timeZone, err)
+		}
+	}
+
 	declarations := []cel.EnvOption{
 		// default stdlibs
 		ext.Lists(),
 		ext.Strings(),
+		// kro libraries
+		Network(),
+		Collections(),
+		YAML(),
+		Checksum(),
+		Semver(),
+		Time(location),
+		References(),
+		Conditions(),
+		Format(),
+		EnvVars(),
+		Nulls(),
+	}
+	gated, err := FeatureOptions(opts.featureFlags, opts.requiredFeatures)
+	if err != nil {
+		return nil, err
+	}
+	declarations = append(declarations, gated...)
+	if len(opts.nativeTypes) > 0 {
+		args := make([]any, 0, len(opts.nativeTypes)+1)
+		for _, t := range opts.nativeTypes {
+			args = append(args, t)
+		}
+		args = append(args, ext.ParseStructTag("json"))
+		declarations = append(declarations, ext.NativeTypes(args...))
+	}
+	if opts.container != "" {
+		declarations = append(declarations, cel.Container(opts.container))
+	}
+
+	for _, name := range opts.resourceLists {
+		declarations = append(declarations, cel.Variable(name, cel.ListType(cel.DynType)))
 	}
 
+	var typed []*apiservercel.DeclType
 	for _, name := range opts.resourceIDs {
-		declarations = append(declarations, cel.Variable(name, cel.AnyType))
+		schema, ok := opts.schemas[name]
+		if !opts.strict || !ok {
+			declarations = append(declarations, cel.Variable(name, cel.AnyType))
+			continue
+		}
+		declType := openapi.SchemaDeclType(schema, false)
+		if declType == nil {
+			return nil, fmt.Errorf("failed to convert the schema of %s to a CEL type", name)
+		}
+		if declType.IsObject() {
+			declType = declType.MaybeAssignTypeName(typeNamePrefix + name)
+		}
+		typed = append(typed, declType)
+		declarations = append(declarations, cel.Variable(name, declType.CelType()))
 	}
-	return cel.NewEnv(declarations...)
+	if len(typed) == 0 {
+		return cel.NewEnv(declarations...)
+	}
+
+	// The typed declarations need a type provider resolving the object
+	// types, built on top of the base environment one.
+	base, err := cel.NewEnv()
+	if err != nil {
+		return nil, err
+	}
+	providerOpts, err := apiservercel.NewDeclTypeProvider(typed...).EnvOptions(base.CELTypeProvider())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL type provider: %w", err)
+	}
+	return cel.NewEnv(append(providerOpts, declarations...)...)
 }
+
+// typeNamePrefix prefixes the names of the object types of the resources
+// declared in strict mode.
+const typeNamePrefix = "kro.resource."
@@ -0,0 +1,132 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestDefaultEnvironment_Strict(t *testing.T) {
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: []string{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: []string{"object"},
+						Properties: map[string]spec.Schema{
+							"name":     *spec.StringProperty(),
+							"replicas": *spec.Int64Property(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		strict     bool
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "strict accepts known fields",
+			strict:     true,
+			expression: `schema.spec.name + "-" + string(schema.spec.replicas)`,
+		},
+		{
+			name:       "strict rejects unknown fields",
+			strict:     true,
+			expression: `schema.spec.nmae`,
+			wantErr:    "undefined field 'nmae'",
+		},
+		{
+			name:       "strict rejects mistyped fields",
+			strict:     true,
+			expression: `schema.spec.replicas + "1"`,
+			wantErr:    "no matching overload",
+		},
+		{
+			name:       "strict keeps untyped resources dynamic",
+			strict:     true,
+			expression: `deployment.status.anything`,
+		},
+		{
+			name:       "lenient accepts unknown fields",
+			expression: `schema.spec.nmae`,
+		},
+		{
+			name:       "undeclared resources are always rejected",
+			strict:     true,
+			expression: `service.spec`,
+			wantErr:    "undeclared reference to 'service'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := DefaultEnvironment(
+				WithResourceIDs([]string{"schema", "deployment"}),
+				WithResourceSchema("schema", schema),
+				WithStrict(tt.strict),
+			)
+			if err != nil {
+				t.Fatalf("DefaultEnvironment() error = %v", err)
+			}
+			_, iss := env.Compile(tt.expression)
+			if tt.wantErr == "" {
+				if iss.Err() != nil {
+					t.Fatalf("Compile(%q) error = %v", tt.expression, iss.Err())
+				}
+				return
+			}
+			if iss.Err() == nil || !strings.Contains(iss.Err().Error(), tt.wantErr) {
+				t.Fatalf("Compile(%q) error = %v, want %q", tt.expression, iss.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultEnvironment_Container(t *testing.T) {
+	env, err := DefaultEnvironment(
+		WithResourceIDs([]string{"acme.settings", "schema"}),
+		WithContainer("acme"),
+	)
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+
+	ast, iss := env.Compile(`settings.replicas + schema.spec.replicas`)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() error = %v", iss.Err())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("Program() error = %v", err)
+	}
+	out, _, err := program.Eval(map[string]interface{}{
+		"acme.settings": map[string]interface{}{"replicas": int64(1)},
+		"schema":        map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(2)}},
+	})
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	if out.Value() != int64(3) {
+		t.Errorf("Eval() = %v, want 3", out.Value())
+	}
+}
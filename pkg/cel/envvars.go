@@ -0,0 +1,97 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+// EnvVars returns a CEL library building the environment variables of
+// containers.
+//
+//	toEnvVars(map) -> list(map(string, string))
+//	  e.g toEnvVars({"B": "2", "A": "1"}) == [{"name": "A", "value": "1"}, {"name": "B", "value": "2"}]
+//
+// The environment variables are sorted by name, so that the rendered
+// containers don't change with the iteration order of the map. Scalar values
+// (numbers and booleans) are converted to strings, other values are rejected.
+func EnvVars() cel.EnvOption {
+	return cel.Lib(envVarsLib{})
+}
+
+type envVarsLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (envVarsLib) LibraryName() string {
+	return "kro.envvars"
+}
+
+// CompileOptions implements cel.Library.
+func (envVarsLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("toEnvVars",
+			cel.Overload("toEnvVars_map",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.DynType)},
+				cel.ListType(cel.MapType(cel.StringType, cel.StringType)),
+				cel.UnaryBinding(toEnvVars),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (envVarsLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func toEnvVars(value ref.Val) ref.Val {
+	m, ok := value.(traits.Mapper)
+	if !ok {
+		return invalidArgument("toEnvVars", errors.New("expected a map"))
+	}
+
+	values := make(map[string]string)
+	for it := m.Iterator(); it.HasNext() == types.True; {
+		key := it.Next()
+		name, ok := key.(types.String)
+		if !ok {
+			return invalidArgument("toEnvVars", fmt.Errorf("expected string keys, got %s", key.Type().TypeName()))
+		}
+		v := m.Get(key)
+		switch v.(type) {
+		case types.String, types.Int, types.Uint, types.Double, types.Bool:
+			values[string(name)] = fmt.Sprintf("%v", v.Value())
+		default:
+			return invalidArgument("toEnvVars", fmt.Errorf("value of %s: expected a scalar, got %s", name, v.Type().TypeName()))
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	envVars := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		envVars = append(envVars, map[string]string{"name": name, "value": values[name]})
+	}
+	return types.DefaultTypeAdapter.NativeToValue(envVars)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestToEnvVarsFunction(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"config": map[string]interface{}{
+					"LOG_LEVEL": "debug",
+					"DB_HOST":   "postgres",
+					"PORT":      int64(8080),
+					"DEBUG":     true,
+				},
+				"empty": map[string]interface{}{},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{name: "empty map", expression: `toEnvVars(schema.spec.empty)`, want: []interface{}{}},
+		{
+			name:       "single entry",
+			expression: `toEnvVars({"NAME": "web"})`,
+			want: []interface{}{
+				map[string]interface{}{"name": "NAME", "value": "web"},
+			},
+		},
+		{
+			name:       "sorted by name",
+			expression: `toEnvVars(schema.spec.config)`,
+			want: []interface{}{
+				map[string]interface{}{"name": "DB_HOST", "value": "postgres"},
+				map[string]interface{}{"name": "DEBUG", "value": "true"},
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+				map[string]interface{}{"name": "PORT", "value": "8080"},
+			},
+		},
+		{
+			name:       "field access",
+			expression: `toEnvVars(schema.spec.config)[0].name`,
+			want:       "DB_HOST",
+		},
+		{
+			name:       "non scalar value",
+			expression: `toEnvVars({"LIST": [1, 2]})`,
+			wantErr:    "toEnvVars: invalid argument: value of LIST: expected a scalar, got list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			got, err := GoNativeType(val)
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoNativeType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
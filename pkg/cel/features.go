@@ -0,0 +1,62 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ErrFeatureDisabled is returned when an expression requires a feature flag
+// that isn't enabled.
+var ErrFeatureDisabled = errors.New("feature flag is disabled")
+
+var (
+	featuresMu sync.RWMutex
+	// features holds the declarations gated behind each feature flag.
+	features = map[string][]cel.EnvOption{}
+)
+
+// RegisterFeature registers CEL declarations (functions, libraries...) gated
+// behind the given feature flag. They are only available to the expressions
+// that require the flag, and only when the flag is enabled.
+func RegisterFeature(flag string, declarations ...cel.EnvOption) {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	features[flag] = append(features[flag], declarations...)
+}
+
+// FeatureOptions returns the declarations gated behind the required feature
+// flags. An error wrapping ErrFeatureDisabled is returned if one of them is
+// not enabled.
+func FeatureOptions(enabled map[string]bool, required []string) ([]cel.EnvOption, error) {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+
+	var declarations []cel.EnvOption
+	for _, flag := range required {
+		gated, ok := features[flag]
+		if !ok {
+			return nil, fmt.Errorf("unknown feature flag %q", flag)
+		}
+		if !enabled[flag] {
+			return nil, fmt.Errorf("%w: expression requires feature flag %q, which is disabled", ErrFeatureDisabled, flag)
+		}
+		declarations = append(declarations, gated...)
+	}
+	return declarations, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func init() {
+	RegisterFeature("test-twice", cel.Function("twice",
+		cel.Overload("twice_int", []*cel.Type{cel.IntType}, cel.IntType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val {
+				return v.(types.Int) * 2
+			}),
+		),
+	))
+}
+
+func TestDefaultEnvironment_FeatureFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    []EnvOption
+		wantEnvErr error
+		// wantCompileErr is expected to be contained in the compilation
+		// error, if any.
+		wantCompileErr string
+	}{
+		{
+			name: "flag enabled and required",
+			options: []EnvOption{
+				WithFeatureFlags(map[string]bool{"test-twice": true}),
+				WithRequiredFeatures("test-twice"),
+			},
+		},
+		{
+			name: "flag disabled and required",
+			options: []EnvOption{
+				WithFeatureFlags(map[string]bool{"test-twice": false}),
+				WithRequiredFeatures("test-twice"),
+			},
+			wantEnvErr: ErrFeatureDisabled,
+		},
+		{
+			name:       "flag required but never set",
+			options:    []EnvOption{WithRequiredFeatures("test-twice")},
+			wantEnvErr: ErrFeatureDisabled,
+		},
+		{
+			name: "flag enabled but not required",
+			options: []EnvOption{
+				WithFeatureFlags(map[string]bool{"test-twice": true}),
+			},
+			wantCompileErr: "undeclared reference to 'twice'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := DefaultEnvironment(tt.options...)
+			if tt.wantEnvErr != nil {
+				if !errors.Is(err, tt.wantEnvErr) {
+					t.Fatalf("DefaultEnvironment() error = %v, want %v", err, tt.wantEnvErr)
+				}
+				if !strings.Contains(err.Error(), `"test-twice"`) {
+					t.Errorf("DefaultEnvironment() error = %v, want it to name the flag", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DefaultEnvironment() error = %v", err)
+			}
+
+			ast, iss := env.Compile(`twice(21)`)
+			if tt.wantCompileErr != "" {
+				if iss.Err() == nil || !strings.Contains(iss.Err().Error(), tt.wantCompileErr) {
+					t.Fatalf("Compile() error = %v, want %q", iss.Err(), tt.wantCompileErr)
+				}
+				return
+			}
+			if iss.Err() != nil {
+				t.Fatalf("Compile() error = %v", iss.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			out, _, err := program.Eval(map[string]interface{}{})
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if out.Value() != int64(42) {
+				t.Errorf("Eval() = %v, want 42", out.Value())
+			}
+		})
+	}
+}
+
+func TestFeatureOptions_UnknownFlag(t *testing.T) {
+	_, err := FeatureOptions(map[string]bool{"unknown": true}, []string{"unknown"})
+	if err == nil || !strings.Contains(err.Error(), `unknown feature flag "unknown"`) {
+		t.Errorf("FeatureOptions() error = %v, want unknown feature flag error", err)
+	}
+}
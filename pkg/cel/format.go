@@ -0,0 +1,151 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// maxFormatArgs is the maximum number of arguments of sprintf. CEL functions
+// aren't variadic, sprintf has an overload per argument count.
+const maxFormatArgs = 8
+
+// Format returns a CEL library formatting strings with positional arguments.
+//
+//	sprintf(format, args...) -> string
+//	  e.g sprintf("%s-%d", schema.spec.name, schema.spec.index) == "web-2"
+//
+// The supported verbs are %s (strings), %d (integers), %t (booleans), %v
+// (any value) and %% (a literal percent sign). The arguments must match the
+// verbs, in count and type, up to 8 arguments.
+func Format() cel.EnvOption {
+	return cel.Lib(formatLib{})
+}
+
+type formatLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (formatLib) LibraryName() string {
+	return "kro.format"
+}
+
+// CompileOptions implements cel.Library.
+func (formatLib) CompileOptions() []cel.EnvOption {
+	overloads := make([]cel.FunctionOpt, 0, maxFormatArgs+1)
+	for count := 0; count <= maxFormatArgs; count++ {
+		args := []*cel.Type{cel.StringType}
+		for i := 0; i < count; i++ {
+			args = append(args, cel.DynType)
+		}
+		overloads = append(overloads, cel.Overload(fmt.Sprintf("sprintf_string_%d_dyn", count),
+			args, cel.StringType,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				return sprintf(args[0], args[1:])
+			}),
+		))
+	}
+	return []cel.EnvOption{
+		cel.Function("sprintf", overloads...),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (formatLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func sprintf(format ref.Val, args []ref.Val) ref.Val {
+	f, ok := format.(types.String)
+	if !ok {
+		return invalidArgument("sprintf", errors.New("expected a string format"))
+	}
+
+	var b strings.Builder
+	next := 0
+	runes := []rune(string(f))
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			b.WriteRune(runes[i])
+			continue
+		}
+		i++
+		if i == len(runes) {
+			return invalidArgument("sprintf", errors.New("format ends with a lone %"))
+		}
+		verb := runes[i]
+		if verb == '%' {
+			b.WriteRune('%')
+			continue
+		}
+		if !strings.ContainsRune("sdtv", verb) {
+			return invalidArgument("sprintf", fmt.Errorf("unsupported verb %%%c", verb))
+		}
+		if next == len(args) {
+			return invalidArgument("sprintf", fmt.Errorf("missing argument for %%%c, got %d arguments", verb, len(args)))
+		}
+		formatted, err := formatArg(verb, args[next])
+		if err != nil {
+			return invalidArgument("sprintf", fmt.Errorf("argument %d: %w", next+1, err))
+		}
+		b.WriteString(formatted)
+		next++
+	}
+	if next != len(args) {
+		return invalidArgument("sprintf", fmt.Errorf("%d arguments given, the format uses %d", len(args), next))
+	}
+	return types.String(b.String())
+}
+
+// formatArg formats the argument for the verb, checking its type.
+func formatArg(verb rune, arg ref.Val) (string, error) {
+	switch verb {
+	case 's':
+		if s, ok := arg.(types.String); ok {
+			return string(s), nil
+		}
+	case 'd':
+		switch n := arg.(type) {
+		case types.Int:
+			return fmt.Sprintf("%d", int64(n)), nil
+		case types.Uint:
+			return fmt.Sprintf("%d", uint64(n)), nil
+		}
+	case 't':
+		if b, ok := arg.(types.Bool); ok {
+			return fmt.Sprintf("%t", bool(b)), nil
+		}
+	case 'v':
+		value, err := GoNativeType(arg)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%v", value), nil
+	default:
+		return "", fmt.Errorf("unsupported verb %%%c", verb)
+	}
+	return "", fmt.Errorf("%%%c expects %s, got %s", verb, verbTypes[verb], arg.Type().TypeName())
+}
+
+// verbTypes describes the argument types expected by the typed verbs.
+var verbTypes = map[rune]string{
+	's': "a string",
+	'd': "an int or uint",
+	't': "a bool",
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSprintfFunction(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name":    "web",
+				"index":   int64(2),
+				"enabled": true,
+				"ports":   []interface{}{int64(80), int64(443)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+		wantErr    string
+	}{
+		{name: "string", expression: `sprintf("name: %s", schema.spec.name)`, want: "name: web"},
+		{name: "int", expression: `sprintf("index: %d", schema.spec.index)`, want: "index: 2"},
+		{name: "uint", expression: `sprintf("%d", 3u)`, want: "3"},
+		{name: "bool", expression: `sprintf("enabled: %t", schema.spec.enabled)`, want: "enabled: true"},
+		{name: "value", expression: `sprintf("ports: %v", schema.spec.ports)`, want: "ports: [80 443]"},
+		{name: "literal percent", expression: `sprintf("100%%")`, want: "100%"},
+		{
+			name:       "positional arguments",
+			expression: `sprintf("%s-%d", schema.spec.name, schema.spec.index)`,
+			want:       "web-2",
+		},
+		{
+			name:       "type mismatch",
+			expression: `sprintf("%d", schema.spec.name)`,
+			wantErr:    "sprintf: invalid argument: argument 1: %d expects an int or uint, got string",
+		},
+		{
+			name:       "missing argument",
+			expression: `sprintf("%s-%d", schema.spec.name)`,
+			wantErr:    "sprintf: invalid argument: missing argument for %d, got 1 arguments",
+		},
+		{
+			name:       "extra argument",
+			expression: `sprintf("%s", schema.spec.name, schema.spec.index)`,
+			wantErr:    "sprintf: invalid argument: 2 arguments given, the format uses 1",
+		},
+		{name: "unsupported verb", expression: `sprintf("%q", "a")`, wantErr: "sprintf: invalid argument: unsupported verb %q"},
+		{name: "lone percent", expression: `sprintf("100%")`, wantErr: "sprintf: invalid argument: format ends with a lone %"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got := val.Value(); got != tt.want {
+				t.Errorf("Eval() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
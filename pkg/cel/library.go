@@ -0,0 +1,67 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// libraryFunctions lists the functions provided by the kro CEL libraries.
+// The parser sees a call like `cidr.contains(a, b)` as a call to `contains`
+// on a `cidr` identifier, this list helps telling them apart from method
+// calls on resources.
+var libraryFunctions = []string{
+	"avg",
+	"checksum",
+	"condition",
+	"explicitNull",
+	"cidr.contains",
+	"cidr.subnet",
+	"ip.increment",
+	"max",
+	"min",
+	"ref",
+	"semver.compare",
+	"semver.gte",
+	"semver.lt",
+	"semver.major",
+	"semver.minor",
+	"semver.patch",
+	"sprintf",
+	"sum",
+	"time.format",
+	"toEnvVars",
+	"yaml.decode",
+	"yaml.encode",
+}
+
+// ErrInvalidArgument is returned when a kro library function is called with
+// invalid arguments, e.g a malformed CIDR.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// invalidArgument returns a CEL error wrapping ErrInvalidArgument.
+func invalidArgument(function string, err error) ref.Val {
+	return types.WrapErr(fmt.Errorf("%s: %w: %w", function, ErrInvalidArgument, err))
+}
+
+// IsLibraryFunction returns true if the given name is a function provided by
+// the kro CEL libraries. e.g "cidr.contains" or "checksum"
+func IsLibraryFunction(name string) bool {
+	return slices.Contains(libraryFunctions, name)
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+func TestIsLibraryFunction(t *testing.T) {
+	base, err := cel.NewEnv(ext.Lists(), ext.Strings())
+	if err != nil {
+		t.Fatalf("NewEnv() error = %v", err)
+	}
+	env, err := DefaultEnvironment()
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+
+	// Every function declared by the kro libraries must be registered.
+	for name := range env.Functions() {
+		if base.HasFunction(name) {
+			continue
+		}
+		if !IsLibraryFunction(name) {
+			t.Errorf("IsLibraryFunction(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"size", "contains", "cidr", "unknown"} {
+		if IsLibraryFunction(name) {
+			t.Errorf("IsLibraryFunction(%q) = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// Network returns a CEL library providing IP and CIDR manipulation functions.
+// Both IPv4 and IPv6 are supported, results are returned as strings.
+//
+//	cidr.contains(cidr, ip) -> bool
+//	  e.g cidr.contains("10.0.0.0/16", "10.0.3.4") == true
+//	cidr.subnet(cidr, newPrefix, index) -> string
+//	  e.g cidr.subnet("10.0.0.0/16", 24, 3) == "10.0.3.0/24"
+//	ip.increment(ip, n) -> string
+//	  e.g ip.increment("10.0.0.1", 2) == "10.0.0.3"
+func Network() cel.EnvOption {
+	return cel.Lib(networkLib{})
+}
+
+type networkLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (networkLib) LibraryName() string {
+	return "kro.network"
+}
+
+// CompileOptions implements cel.Library.
+func (networkLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("cidr.contains",
+			cel.Overload("cidr_contains_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(cidrContains),
+			),
+		),
+		cel.Function("cidr.subnet",
+			cel.Overload("cidr_subnet_string_int_int",
+				[]*cel.Type{cel.StringType, cel.IntType, cel.IntType}, cel.StringType,
+				cel.FunctionBinding(cidrSubnet),
+			),
+		),
+		cel.Function("ip.increment",
+			cel.Overload("ip_increment_string_int",
+				[]*cel.Type{cel.StringType, cel.IntType}, cel.StringType,
+				cel.BinaryBinding(ipIncrement),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (networkLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func cidrContains(cidr, ip ref.Val) ref.Val {
+	prefix, err := parsePrefix(cidr)
+	if err != nil {
+		return invalidArgument("cidr.contains", err)
+	}
+	addr, err := parseAddr(ip)
+	if err != nil {
+		return invalidArgument("cidr.contains", err)
+	}
+	return types.Bool(prefix.Contains(addr))
+}
+
+func cidrSubnet(args ...ref.Val) ref.Val {
+	prefix, err := parsePrefix(args[0])
+	if err != nil {
+		return invalidArgument("cidr.subnet", err)
+	}
+	newPrefix, ok := args[1].(types.Int)
+	if !ok {
+		return invalidArgument("cidr.subnet", fmt.Errorf("new prefix must be an int, got %v", args[1].Type()))
+	}
+	index, ok := args[2].(types.Int)
+	if !ok {
+		return invalidArgument("cidr.subnet", fmt.Errorf("index must be an int, got %v", args[2].Type()))
+	}
+
+	bits := prefix.Addr().BitLen()
+	if int(newPrefix) < prefix.Bits() || int(newPrefix) > bits {
+		return invalidArgument("cidr.subnet", fmt.Errorf("new prefix %d must be between %d and %d", newPrefix, prefix.Bits(), bits))
+	}
+	subnets := new(big.Int).Lsh(big.NewInt(1), uint(int(newPrefix)-prefix.Bits()))
+	if index < 0 || big.NewInt(int64(index)).Cmp(subnets) >= 0 {
+		return invalidArgument("cidr.subnet", fmt.Errorf("index %d out of range, %s has %s /%d subnets", index, prefix, subnets, newPrefix))
+	}
+
+	offset := new(big.Int).Lsh(big.NewInt(int64(index)), uint(bits-int(newPrefix)))
+	addr, err := addToAddr(prefix.Addr(), offset)
+	if err != nil {
+		return invalidArgument("cidr.subnet", err)
+	}
+	return types.String(netip.PrefixFrom(addr, int(newPrefix)).String())
+}
+
+func ipIncrement(ip, n ref.Val) ref.Val {
+	addr, err := parseAddr(ip)
+	if err != nil {
+		return invalidArgument("ip.increment", err)
+	}
+	count, ok := n.(types.Int)
+	if !ok {
+		return invalidArgument("ip.increment", fmt.Errorf("increment must be an int, got %v", n.Type()))
+	}
+	addr, err = addToAddr(addr, big.NewInt(int64(count)))
+	if err != nil {
+		return invalidArgument("ip.increment", err)
+	}
+	return types.String(addr.String())
+}
+
+// parsePrefix parses a CEL string into a masked network prefix.
+func parsePrefix(v ref.Val) (netip.Prefix, error) {
+	s, ok := v.(types.String)
+	if !ok {
+		return netip.Prefix{}, fmt.Errorf("CIDR must be a string, got %v", v.Type())
+	}
+	prefix, err := netip.ParsePrefix(string(s))
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", string(s))
+	}
+	return prefix.Masked(), nil
+}
+
+// parseAddr parses a CEL string into an IP address.
+func parseAddr(v ref.Val) (netip.Addr, error) {
+	s, ok := v.(types.String)
+	if !ok {
+		return netip.Addr{}, fmt.Errorf("IP must be a string, got %v", v.Type())
+	}
+	addr, err := netip.ParseAddr(string(s))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid IP %q", string(s))
+	}
+	return addr, nil
+}
+
+// addToAddr adds n (which can be negative) to the given address. It returns
+// an error if the result overflows the address family.
+func addToAddr(addr netip.Addr, n *big.Int) (netip.Addr, error) {
+	sum := new(big.Int).SetBytes(addr.AsSlice())
+	sum.Add(sum, n)
+
+	size := addr.BitLen() / 8
+	if sum.Sign() < 0 || sum.BitLen() > addr.BitLen() {
+		return netip.Addr{}, fmt.Errorf("adding %s to %s overflows the address space", n, addr)
+	}
+	bytes := sum.FillBytes(make([]byte, size))
+	result, _ := netip.AddrFromSlice(bytes)
+	return result, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNetworkFunctions(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{
+			name:       "cidr contains ip",
+			expression: `cidr.contains("10.0.0.0/16", "10.0.3.4")`,
+			want:       true,
+		},
+		{
+			name:       "cidr doesn't contain ip",
+			expression: `cidr.contains("10.0.0.0/16", "10.1.0.1")`,
+			want:       false,
+		},
+		{
+			name:       "cidr contains ip with unmasked cidr",
+			expression: `cidr.contains("10.0.12.7/16", "10.0.0.1")`,
+			want:       true,
+		},
+		{
+			name:       "ipv6 cidr contains ip",
+			expression: `cidr.contains("2001:db8::/32", "2001:db8::1")`,
+			want:       true,
+		},
+		{
+			name:       "ipv4 cidr doesn't contain ipv6",
+			expression: `cidr.contains("10.0.0.0/8", "2001:db8::1")`,
+			want:       false,
+		},
+		{
+			name:       "cidr contains with invalid cidr",
+			expression: `cidr.contains("10.0.0.0/33", "10.0.0.1")`,
+			wantErr:    `cidr.contains: invalid argument: invalid CIDR "10.0.0.0/33"`,
+		},
+		{
+			name:       "cidr contains with invalid ip",
+			expression: `cidr.contains("10.0.0.0/16", "10.0.0")`,
+			wantErr:    `cidr.contains: invalid argument: invalid IP "10.0.0"`,
+		},
+		{
+			name:       "first subnet",
+			expression: `cidr.subnet("10.0.0.0/16", 24, 0)`,
+			want:       "10.0.0.0/24",
+		},
+		{
+			name:       "nth subnet",
+			expression: `cidr.subnet("10.0.0.0/16", 24, 3)`,
+			want:       "10.0.3.0/24",
+		},
+		{
+			name:       "last subnet",
+			expression: `cidr.subnet("10.0.0.0/16", 20, 15)`,
+			want:       "10.0.240.0/20",
+		},
+		{
+			name:       "same prefix",
+			expression: `cidr.subnet("10.0.0.0/16", 16, 0)`,
+			want:       "10.0.0.0/16",
+		},
+		{
+			name:       "ipv6 subnet",
+			expression: `cidr.subnet("2001:db8::/32", 48, 5)`,
+			want:       "2001:db8:5::/48",
+		},
+		{
+			name:       "subnet index out of range",
+			expression: `cidr.subnet("10.0.0.0/16", 24, 256)`,
+			wantErr:    "cidr.subnet: invalid argument: index 256 out of range, 10.0.0.0/16 has 256 /24 subnets",
+		},
+		{
+			name:       "subnet prefix smaller than the cidr prefix",
+			expression: `cidr.subnet("10.0.0.0/16", 8, 0)`,
+			wantErr:    "cidr.subnet: invalid argument: new prefix 8 must be between 16 and 32",
+		},
+		{
+			name:       "subnet with invalid cidr",
+			expression: `cidr.subnet("not-a-cidr", 24, 0)`,
+			wantErr:    `cidr.subnet: invalid argument: invalid CIDR "not-a-cidr"`,
+		},
+		{
+			name:       "increment ip",
+			expression: `ip.increment("10.0.0.1", 2)`,
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "increment ip across octets",
+			expression: `ip.increment("10.0.0.255", 1)`,
+			want:       "10.0.1.0",
+		},
+		{
+			name:       "decrement ip",
+			expression: `ip.increment("10.0.1.0", -1)`,
+			want:       "10.0.0.255",
+		},
+		{
+			name:       "increment ipv6",
+			expression: `ip.increment("2001:db8::ffff", 1)`,
+			want:       "2001:db8::1:0",
+		},
+		{
+			name:       "increment overflow",
+			expression: `ip.increment("255.255.255.255", 1)`,
+			wantErr:    "ip.increment: invalid argument: adding 1 to 255.255.255.255 overflows the address space",
+		},
+		{
+			name:       "increment invalid ip",
+			expression: `ip.increment("10.0.0.256", 1)`,
+			wantErr:    `ip.increment: invalid argument: invalid IP "10.0.0.256"`,
+		},
+		{
+			name:       "functions with spec values",
+			expression: `cidr.subnet(schema.spec.vpcCidr, 24, schema.spec.subnetIndex)`,
+			want:       "192.168.2.0/24",
+		},
+	}
+
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"vpcCidr":     "192.168.0.0/16",
+				"subnetIndex": int64(2),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if val.Value() != tt.want {
+				t.Errorf("Eval() = %v, want %v", val.Value(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ExplicitNullValue is the type of ExplicitNull.
+type ExplicitNullValue struct{}
+
+// String implements fmt.Stringer.
+func (ExplicitNullValue) String() string {
+	return "null"
+}
+
+// ExplicitNull is the Go value of the explicitNull() CEL function, see
+// GoNativeType. Resolvers write it as a JSON null, unlike null values which
+// may be omitted (e.g in strict null handling mode).
+var ExplicitNull = ExplicitNullValue{}
+
+// IsExplicitNull returns true if the value is ExplicitNull.
+func IsExplicitNull(value interface{}) bool {
+	_, ok := value.(ExplicitNullValue)
+	return ok
+}
+
+// Nulls returns a CEL library telling explicit nulls apart from absent
+// values.
+//
+//	explicitNull() -> dyn
+//	  e.g schema.spec.selector == "" ? explicitNull() : schema.spec.selector
+//
+// explicitNull returns a value setting the field to null, where a null value
+// may leave the field unset. Some APIs give a different meaning to a null
+// field and an absent one.
+func Nulls() cel.EnvOption {
+	return cel.Lib(nullsLib{})
+}
+
+type nullsLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (nullsLib) LibraryName() string {
+	return "kro.nulls"
+}
+
+// CompileOptions implements cel.Library.
+func (nullsLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("explicitNull",
+			cel.Overload("explicitNull",
+				[]*cel.Type{}, cel.DynType,
+				cel.FunctionBinding(func(...ref.Val) ref.Val {
+					return explicitNullVal{}
+				}),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (nullsLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// explicitNullType is the CEL type of the explicitNull() values.
+var explicitNullType = types.NewOpaqueType("kro.ExplicitNull")
+
+// explicitNullVal is the CEL value returned by explicitNull().
+type explicitNullVal struct{}
+
+// ConvertToNative implements ref.Val.
+func (explicitNullVal) ConvertToNative(typeDesc reflect.Type) (interface{}, error) {
+	if reflect.TypeOf(ExplicitNull).AssignableTo(typeDesc) {
+		return ExplicitNull, nil
+	}
+	return nil, fmt.Errorf("type conversion error from explicit null to '%v'", typeDesc)
+}
+
+// ConvertToType implements ref.Val.
+func (v explicitNullVal) ConvertToType(typeValue ref.Type) ref.Val {
+	switch typeValue {
+	case explicitNullType:
+		return v
+	case types.TypeType:
+		return explicitNullType
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", explicitNullType, typeValue)
+}
+
+// Equal implements ref.Val.
+func (explicitNullVal) Equal(other ref.Val) ref.Val {
+	_, ok := other.(explicitNullVal)
+	return types.Bool(ok)
+}
+
+// Type implements ref.Val.
+func (explicitNullVal) Type() ref.Type {
+	return explicitNullType
+}
+
+// Value implements ref.Val.
+func (explicitNullVal) Value() interface{} {
+	return ExplicitNull
+}
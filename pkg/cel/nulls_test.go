@@ -0,0 +1,92 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplicitNullFunction(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{"selector": ""},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+	}{
+		{
+			name:       "explicit null",
+			expression: `explicitNull()`,
+			want:       ExplicitNull,
+		},
+		{
+			name:       "conditional explicit null",
+			expression: `schema.spec.selector == "" ? explicitNull() : schema.spec.selector`,
+			want:       ExplicitNull,
+		},
+		{
+			name:       "null",
+			expression: `null`,
+			want:       nil,
+		},
+		{
+			name:       "nested explicit null",
+			expression: `{"matchLabels": explicitNull()}`,
+			want:       map[string]interface{}{"matchLabels": ExplicitNull},
+		},
+		{
+			name:       "explicit nulls are equal",
+			expression: `explicitNull() == explicitNull()`,
+			want:       true,
+		},
+		{
+			name:       "explicit null isn't null",
+			expression: `explicitNull() == null`,
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			out, _, err := program.Eval(context)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			got, err := GoNativeType(out)
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoNativeType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
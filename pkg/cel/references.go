@@ -0,0 +1,92 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// References returns a CEL library building references to Kubernetes
+// objects, e.g to fill the secretRef or objectRef fields of a resource.
+//
+//	ref(kind, name) -> map(string, string)
+//	  e.g ref("ClusterRole", "admin") == {"kind": "ClusterRole", "name": "admin"}
+//	ref(kind, name, namespace) -> map(string, string)
+//	  e.g ref("Secret", "creds", "default") == {"kind": "Secret", "name": "creds", "namespace": "default"}
+//
+// An empty namespace is left out of the reference, for objects whose
+// namespace is optional.
+func References() cel.EnvOption {
+	return cel.Lib(referencesLib{})
+}
+
+type referencesLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (referencesLib) LibraryName() string {
+	return "kro.references"
+}
+
+// CompileOptions implements cel.Library.
+func (referencesLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("ref",
+			cel.Overload("ref_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.MapType(cel.StringType, cel.StringType),
+				cel.BinaryBinding(func(kind, name ref.Val) ref.Val {
+					return objectReference(kind, name, types.String(""))
+				}),
+			),
+			cel.Overload("ref_string_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType, cel.StringType}, cel.MapType(cel.StringType, cel.StringType),
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					return objectReference(args[0], args[1], args[2])
+				}),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (referencesLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func objectReference(kind, name, namespace ref.Val) ref.Val {
+	k, kOk := kind.(types.String)
+	n, nOk := name.(types.String)
+	ns, nsOk := namespace.(types.String)
+	if !kOk || !nOk || !nsOk {
+		return invalidArgument("ref", errors.New("expected string arguments"))
+	}
+	if k == "" {
+		return invalidArgument("ref", errors.New("kind must not be empty"))
+	}
+	if n == "" {
+		return invalidArgument("ref", errors.New("name must not be empty"))
+	}
+
+	reference := map[string]string{
+		"kind": string(k),
+		"name": string(n),
+	}
+	if ns != "" {
+		reference["namespace"] = string(ns)
+	}
+	return types.NewStringStringMap(types.DefaultTypeAdapter, reference)
+}
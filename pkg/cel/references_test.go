@@ -0,0 +1,98 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReferenceFunctions(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema", "service"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{"namespace": "production"},
+		},
+		"service": map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "frontend"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{
+			name:       "namespaced",
+			expression: `ref("Secret", service.metadata.name, schema.spec.namespace)`,
+			want:       map[string]interface{}{"kind": "Secret", "name": "frontend", "namespace": "production"},
+		},
+		{
+			name:       "cluster scoped",
+			expression: `ref("ClusterRole", service.metadata.name + "-reader")`,
+			want:       map[string]interface{}{"kind": "ClusterRole", "name": "frontend-reader"},
+		},
+		{
+			name:       "empty namespace",
+			expression: `ref("Secret", "credentials", "")`,
+			want:       map[string]interface{}{"kind": "Secret", "name": "credentials"},
+		},
+		{
+			name:       "field access",
+			expression: `ref("Secret", "credentials", "default").namespace`,
+			want:       "default",
+		},
+		{name: "empty kind", expression: `ref("", "credentials")`, wantErr: "ref: invalid argument: kind must not be empty"},
+		{name: "empty name", expression: `ref("Secret", "", "default")`, wantErr: "ref: invalid argument: name must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			got, err := GoNativeType(val)
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GoNativeType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// Semver returns a CEL library comparing semantic versions, e.g to pick
+// images or API versions depending on a version of the instance spec. A
+// leading "v" is accepted, pre-releases are ordered before their release,
+// and build metadata is ignored.
+//
+//	semver.compare(string, string) -> int
+//	  e.g semver.compare("1.2.0", "1.10.0") == -1
+//	semver.gte(string, string) -> bool
+//	  e.g semver.gte("1.20.0", "1.20.0-rc.1") == true
+//	semver.lt(string, string) -> bool
+//	  e.g semver.lt("v1.19.3", "1.20.0") == true
+//	semver.major(string) -> int
+//	  e.g semver.major("1.20.3") == 1
+//	semver.minor(string) -> int
+//	  e.g semver.minor("1.20.3") == 20
+//	semver.patch(string) -> int
+//	  e.g semver.patch("1.20.3") == 3
+func Semver() cel.EnvOption {
+	return cel.Lib(semverLib{})
+}
+
+type semverLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (semverLib) LibraryName() string {
+	return "kro.semver"
+}
+
+// CompileOptions implements cel.Library.
+func (semverLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("semver.compare",
+			cel.Overload("semver_compare_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val {
+					return semverCompare("semver.compare", a, b, func(c int) ref.Val { return types.Int(c) })
+				}),
+			),
+		),
+		cel.Function("semver.gte",
+			cel.Overload("semver_gte_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val {
+					return semverCompare("semver.gte", a, b, func(c int) ref.Val { return types.Bool(c >= 0) })
+				}),
+			),
+		),
+		cel.Function("semver.lt",
+			cel.Overload("semver_lt_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(a, b ref.Val) ref.Val {
+					return semverCompare("semver.lt", a, b, func(c int) ref.Val { return types.Bool(c < 0) })
+				}),
+			),
+		),
+		cel.Function("semver.major",
+			cel.Overload("semver_major_string",
+				[]*cel.Type{cel.StringType}, cel.IntType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					return semverComponent("semver.major", v, (*utilversion.Version).Major)
+				}),
+			),
+		),
+		cel.Function("semver.minor",
+			cel.Overload("semver_minor_string",
+				[]*cel.Type{cel.StringType}, cel.IntType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					return semverComponent("semver.minor", v, (*utilversion.Version).Minor)
+				}),
+			),
+		),
+		cel.Function("semver.patch",
+			cel.Overload("semver_patch_string",
+				[]*cel.Type{cel.StringType}, cel.IntType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					return semverComponent("semver.patch", v, (*utilversion.Version).Patch)
+				}),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (semverLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// parseSemver parses the semantic version held by the CEL value.
+func parseSemver(v ref.Val) (*utilversion.Version, error) {
+	s, ok := v.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("expected a string, got %v", v.Type())
+	}
+	version, err := utilversion.ParseSemantic(string(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid semantic version %q: %w", string(s), err)
+	}
+	return version, nil
+}
+
+func semverCompare(function string, a, b ref.Val, result func(int) ref.Val) ref.Val {
+	va, err := parseSemver(a)
+	if err != nil {
+		return invalidArgument(function, err)
+	}
+	vb, err := parseSemver(b)
+	if err != nil {
+		return invalidArgument(function, err)
+	}
+	switch {
+	case va.LessThan(vb):
+		return result(-1)
+	case va.GreaterThan(vb):
+		return result(1)
+	default:
+		return result(0)
+	}
+}
+
+func semverComponent(function string, v ref.Val, component func(*utilversion.Version) uint) ref.Val {
+	version, err := parseSemver(v)
+	if err != nil {
+		return invalidArgument(function, err)
+	}
+	return types.Int(component(version))
+}
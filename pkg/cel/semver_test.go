@@ -0,0 +1,84 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSemverFunctions(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{"version": "v1.21.3"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{name: "compare lower", expression: `semver.compare("1.2.0", "1.10.0")`, want: int64(-1)},
+		{name: "compare equal", expression: `semver.compare("1.2.0", "v1.2.0")`, want: int64(0)},
+		{name: "compare greater", expression: `semver.compare("2.0.0", "1.99.99")`, want: int64(1)},
+		{name: "compare ignores build metadata", expression: `semver.compare("1.2.0+build.1", "1.2.0+build.2")`, want: int64(0)},
+		{name: "gte spec version", expression: `semver.gte(schema.spec.version, "1.20.0")`, want: true},
+		{name: "gte equal", expression: `semver.gte("1.20.0", "1.20.0")`, want: true},
+		{name: "lt spec version", expression: `semver.lt(schema.spec.version, "1.20.0")`, want: false},
+		{name: "prerelease before release", expression: `semver.lt("1.20.0-rc.1", "1.20.0")`, want: true},
+		{name: "prereleases ordering", expression: `semver.compare("1.20.0-alpha.2", "1.20.0-alpha.10")`, want: int64(-1)},
+		{name: "release after prerelease", expression: `semver.gte("1.20.0", "1.20.0-rc.1")`, want: true},
+		{name: "major", expression: `semver.major(schema.spec.version)`, want: int64(1)},
+		{name: "minor", expression: `semver.minor(schema.spec.version)`, want: int64(21)},
+		{name: "patch", expression: `semver.patch(schema.spec.version)`, want: int64(3)},
+		{name: "invalid version", expression: `semver.gte("latest", "1.20.0")`, wantErr: `semver.gte: invalid argument: invalid semantic version "latest"`},
+		{name: "incomplete version", expression: `semver.major("1.20")`, wantErr: `semver.major: invalid argument: invalid semantic version "1.20"`},
+		{name: "invalid second version", expression: `semver.compare("1.20.0", "1.x.0")`, wantErr: `semver.compare: invalid argument: invalid semantic version "1.x.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if val.Value() != tt.want {
+				t.Errorf("Eval() = %v, want %v", val.Value(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+)
+
+// ErrUnknownFunction is returned when an expression calls a function that
+// isn't declared in the environment, e.g a typo'd function name.
+var ErrUnknownFunction = errors.New("unknown function")
+
+// macroNames are the macros expanded by the parser, they're not part of the
+// environment functions but are valid suggestions.
+var macroNames = []string{"all", "countWhere", "exists", "exists_one", "filter", "has", "map"}
+
+// maxSuggestions is the maximum number of similar functions suggested.
+const maxSuggestions = 3
+
+// UnknownFunctionError returns an error wrapping ErrUnknownFunction if the
+// expression calls a function the environment doesn't declare, suggesting
+// the similar declared functions. e.g
+//
+//	unknown function 'yaml.decod', did you mean 'yaml.decode'?
+//
+// It returns nil if all the called functions are declared, or if the
+// expression can't be parsed.
+func UnknownFunctionError(env *cel.Env, expression string) error {
+	ast, issues := env.Parse(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil
+	}
+	var unknown string
+	celast.PreOrderVisit(ast.NativeRep().Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		if unknown != "" || e.Kind() != celast.CallKind {
+			return
+		}
+		if name, ok := unknownFunctionName(env, e.AsCall()); ok {
+			unknown = name
+		}
+	}))
+	if unknown == "" {
+		return nil
+	}
+
+	suggestions := SuggestFunctions(env, unknown)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("%w '%s'", ErrUnknownFunction, unknown)
+	}
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = "'" + suggestion + "'"
+	}
+	return fmt.Errorf("%w '%s', did you mean %s?", ErrUnknownFunction, unknown, strings.Join(quoted, " or "))
+}
+
+// unknownFunctionName returns the name of the function called, and true if
+// the environment doesn't declare it. Calls on an identifier are namespaced
+// function calls (e.g yaml.decode(x)) when a function of the namespace is
+// declared, and method calls otherwise.
+func unknownFunctionName(env *cel.Env, call celast.CallExpr) (string, bool) {
+	name := call.FunctionName()
+	if strings.HasPrefix(name, "_") || strings.HasPrefix(name, "@") || strings.HasPrefix(name, "!") {
+		// Operators.
+		return "", false
+	}
+	if call.IsMemberFunction() && call.Target().Kind() == celast.IdentKind {
+		namespace := call.Target().AsIdent()
+		qualified := namespace + "." + name
+		if env.HasFunction(qualified) {
+			return "", false
+		}
+		if isFunctionNamespace(env, namespace) {
+			return qualified, true
+		}
+	}
+	if env.HasFunction(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// isFunctionNamespace returns true if the environment declares functions in
+// the given namespace, e.g "yaml" for yaml.decode.
+func isFunctionNamespace(env *cel.Env, namespace string) bool {
+	for name := range env.Functions() {
+		if strings.HasPrefix(name, namespace+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestFunctions returns the declared functions, and macros, with the name
+// the most similar to the given one, sorted. Names are compared ignoring
+// the case, dots and underscores, e.g "base64Encode" is similar to
+// "base64.encode".
+func SuggestFunctions(env *cel.Env, name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	normalized := normalizeFunctionName(name)
+	threshold := max(2, len(normalized)/3)
+
+	var candidates []candidate
+	names := slices.Clone(macroNames)
+	for declared := range env.Functions() {
+		names = append(names, declared)
+	}
+	for _, declared := range names {
+		if strings.HasPrefix(declared, "_") || strings.HasPrefix(declared, "@") || strings.HasPrefix(declared, "!") {
+			continue
+		}
+		distance := levenshtein(normalized, normalizeFunctionName(declared))
+		if distance <= threshold && declared != name {
+			candidates = append(candidates, candidate{name: declared, distance: distance})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	candidates = slices.CompactFunc(candidates, func(a, b candidate) bool { return a.name == b.name })
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, c := range candidates {
+		// Only the closest names are relevant.
+		if len(suggestions) == maxSuggestions || c.distance > candidates[0].distance {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// normalizeFunctionName lower cases the name, and removes the dots and
+// underscores.
+func normalizeFunctionName(name string) string {
+	return strings.NewReplacer(".", "", "_", "").Replace(strings.ToLower(name))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
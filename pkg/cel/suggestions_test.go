@@ -0,0 +1,117 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+func TestUnknownFunctionError(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	// An environment with the encoders library, for base64.encode.
+	encodersEnv, err := env.Extend(ext.Encoders())
+	if err != nil {
+		t.Fatalf("Extend() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		env        *cel.Env
+		expression string
+		wantErr    string
+	}{
+		{
+			name:       "camel cased namespaced function",
+			env:        encodersEnv,
+			expression: `base64Encode(b"kro")`,
+			wantErr:    "unknown function 'base64Encode', did you mean 'base64.encode'?",
+		},
+		{
+			name:       "typo in a namespaced function",
+			env:        env,
+			expression: `yaml.decod(schema.spec.config)`,
+			wantErr:    "unknown function 'yaml.decod', did you mean 'yaml.decode'?",
+		},
+		{
+			name:       "typo in a global function",
+			env:        env,
+			expression: `checksun(schema.spec)`,
+			wantErr:    "unknown function 'checksun', did you mean 'checksum'?",
+		},
+		{
+			name:       "typo in a method",
+			env:        env,
+			expression: `schema.spec.name.upperAsci()`,
+			wantErr:    "unknown function 'upperAsci', did you mean 'upperAscii'?",
+		},
+		{
+			name:       "no similar function",
+			env:        env,
+			expression: `frobnicate(schema.spec)`,
+			wantErr:    "unknown function 'frobnicate'",
+		},
+		{
+			name:       "known functions",
+			env:        env,
+			expression: `yaml.decode(schema.spec.config).name.upperAscii() + checksum(schema.spec)`,
+		},
+		{
+			name:       "undeclared variable",
+			env:        env,
+			expression: `service.spec.name`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UnknownFunctionError(tt.env, tt.expression)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("UnknownFunctionError() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnknownFunction) {
+				t.Fatalf("UnknownFunctionError() error = %v, want %v", err, ErrUnknownFunction)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("UnknownFunctionError() error = %q, want %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuggestFunctions(t *testing.T) {
+	env, err := DefaultEnvironment()
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	got := SuggestFunctions(env, "cidrContains")
+	if len(got) == 0 || got[0] != "cidr.contains" {
+		t.Errorf("SuggestFunctions(cidrContains) = %v, want cidr.contains first", got)
+	}
+	if got := SuggestFunctions(env, "countwhere"); len(got) == 0 || got[0] != "countWhere" {
+		t.Errorf("SuggestFunctions(countwhere) = %v, want countWhere first", got)
+	}
+	if got := SuggestFunctions(env, strings.Repeat("z", 20)); len(got) != 0 {
+		t.Errorf("SuggestFunctions() = %v, want no suggestions", got)
+	}
+}
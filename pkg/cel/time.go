@@ -0,0 +1,83 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+	"time"
+	// The controller image doesn't ship the time zone database, embed it
+	// for WithTimeZone.
+	_ "time/tzdata"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// Time returns a CEL library formatting timestamps in the given location,
+// e.g for cron schedules or display timestamps. See WithTimeZone.
+//
+//	time.format(timestamp) -> string
+//	  e.g time.format(timestamp("2025-01-02T03:04:05Z")) == "2025-01-02T03:04:05Z"
+//	time.format(timestamp, layout) -> string
+//	  e.g time.format(timestamp("2025-01-02T03:04:05Z"), "15:04") == "03:04"
+//
+// The layouts are Go time layouts, see the time package.
+func Time(location *time.Location) cel.EnvOption {
+	return cel.Lib(timeLib{location: location})
+}
+
+type timeLib struct {
+	location *time.Location
+}
+
+// LibraryName implements cel.SingletonLibrary.
+func (timeLib) LibraryName() string {
+	return "kro.time"
+}
+
+// CompileOptions implements cel.Library.
+func (l timeLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("time.format",
+			cel.Overload("time_format_timestamp",
+				[]*cel.Type{cel.TimestampType}, cel.StringType,
+				cel.UnaryBinding(func(ts ref.Val) ref.Val {
+					return l.format(ts, types.String(time.RFC3339))
+				}),
+			),
+			cel.Overload("time_format_timestamp_string",
+				[]*cel.Type{cel.TimestampType, cel.StringType}, cel.StringType,
+				cel.BinaryBinding(l.format),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (timeLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func (l timeLib) format(ts, layout ref.Val) ref.Val {
+	t, ok := ts.(types.Timestamp)
+	if !ok {
+		return invalidArgument("time.format", fmt.Errorf("expected a timestamp, got %v", ts.Type()))
+	}
+	s, ok := layout.(types.String)
+	if !ok {
+		return invalidArgument("time.format", fmt.Errorf("expected a string layout, got %v", layout.Type()))
+	}
+	return types.String(t.Time.In(l.location).Format(string(s)))
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTimeFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeZone   string
+		expression string
+		want       string
+	}{
+		{
+			name:       "defaults to UTC",
+			expression: `time.format(timestamp("2025-01-02T03:04:05Z"))`,
+			want:       "2025-01-02T03:04:05Z",
+		},
+		{
+			name:       "custom layout in UTC",
+			expression: `time.format(timestamp("2025-01-02T03:04:05Z"), "15:04")`,
+			want:       "03:04",
+		},
+		{
+			name:       "RFC3339 in a non UTC zone",
+			timeZone:   "Asia/Tokyo",
+			expression: `time.format(timestamp("2025-01-02T03:04:05Z"))`,
+			want:       "2025-01-02T12:04:05+09:00",
+		},
+		{
+			name:       "custom layout with daylight saving time",
+			timeZone:   "America/New_York",
+			expression: `time.format(timestamp("2025-07-01T12:00:00Z"), "2006-01-02 15:04 MST")`,
+			want:       "2025-07-01 08:00 EDT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := DefaultEnvironment(WithTimeZone(tt.timeZone))
+			if err != nil {
+				t.Fatalf("DefaultEnvironment() error = %v", err)
+			}
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(map[string]interface{}{})
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if val.Value() != tt.want {
+				t.Errorf("Eval() = %v, want %v", val.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidTimeZone(t *testing.T) {
+	_, err := DefaultEnvironment(WithTimeZone("Mars/Olympus_Mons"))
+	if err == nil || !strings.Contains(err.Error(), `invalid time zone "Mars/Olympus_Mons"`) {
+		t.Errorf("DefaultEnvironment() error = %v, want an invalid time zone error", err)
+	}
+}
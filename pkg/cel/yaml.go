@@ -0,0 +1,123 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"sigs.k8s.io/yaml"
+)
+
+// YAML returns a CEL library decoding and encoding YAML documents, e.g to
+// expand a configuration authored as a YAML string into a structured field.
+//
+//	yaml.decode(string) -> dyn
+//	  e.g yaml.decode("replicas: 3\nname: app") == {"replicas": 3, "name": "app"}
+//	yaml.encode(dyn) -> string
+//	  e.g yaml.encode({"replicas": 3}) == "replicas: 3\n"
+func YAML() cel.EnvOption {
+	return cel.Lib(yamlLib{})
+}
+
+type yamlLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (yamlLib) LibraryName() string {
+	return "kro.yaml"
+}
+
+// CompileOptions implements cel.Library.
+func (yamlLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("yaml.decode",
+			cel.Overload("yaml_decode_string",
+				[]*cel.Type{cel.StringType}, cel.DynType,
+				cel.UnaryBinding(yamlDecode),
+			),
+		),
+		cel.Function("yaml.encode",
+			cel.Overload("yaml_encode_dyn",
+				[]*cel.Type{cel.DynType}, cel.StringType,
+				cel.UnaryBinding(yamlEncode),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (yamlLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func yamlDecode(document ref.Val) ref.Val {
+	s, ok := document.(types.String)
+	if !ok {
+		return invalidArgument("yaml.decode", fmt.Errorf("expected a string, got %v", document.Type()))
+	}
+	data, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		return invalidArgument("yaml.decode", fmt.Errorf("invalid YAML: %w", err))
+	}
+	// Decode the numbers as json.Number, to tell integers apart from
+	// floating point numbers.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return invalidArgument("yaml.decode", fmt.Errorf("invalid YAML: %w", err))
+	}
+	return types.DefaultTypeAdapter.NativeToValue(convertJSONNumbers(value))
+}
+
+func yamlEncode(value ref.Val) ref.Val {
+	native, err := GoNativeType(value)
+	if err != nil {
+		return invalidArgument("yaml.encode", err)
+	}
+	data, err := yaml.Marshal(native)
+	if err != nil {
+		return invalidArgument("yaml.encode", err)
+	}
+	return types.String(data)
+}
+
+// convertJSONNumbers replaces the json.Number values by int64 values, or
+// float64 values when they're not integers.
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
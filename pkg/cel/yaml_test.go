@@ -0,0 +1,108 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cel
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLFunctions(t *testing.T) {
+	env, err := DefaultEnvironment(WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"config": "name: app\nreplicas: 3\nratio: 0.5\nenabled: true\nports:\n  - 80\n  - 443\nlabels:\n  tier: web\n",
+				"broken": "name: [app",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		wantErr    string
+	}{
+		{
+			name:       "decode a multi-key document",
+			expression: `yaml.decode(schema.spec.config)`,
+			want: map[string]interface{}{
+				"name":     "app",
+				"replicas": int64(3),
+				"ratio":    0.5,
+				"enabled":  true,
+				"ports":    []interface{}{int64(80), int64(443)},
+				"labels":   map[string]interface{}{"tier": "web"},
+			},
+		},
+		{
+			name:       "access a decoded field",
+			expression: `yaml.decode(schema.spec.config).replicas + 1`,
+			want:       int64(4),
+		},
+		{
+			name:       "encode a map",
+			expression: `yaml.encode({"name": "app", "replicas": 3})`,
+			want:       "name: app\nreplicas: 3\n",
+		},
+		{
+			name:       "round trip",
+			expression: `yaml.decode(yaml.encode(yaml.decode(schema.spec.config))) == yaml.decode(schema.spec.config)`,
+			want:       true,
+		},
+		{
+			name:       "invalid YAML",
+			expression: `yaml.decode(schema.spec.broken)`,
+			wantErr:    "yaml.decode: invalid argument: invalid YAML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ast, issues := env.Compile(tt.expression)
+			if issues != nil && issues.Err() != nil {
+				t.Fatalf("Compile() error = %v", issues.Err())
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("Program() error = %v", err)
+			}
+			val, _, err := program.Eval(context)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Eval() error = %v, want %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, ErrInvalidArgument) {
+					t.Errorf("Eval() error = %v, want %v", err, ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			got, err := GoNativeType(val)
+			if err != nil {
+				t.Fatalf("GoNativeType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
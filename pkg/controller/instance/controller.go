@@ -17,13 +17,17 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/dynamic"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -31,6 +35,7 @@ import (
 	kroclient "github.com/kro-run/kro/pkg/client"
 	"github.com/kro-run/kro/pkg/graph"
 	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/runtime"
 )
 
 // ReconcileConfig holds configuration parameters for the recnociliation process.
@@ -47,6 +52,10 @@ type ReconcileConfig struct {
 	// TODO(a-hilaly): need to define think the different deletion policies we need to
 	// support.
 	DeletionPolicy string
+	// ClusterInfo is the identity of the cluster the controller runs in, e.g
+	// its name, region or provider. It's exposed to the expressions as the
+	// "cluster" variable.
+	ClusterInfo map[string]interface{}
 }
 
 // Controller manages the reconciliation of a single instance of a ResourceGraphDefinition,
@@ -88,6 +97,21 @@ type Controller struct {
 	reconcileConfig ReconcileConfig
 	// defaultServiceAccounts is a map of service accounts to use for controller impersonation.
 	defaultServiceAccounts map[string]string
+	// previousStates holds the resolved state of the last reconcile of each
+	// instance, keyed by namespaced name, see previousState.
+	previousStates sync.Map
+}
+
+// previousState is the resolved state of the last reconcile of an instance,
+// exposed to the expressions of the next reconcile as the "previous"
+// variable. It's only kept in memory: the first reconcile after a controller
+// restart has no previous state. Only the fields read by the expressions are
+// kept, see ResourceGraphDefinitionRuntime.ReferencedState.
+type previousState struct {
+	// uid is the uid of the instance, a recreated instance doesn't see the
+	// state of the deleted one.
+	uid   types.UID
+	state map[string]interface{}
 }
 
 // NewController creates a new Controller instance.
@@ -121,6 +145,7 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("Instance not found, it may have been deleted")
+			c.previousStates.Delete(req.Name)
 			return nil
 		}
 		log.Error(err, "Failed to get instance")
@@ -132,7 +157,11 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 	// instance of the resource graph definition. The instance graph reconciler is responsible
 	// for reconciling the instance and its sub-resources, while keeping the same
 	// runtime object in it's fields.
-	rgRuntime, err := c.rgd.NewGraphRuntime(instance)
+	rgRuntime, err := c.rgd.NewGraphRuntime(instance,
+		runtime.WithClusterInfo(c.reconcileConfig.ClusterInfo),
+		runtime.WithReconcileContext(c.newReconcileContext()),
+		runtime.WithPreviousState(c.loadPreviousState(req.Name, instance.GetUID())),
+	)
 	if err != nil {
 		return fmt.Errorf("failed to create runtime resource graph definition: %w", err)
 	}
@@ -160,7 +189,53 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) error {
 		// Fresh instance state at each reconciliation loop.
 		state: newInstanceState(),
 	}
-	return instanceGraphReconciler.reconcile(ctx)
+	err = instanceGraphReconciler.reconcile(ctx)
+	c.storePreviousState(req.Name, instance, rgRuntime, err)
+	return err
+}
+
+// storePreviousState keeps the state read by the "previous" expressions for
+// the next reconcile of the instance. Nothing is kept if the expressions
+// don't read the previous state, or once the instance is finalized.
+func (c *Controller) storePreviousState(key string, instance *unstructured.Unstructured, rt runtime.Interface, reconcileErr error) {
+	// A successful reconcile of a deleted instance removed its finalizer.
+	finalized := !instance.GetDeletionTimestamp().IsZero() && reconcileErr == nil
+	state := rt.ReferencedState()
+	if finalized || state == nil {
+		c.previousStates.Delete(key)
+		return
+	}
+	c.previousStates.Store(key, previousState{
+		uid:   instance.GetUID(),
+		state: state,
+	})
+}
+
+// loadPreviousState returns the resolved state of the last reconcile of the
+// instance, or nil if there is none.
+func (c *Controller) loadPreviousState(key string, uid types.UID) map[string]interface{} {
+	value, ok := c.previousStates.Load(key)
+	if !ok {
+		return nil
+	}
+	previous := value.(previousState)
+	if previous.uid != uid {
+		return nil
+	}
+	return previous.state
+}
+
+// newReconcileContext returns the contextual information of a new reconcile,
+// exposed to the expressions as the "reconcileContext" variable: a unique
+// reconcile id and, when known, the name of the cluster.
+func (c *Controller) newReconcileContext() map[string]interface{} {
+	reconcileContext := map[string]interface{}{
+		"reconcileID": string(uuid.NewUUID()),
+	}
+	if name, ok := c.reconcileConfig.ClusterInfo["name"]; ok {
+		reconcileContext["clusterName"] = name
+	}
+	return reconcileContext
 }
 
 // getNamespaceName extracts the namespace and name from the request.
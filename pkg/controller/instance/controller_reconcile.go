@@ -15,6 +15,7 @@ package instance
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-logr/logr"
@@ -25,12 +26,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 
-	"github.com/kro-run/kro/pkg/controller/instance/delta"
 	"github.com/kro-run/kro/pkg/metadata"
 	"github.com/kro-run/kro/pkg/requeue"
 	"github.com/kro-run/kro/pkg/runtime"
 )
 
+// FieldManager is the field manager kro applies the resources with. kro owns
+// the fields of the resource templates, and server-side apply removes the
+// fields it stops applying.
+const FieldManager = "kro"
+
 // instanceGraphReconciler is responsible for reconciling a single instance and
 // and its associated sub-resources. It executes the reconciliation logic based
 // on the graph inferred from the ResourceGraphDefinition analysis.
@@ -81,7 +86,11 @@ func (igr *instanceGraphReconciler) handleReconciliation(ctx context.Context, re
 		igr.updateInstanceState()
 
 		// Prepare and patch status
-		status := igr.prepareStatus()
+		status, err := igr.prepareStatus()
+		if err != nil {
+			igr.log.Error(err, "Failed to prepare instance status")
+			return
+		}
 		if err := igr.patchInstanceStatus(ctx, status); err != nil {
 			// Only log error if instance still exists
 			if !apierrors.IsNotFound(err) {
@@ -221,9 +230,10 @@ func (igr *instanceGraphReconciler) handleResourceCreation(
 ) error {
 	igr.log.V(1).Info("Creating new resource", "resourceID", resourceID)
 
-	// Apply labels and create resource
+	// Apply labels and create resource. The resource is created with
+	// server-side apply, for kro to own the template fields from the start.
 	igr.instanceSubResourcesLabeler.ApplyLabels(resource)
-	if _, err := rc.Create(ctx, resource, metav1.CreateOptions{}); err != nil {
+	if _, err := rc.Apply(ctx, resource.GetName(), resource, metav1.ApplyOptions{FieldManager: FieldManager, Force: true}); err != nil {
 		resourceState.State = "ERROR"
 		resourceState.Err = fmt.Errorf("failed to create resource: %w", err)
 		return resourceState.Err
@@ -233,8 +243,8 @@ func (igr *instanceGraphReconciler) handleResourceCreation(
 	return igr.delayedRequeue(fmt.Errorf("awaiting resource creation completion"))
 }
 
-// updateResource handles updates to an existing resource, comparing the desired
-// and observed states and applying the necessary changes.
+// updateResource handles updates to an existing resource, applying the desired
+// state with server-side apply.
 func (igr *instanceGraphReconciler) updateResource(
 	ctx context.Context,
 	rc dynamic.ResourceInterface,
@@ -243,43 +253,40 @@ func (igr *instanceGraphReconciler) updateResource(
 	resourceState *ResourceState,
 ) error {
 	igr.log.V(1).Info("Processing resource update", "resourceID", resourceID)
+	igr.instanceSubResourcesLabeler.ApplyLabels(desired)
 
-	// Compare desired and observed states
-	differences, err := delta.Compare(desired, observed)
+	// Apply the desired object, which only holds the template fields, with
+	// server-side apply. The fields kro doesn't manage (server defaulted
+	// fields, fields added by other controllers...) are preserved, the fields
+	// kro applied before but that left the template are removed, and
+	// explicit nulls are written as nulls. The resource is applied even
+	// without visible differences, a comparison can't tell the removed
+	// fields, and an apply without changes keeps the resource version.
+	// The observed resource version makes the apply fail if the resource
+	// changed in the meantime.
+	// TODO: Handle annotations
+	desired = desired.DeepCopy()
+	desired.SetResourceVersion(observed.GetResourceVersion())
+	applied, err := rc.Apply(ctx, desired.GetName(), desired, metav1.ApplyOptions{FieldManager: FieldManager, Force: true})
+	if err != nil && igr.runtime.ResourceDescriptor(resourceID).GetIgnoreUpdateErrors() {
+		igr.log.Info("Ignoring resource update failure", "resourceID", resourceID, "error", err)
+		resourceState.State = "UPDATE_FAILED_IGNORED"
+		resourceState.Err = fmt.Errorf("failed to update resource: %w", err)
+		return nil
+	}
 	if err != nil {
 		resourceState.State = "ERROR"
-		resourceState.Err = fmt.Errorf("failed to compare desired and observed states: %w", err)
+		resourceState.Err = fmt.Errorf("failed to update resource: %w", err)
 		return resourceState.Err
 	}
 
-	// If no differences are found, the resource is in sync.
-	if len(differences) == 0 {
+	// If the apply didn't change anything, the resource is in sync.
+	if applied.GetResourceVersion() == observed.GetResourceVersion() {
 		resourceState.State = "SYNCED"
 		igr.log.V(1).Info("No deltas found for resource", "resourceID", resourceID)
 		return nil
 	}
 
-	// Proceed with the update, note that we don't need to handle each difference
-	// individually. We can apply all changes at once.
-	//
-	// NOTE(a-hilaly): are there any cases where we need to handle each difference individually?
-	igr.log.V(1).Info("Found deltas for resource",
-		"resourceID", resourceID,
-		"delta", differences,
-	)
-	igr.instanceSubResourcesLabeler.ApplyLabels(desired)
-
-	// Apply changes to the resource
-	// TODO: Handle annotations
-	desired.SetResourceVersion(observed.GetResourceVersion())
-	desired.SetFinalizers(observed.GetFinalizers())
-	_, err = rc.Update(ctx, desired, metav1.UpdateOptions{})
-	if err != nil {
-		resourceState.State = "ERROR"
-		resourceState.Err = fmt.Errorf("failed to update resource: %w", err)
-		return resourceState.Err
-	}
-
 	// Set state to UPDATING and requeue to check the update
 	resourceState.State = "UPDATING"
 	return igr.delayedRequeue(fmt.Errorf("resource update in progress"))
@@ -368,8 +375,23 @@ func (igr *instanceGraphReconciler) deleteResource(ctx context.Context, resource
 	resource, _ := igr.runtime.GetResource(resourceID)
 	rc := igr.getResourceClient(resourceID)
 
+	policy, err := igr.runtime.GetDeletionPolicy(resourceID)
+	if err != nil {
+		igr.state.ResourceStates[resourceID].State = InstanceStateError
+		igr.state.ResourceStates[resourceID].Err = fmt.Errorf("failed to get deletion policy: %w", err)
+		return igr.state.ResourceStates[resourceID].Err
+	}
+	switch policy {
+	case runtime.DeletionPolicyRetain:
+		igr.log.V(1).Info("Retaining resource", "resourceID", resourceID)
+		igr.state.ResourceStates[resourceID].State = "RETAINED"
+		return nil
+	case runtime.DeletionPolicyOrphan:
+		return igr.orphanResource(ctx, rc, resource, resourceID)
+	}
+
 	// Attempt to delete the resource
-	err := rc.Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
+	err = rc.Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			igr.state.ResourceStates[resourceID].State = "DELETED"
@@ -384,12 +406,75 @@ func (igr *instanceGraphReconciler) deleteResource(ctx context.Context, resource
 	return igr.delayedRequeue(fmt.Errorf("resource deletion in progress"))
 }
 
+// orphanResource leaves the resource behind, after removing the labels
+// linking it to the instance and the ownerReference pointing to the
+// instance, which would let the garbage collector delete it along with the
+// instance.
+func (igr *instanceGraphReconciler) orphanResource(
+	ctx context.Context,
+	rc dynamic.ResourceInterface,
+	resource *unstructured.Unstructured,
+	resourceID string,
+) error {
+	igr.log.V(1).Info("Orphaning resource", "resourceID", resourceID)
+
+	live, err := rc.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		igr.state.ResourceStates[resourceID].State = "ORPHANED"
+		return nil
+	}
+	if err != nil {
+		igr.state.ResourceStates[resourceID].State = InstanceStateError
+		igr.state.ResourceStates[resourceID].Err = fmt.Errorf("failed to get resource: %w", err)
+		return igr.state.ResourceStates[resourceID].Err
+	}
+
+	labels := map[string]interface{}{}
+	for key := range igr.instanceSubResourcesLabeler.Labels() {
+		labels[key] = nil
+	}
+	// The resource version makes the patch fail if the owner references
+	// changed in the meantime, merge patches replace the whole list.
+	patchMetadata := map[string]interface{}{
+		"labels":          labels,
+		"resourceVersion": live.GetResourceVersion(),
+	}
+	instanceUID := igr.runtime.GetInstance().GetUID()
+	ownerReferences := live.GetOwnerReferences()
+	remaining := make([]metav1.OwnerReference, 0, len(ownerReferences))
+	for _, ref := range ownerReferences {
+		if ref.UID != instanceUID {
+			remaining = append(remaining, ref)
+		}
+	}
+	if len(remaining) != len(ownerReferences) {
+		patchMetadata["ownerReferences"] = remaining
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": patchMetadata})
+	if err != nil {
+		return fmt.Errorf("failed to build orphan patch: %w", err)
+	}
+
+	_, err = rc.Patch(ctx, resource.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		igr.state.ResourceStates[resourceID].State = InstanceStateError
+		igr.state.ResourceStates[resourceID].Err = fmt.Errorf("failed to orphan resource: %w", err)
+		return igr.state.ResourceStates[resourceID].Err
+	}
+
+	igr.state.ResourceStates[resourceID].State = "ORPHANED"
+	return nil
+}
+
 // finalizeDeletion checks if all resources are deleted and removes the instance finalizer
 // if appropriate.
 func (igr *instanceGraphReconciler) finalizeDeletion(ctx context.Context) error {
 	// Check if all resources are deleted
 	for _, resourceState := range igr.state.ResourceStates {
-		if resourceState.State != "DELETED" && resourceState.State != "SKIPPED" {
+		switch resourceState.State {
+		case "DELETED", "SKIPPED", "RETAINED", "ORPHANED":
+			// The resource is gone, or left behind on purpose.
+		default:
 			return igr.delayedRequeue(fmt.Errorf("waiting for resource deletion completion"))
 		}
 	}
@@ -0,0 +1,186 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/runtime"
+	"github.com/kro-run/kro/pkg/testutil/fakeruntime"
+)
+
+var widgetsGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+// newFakeClient returns a client knowing the widgets, backed by a tracker
+// implementing server-side apply.
+func newFakeClient() *dynamicfake.FakeDynamicClient {
+	scheme := k8sruntime.NewScheme()
+	gv := widgetsGVR.GroupVersion()
+	scheme.AddKnownTypeWithName(gv.WithKind("Widget"), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(gv.WithKind("WidgetList"), &unstructured.UnstructuredList{})
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		widgetsGVR: "WidgetList",
+	})
+	tracker := clienttesting.NewFieldManagedObjectTracker(scheme, unstructured.UnstructuredJSONScheme, managedfields.NewDeducedTypeConverter())
+	client.ReactionChain = nil
+	client.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+	return client
+}
+
+// newWidget returns a widget with the given spec.
+func newWidget(spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":      "widget",
+			"namespace": "default",
+		},
+		"spec": spec,
+	}}
+}
+
+func newTestReconciler() *instanceGraphReconciler {
+	return &instanceGraphReconciler{
+		log:                         logr.Discard(),
+		instanceSubResourcesLabeler: metadata.GenericLabeler{"kro.run/instance-name": "test"},
+		state:                       newInstanceState(),
+	}
+}
+
+func Test_updateResource(t *testing.T) {
+	ctx := context.Background()
+	rc := newFakeClient().Resource(widgetsGVR).Namespace("default")
+	igr := newTestReconciler()
+
+	// Created by another client, kro applies its template on top of it.
+	if _, err := rc.Create(ctx, newWidget(map[string]interface{}{"defaulted": "server"}), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	update := func(spec map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		observed, err := rc.Get(ctx, "widget", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		state := &ResourceState{}
+		_ = igr.updateResource(ctx, rc, newWidget(spec), observed, "widget", state)
+		if state.Err != nil {
+			t.Fatalf("updateResource() error = %v", state.Err)
+		}
+		live, err := rc.Get(ctx, "widget", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return live.Object["spec"].(map[string]interface{})
+	}
+
+	got := update(map[string]interface{}{"replicas": int64(2), "selector": map[string]interface{}{"app": "a"}})
+	want := map[string]interface{}{"defaulted": "server", "replicas": int64(2), "selector": map[string]interface{}{"app": "a"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("spec = %v, want %v", got, want)
+	}
+
+	t.Run("fields removed from the template are removed", func(t *testing.T) {
+		got := update(map[string]interface{}{"selector": map[string]interface{}{"app": "a"}})
+		want := map[string]interface{}{"defaulted": "server", "selector": map[string]interface{}{"app": "a"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("spec = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("explicit nulls are written", func(t *testing.T) {
+		got := update(map[string]interface{}{"selector": nil})
+		want := map[string]interface{}{"defaulted": "server", "selector": nil}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("spec = %v, want %v", got, want)
+		}
+	})
+}
+
+func Test_orphanResource(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	rc := client.Resource(widgetsGVR).Namespace("default")
+
+	instance := fakeruntime.NewResource(map[string]interface{}{
+		"apiVersion": "kro.run/v1alpha1",
+		"kind":       "WidgetApp",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "default",
+			"uid":       "instance-uid",
+		},
+	})
+	widget := fakeruntime.NewResource(newWidget(map[string]interface{}{"replicas": int64(1)}).Object)
+	widget.GVR = widgetsGVR
+	widget.Namespaced = true
+	widget.DeletionPolicy = runtime.DeletionPolicyOrphan
+	rt, err := runtime.NewResourceGraphDefinitionRuntime(
+		instance,
+		map[string]runtime.Resource{"widget": widget},
+		[]string{"widget"},
+		runtime.WithOwnerReferences(),
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// The widget is also owned by another object, which must stay.
+	rendered, _ := rt.GetResource("widget")
+	otherOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+	rendered.SetOwnerReferences(append(rendered.GetOwnerReferences(), otherOwner))
+	if got := len(rendered.GetOwnerReferences()); got != 2 {
+		t.Fatalf("rendered widget has %d ownerReferences, want 2", got)
+	}
+	igr := newTestReconciler()
+	igr.client = client
+	igr.runtime = rt
+	igr.instanceSubResourcesLabeler.ApplyLabels(rendered)
+	if _, err := rc.Create(ctx, rendered, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	igr.state.ResourceStates["widget"] = &ResourceState{}
+	if err := igr.deleteResource(ctx, "widget"); err != nil {
+		t.Fatalf("deleteResource() error = %v", err)
+	}
+	if got := igr.state.ResourceStates["widget"].State; got != "ORPHANED" {
+		t.Errorf("state = %s, want ORPHANED", got)
+	}
+
+	live, err := rc.Get(ctx, "widget", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := live.GetOwnerReferences(); !reflect.DeepEqual(got, []metav1.OwnerReference{otherOwner}) {
+		t.Errorf("ownerReferences = %v, want %v", got, []metav1.OwnerReference{otherOwner})
+	}
+	if got := live.GetLabels(); len(got) != 0 {
+		t.Errorf("labels = %v, want none", got)
+	}
+}
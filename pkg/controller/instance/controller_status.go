@@ -15,11 +15,15 @@ package instance
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/kro-run/kro/api/v1alpha1"
 	"github.com/kro-run/kro/pkg/requeue"
@@ -37,32 +41,46 @@ func createCondition(conditionType v1alpha1.ConditionType, status corev1.Conditi
 }
 
 // prepareStatus creates the status object for the instance based on current state.
-func (igr *instanceGraphReconciler) prepareStatus() map[string]interface{} {
-	status := igr.getResolvedStatus()
+//
+// Only the status fields owned by kro (the state, the conditions and the
+// fields set by the instance expressions) are part of it, the fields written
+// by other controllers are left untouched by the status patch.
+func (igr *instanceGraphReconciler) prepareStatus() (map[string]interface{}, error) {
+	status, err := igr.runtime.ManagedStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed status: %w", err)
+	}
 	generation := igr.runtime.GetInstance().GetGeneration()
 
 	status["state"] = igr.state.State
-	status["conditions"] = igr.prepareConditions(igr.state.ReconcileErr, generation)
+	status["conditions"] = mergeConditions(
+		status["conditions"],
+		igr.prepareConditions(igr.state.ReconcileErr, generation),
+	)
 
-	return status
+	return status, nil
 }
 
-// getResolvedStatus retrieves the current status while preserving non-condition fields.
-func (igr *instanceGraphReconciler) getResolvedStatus() map[string]interface{} {
-	status := map[string]interface{}{
-		"conditions": []interface{}{},
+// mergeConditions merges the conditions set by the controller into the
+// conditions set by the instance expressions: the conditions of the same type
+// are replaced, the others are kept.
+func mergeConditions(existing interface{}, conditions []interface{}) []interface{} {
+	existingConditions, _ := existing.([]interface{})
+	replaced := map[interface{}]bool{}
+	for _, condition := range conditions {
+		if c, ok := condition.(map[string]interface{}); ok {
+			replaced[c["type"]] = true
+		}
 	}
 
-	if existingStatus, ok := igr.runtime.GetInstance().Object["status"].(map[string]interface{}); ok {
-		// Copy existing status but reset conditions
-		for k, v := range existingStatus {
-			if k != "conditions" {
-				status[k] = v
-			}
+	merged := make([]interface{}, 0, len(existingConditions)+len(conditions))
+	for _, condition := range existingConditions {
+		if c, ok := condition.(map[string]interface{}); ok && replaced[c["type"]] {
+			continue
 		}
+		merged = append(merged, condition)
 	}
-
-	return status
+	return append(merged, conditions...)
 }
 
 // prepareConditions creates the conditions array for the instance status.
@@ -95,14 +113,40 @@ func (igr *instanceGraphReconciler) prepareConditions(
 }
 
 // patchInstanceStatus updates the status subresource of the instance.
+//
+// The status is merge patched, so that the status fields it doesn't contain,
+// e.g written by other controllers, are preserved. A merge patch replaces
+// lists as a whole though: the conditions of the fresh instance are merged
+// with the patched ones, and the patch is conditioned on its resourceVersion,
+// so that the conditions set by other controllers aren't lost.
 func (igr *instanceGraphReconciler) patchInstanceStatus(ctx context.Context, status map[string]interface{}) error {
-	instance := igr.runtime.GetInstance().DeepCopy()
-	instance.Object["status"] = status
-
-	_, err := igr.client.Resource(igr.gvr).
-		Namespace(instance.GetNamespace()).
-		UpdateStatus(ctx, instance, metav1.UpdateOptions{})
+	instance := igr.runtime.GetInstance()
+	rc := igr.client.Resource(igr.gvr).Namespace(instance.GetNamespace())
+	conditions, _ := status["conditions"].([]interface{})
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get fresh copy to merge the conditions with
+		current, err := rc.Get(ctx, instance.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		currentConditions, _, _ := unstructured.NestedSlice(current.Object, "status", "conditions")
 
+		patched := make(map[string]interface{}, len(status))
+		for k, v := range status {
+			patched[k] = v
+		}
+		patched["conditions"] = mergeConditions(currentConditions, conditions)
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"resourceVersion": current.GetResourceVersion()},
+			"status":   patched,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal instance status patch: %w", err)
+		}
+		_, err = rc.Patch(ctx, instance.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update instance status: %w", err)
 	}
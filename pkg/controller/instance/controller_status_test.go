@@ -0,0 +1,86 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/runtime"
+	"github.com/kro-run/kro/pkg/testutil/fakeruntime"
+)
+
+func Test_patchInstanceStatus(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	rc := client.Resource(widgetsGVR).Namespace("default")
+
+	// The instance, as read at the beginning of the reconcile.
+	instance := newWidget(map[string]interface{}{"replicas": int64(1)})
+	rt, err := runtime.NewResourceGraphDefinitionRuntime(
+		fakeruntime.NewResource(instance.Object), map[string]runtime.Resource{}, []string{})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// Another controller sets its own condition in the meantime.
+	live := instance.DeepCopy()
+	live.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Audited", "status": "True"},
+			map[string]interface{}{"type": "InstanceSynced", "status": "False"},
+		},
+		"endpoint": "10.0.0.1",
+	}
+	if _, err := rc.Create(ctx, live, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	igr := newTestReconciler()
+	igr.client = client
+	igr.gvr = widgetsGVR
+	igr.runtime = rt
+	err = igr.patchInstanceStatus(ctx, map[string]interface{}{
+		"state": "ACTIVE",
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "InstanceSynced", "status": "True"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("patchInstanceStatus() error = %v", err)
+	}
+
+	got, err := rc.Get(ctx, "widget", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	conditions, _, _ := unstructured.NestedSlice(got.Object, "status", "conditions")
+	statuses := map[interface{}]interface{}{}
+	for _, condition := range conditions {
+		c := condition.(map[string]interface{})
+		statuses[c["type"]] = c["status"]
+	}
+	if len(conditions) != 2 || statuses["Audited"] != "True" || statuses["InstanceSynced"] != "True" {
+		t.Errorf("conditions = %v, want Audited and the patched InstanceSynced", conditions)
+	}
+	if endpoint, _, _ := unstructured.NestedString(got.Object, "status", "endpoint"); endpoint != "10.0.0.1" {
+		t.Errorf("status.endpoint = %q, want it preserved", endpoint)
+	}
+	if state, _, _ := unstructured.NestedString(got.Object, "status", "state"); state != "ACTIVE" {
+		t.Errorf("status.state = %q, want ACTIVE", state)
+	}
+}
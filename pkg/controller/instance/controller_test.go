@@ -0,0 +1,130 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package instance
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime"
+	"github.com/kro-run/kro/pkg/testutil/fakeruntime"
+)
+
+func Test_storePreviousState(t *testing.T) {
+	newRuntime := func(t *testing.T, expr string) *runtime.ResourceGraphDefinitionRuntime {
+		t.Helper()
+		config := fakeruntime.NewResource(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "config"},
+		})
+		consumer := fakeruntime.NewResource(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "consumer"},
+			"data":       map[string]interface{}{"changed": "${" + expr + "}"},
+		})
+		consumer.Dependencies = []string{"config"}
+		consumer.Variables = []*variable.ResourceField{{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "data.changed",
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"config"},
+		}}
+		rt, err := runtime.NewResourceGraphDefinitionRuntime(
+			fakeruntime.NewResource(map[string]interface{}{}),
+			map[string]runtime.Resource{"config": config, "consumer": consumer},
+			[]string{"config", "consumer"},
+		)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("config", &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "config"},
+			"data":     map[string]interface{}{"version": "1", "payload": "large"},
+		}})
+		return rt
+	}
+	newInstance := func(deleted bool) *unstructured.Unstructured {
+		instance := &unstructured.Unstructured{}
+		instance.SetName("app")
+		instance.SetUID("instance-uid")
+		if deleted {
+			now := metav1.Now()
+			instance.SetDeletionTimestamp(&now)
+		}
+		return instance
+	}
+	const referencing = "has(previous.config.data.version) && previous.config.data.version != config.data.version"
+
+	tests := []struct {
+		name         string
+		expr         string
+		deleted      bool
+		reconcileErr error
+		want         map[string]interface{}
+	}{
+		{
+			name: "referenced fields are kept",
+			expr: referencing,
+			want: map[string]interface{}{
+				"config": map[string]interface{}{
+					"data": map[string]interface{}{"version": "1"},
+				},
+			},
+		},
+		{
+			name: "nothing kept without previous references",
+			expr: "config.data.version",
+		},
+		{
+			name:    "dropped once finalized",
+			expr:    referencing,
+			deleted: true,
+		},
+		{
+			name:         "kept while finalization fails",
+			expr:         referencing,
+			deleted:      true,
+			reconcileErr: errors.New("boom"),
+			want: map[string]interface{}{
+				"config": map[string]interface{}{
+					"data": map[string]interface{}{"version": "1"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{}
+			// A stale entry must never survive the call.
+			c.previousStates.Store("app", previousState{uid: "instance-uid", state: map[string]interface{}{"stale": true}})
+
+			c.storePreviousState("app", newInstance(tt.deleted), newRuntime(t, tt.expr), tt.reconcileErr)
+
+			got := c.loadPreviousState("app", "instance-uid")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("previous state = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
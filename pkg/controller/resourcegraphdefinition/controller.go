@@ -49,6 +49,9 @@ type ResourceGraphDefinitionReconciler struct {
 	metadataLabeler   metadata.Labeler
 	rgBuilder         *graph.Builder
 	dynamicController *dynamiccontroller.DynamicController
+	// clusterInfo is the identity of the cluster, exposed to the expressions
+	// of the instances.
+	clusterInfo map[string]interface{}
 }
 
 func NewResourceGraphDefinitionReconciler(
@@ -58,6 +61,7 @@ func NewResourceGraphDefinitionReconciler(
 	allowCRDDeletion bool,
 	dynamicController *dynamiccontroller.DynamicController,
 	builder *graph.Builder,
+	clusterInfo map[string]interface{},
 ) *ResourceGraphDefinitionReconciler {
 	crdWrapper := clientSet.CRD(kroclient.CRDWrapperConfig{
 		Log: log,
@@ -74,6 +78,7 @@ func NewResourceGraphDefinitionReconciler(
 		dynamicController: dynamicController,
 		metadataLabeler:   metadata.NewKroMetaLabeler("0.2.1", "kro-pod"),
 		rgBuilder:         builder,
+		clusterInfo:       clusterInfo,
 	}
 }
 
@@ -89,6 +89,7 @@ func (r *ResourceGraphDefinitionReconciler) setupMicroController(
 			DefaultRequeueDuration:    3 * time.Second,
 			DeletionGraceTimeDuration: 30 * time.Second,
 			DeletionPolicy:            "Delete",
+			ClusterInfo:               r.clusterInfo,
 		},
 		gvr,
 		processedRGD,
@@ -14,15 +14,21 @@
 package graph
 
 import (
+	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	cel "github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
 	"golang.org/x/exp/maps"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/apiserver/pkg/cel/openapi/resolver"
 	"k8s.io/client-go/discovery"
@@ -38,6 +44,7 @@ import (
 	"github.com/kro-run/kro/pkg/graph/schema"
 	"github.com/kro-run/kro/pkg/graph/variable"
 	"github.com/kro-run/kro/pkg/metadata"
+	"github.com/kro-run/kro/pkg/runtime"
 	"github.com/kro-run/kro/pkg/simpleschema"
 )
 
@@ -243,6 +250,8 @@ func (b *Builder) NewResourceGraphDefinition(originalCR *v1alpha1.ResourceGraphD
 		Instance:         instance,
 		Resources:        resources,
 		TopologicalOrder: topologicalOrder,
+		Name:             originalCR.Name,
+		Version:          rgd.Spec.Schema.APIVersion,
 	}
 	return resourceGraphDefinition, nil
 }
@@ -326,6 +335,27 @@ func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResou
 		return nil, fmt.Errorf("failed to parse includeWhen expressions: %v", err)
 	}
 
+	// 8. Validate the deletion policy
+	if err := validateDeletionPolicy(rgResource.DeletionPolicy); err != nil {
+		return nil, fmt.Errorf("failed to parse deletionPolicy of resource %s: %w", rgResource.ID, err)
+	}
+
+	// 9. Validate the priority
+	if err := validatePriority(rgResource.Priority); err != nil {
+		return nil, fmt.Errorf("failed to parse priority of resource %s: %w", rgResource.ID, err)
+	}
+
+	// 10. Parse the readyWhen messages
+	readyWhenMessages, err := parseReadyWhenMessages(rgResource.ReadyWhenMessages, readyWhen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse readyWhenMessages of resource %s: %w", rgResource.ID, err)
+	}
+
+	// 11. Apply the field settings to the variables
+	if err := applyFieldSettings(resourceVariables, rgResource.Fields); err != nil {
+		return nil, fmt.Errorf("failed to apply the field settings of resource %s: %w", rgResource.ID, err)
+	}
+
 	_, isNamespaced := namespacedResources[gvk.GroupKind()]
 
 	// Note that at this point we don't inject the dependencies into the resource.
@@ -337,12 +367,153 @@ func (b *Builder) buildRGResource(rgResource *v1alpha1.Resource, namespacedResou
 		originalObject:         &unstructured.Unstructured{Object: resourceObject},
 		variables:              resourceVariables,
 		readyWhenExpressions:   readyWhen,
+		readyWhenMessages:      readyWhenMessages,
 		includeWhenExpressions: includeWhen,
+		deletionPolicy:         rgResource.DeletionPolicy,
+		priority:               rgResource.Priority,
+		readinessDependencies:  slices.Clone(rgResource.ReadinessDependencies),
 		namespaced:             isNamespaced,
+		skipReadiness:          rgResource.SkipReadiness,
+		ignoreUpdateErrors:     rgResource.IgnoreUpdateErrors,
 		order:                  order,
 	}, nil
 }
 
+// validateDeletionPolicy makes sure that the given deletion policy is one of
+// the supported policies. Policies embedding expressions are only known when
+// the instance is deleted, their expressions are validated with the other
+// expressions of the resource.
+func validateDeletionPolicy(policy string) error {
+	expressions, err := parser.ExtractExpressions(policy)
+	if err != nil {
+		return err
+	}
+	if policy == "" || len(expressions) > 0 {
+		return nil
+	}
+	switch policy {
+	case runtime.DeletionPolicyDelete, runtime.DeletionPolicyOrphan, runtime.DeletionPolicyRetain:
+		return nil
+	}
+	return fmt.Errorf("unknown deletion policy %q, must be one of %s, %s or %s",
+		policy, runtime.DeletionPolicyDelete, runtime.DeletionPolicyOrphan, runtime.DeletionPolicyRetain)
+}
+
+// validatePriority makes sure that the given priority is an integer. As for
+// the deletion policies, priorities embedding expressions are validated with
+// the other expressions of the resource.
+func validatePriority(priority string) error {
+	expressions, err := parser.ExtractExpressions(priority)
+	if err != nil {
+		return err
+	}
+	if priority == "" || len(expressions) > 0 {
+		return nil
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(priority), 10, 64); err != nil {
+		return fmt.Errorf("priority %q is not an integer", priority)
+	}
+	return nil
+}
+
+// parseReadyWhenMessages returns the given readyWhen messages keyed by the
+// expression of their readyWhen condition, e.g "vpc.status.ready" for
+// "${vpc.status.ready}". Every message must match a readyWhen condition.
+func parseReadyWhenMessages(messages map[string]string, readyWhen []string) (map[string]string, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	conditions := maps.Keys(messages)
+	slices.Sort(conditions)
+
+	parsed := make(map[string]string, len(messages))
+	for _, condition := range conditions {
+		expressions, err := parser.ParseConditionExpressions([]string{condition})
+		if err != nil {
+			return nil, fmt.Errorf("invalid readyWhen condition %q: %w", condition, err)
+		}
+		if !slices.Contains(readyWhen, expressions[0]) {
+			return nil, fmt.Errorf("found message for unknown readyWhen condition %q", condition)
+		}
+		if _, err := parser.ExtractExpressions(messages[condition]); err != nil {
+			return nil, fmt.Errorf("invalid message for readyWhen condition %q: %w", condition, err)
+		}
+		parsed[expressions[0]] = messages[condition]
+	}
+	return parsed, nil
+}
+
+// patchTypes maps the patch types of the field settings to the patch types
+// applied by the resolver.
+var patchTypes = map[string]k8stypes.PatchType{
+	"json":  k8stypes.JSONPatchType,
+	"merge": k8stypes.MergePatchType,
+}
+
+// applyFieldSettings applies the given field settings to the variables of the
+// resource. Every setting must match a field holding expressions.
+func applyFieldSettings(variables []*variable.ResourceField, fields []v1alpha1.Field) error {
+	for _, field := range fields {
+		i := slices.IndexFunc(variables, func(v *variable.ResourceField) bool {
+			return v.Path == field.Path
+		})
+		if i < 0 {
+			return fmt.Errorf("field %s doesn't hold any expression", field.Path)
+		}
+		resourceVariable := variables[i]
+
+		if len(field.RequiredFeatures) > 0 {
+			resourceVariable.RequiredFeatures = slices.Clone(field.RequiredFeatures)
+		}
+
+		if field.PatchType != "" {
+			patchType, ok := patchTypes[field.PatchType]
+			if !ok {
+				return fmt.Errorf("unknown patch type %q for field %s", field.PatchType, field.Path)
+			}
+			if !resourceVariable.StandaloneExpression {
+				return fmt.Errorf("patch field %s must be a standalone expression", field.Path)
+			}
+			resourceVariable.PatchType = patchType
+		}
+	}
+	return nil
+}
+
+// featureEnvironment returns the given environment extended with the
+// declarations gated behind the required feature flags. Whether the flags are
+// enabled is only known to the runtime, so they're assumed to be.
+func featureEnvironment(env *cel.Env, requiredFeatures []string) (*cel.Env, error) {
+	if len(requiredFeatures) == 0 {
+		return env, nil
+	}
+	enabled := make(map[string]bool, len(requiredFeatures))
+	for _, flag := range requiredFeatures {
+		enabled[flag] = true
+	}
+	declarations, err := krocel.FeatureOptions(enabled, requiredFeatures)
+	if err != nil {
+		return nil, err
+	}
+	return env.Extend(declarations...)
+}
+
+// validateTemplateExpressions validates the expressions embedded in the given
+// template, e.g the deletion policy of a resource, against the resources of
+// the resource graph definition.
+func validateTemplateExpressions(env *cel.Env, template string, resourceNames []string) error {
+	expressions, err := parser.ExtractExpressions(template)
+	if err != nil {
+		return err
+	}
+	for _, expression := range expressions {
+		if _, _, err := extractDependencies(env, expression, resourceNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // buildDependencyGraph builds the dependency graph between the resources in the
 // resource graph definition. The dependency graph is an directed acyclic graph that represents
 // the relationships between the resources in the resource graph definition. The graph is used
@@ -360,8 +531,12 @@ func (b *Builder) buildDependencyGraph(
 ) {
 
 	resourceNames := maps.Keys(resources)
-	// We also want to allow users to refer to the instance spec in their expressions.
-	resourceNames = append(resourceNames, "schema")
+	// We also want to allow users to refer to the instance in their expressions.
+	resourceNames = append(resourceNames, instanceVariableNames...)
+	resourceNames = append(resourceNames, contextVariableNames...)
+	// The resource expressions can also refer to the readiness of their
+	// dependencies.
+	resourceNames = append(resourceNames, readyVariableName)
 
 	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
 	if err != nil {
@@ -378,16 +553,20 @@ func (b *Builder) buildDependencyGraph(
 
 	for _, resource := range resources {
 		for _, resourceVariable := range resource.variables {
+			variableEnv, err := featureEnvironment(env, resourceVariable.RequiredFeatures)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create CEL environment for field %s of resource %s: %w", resourceVariable.Path, resource.id, err)
+			}
 			for _, expression := range resourceVariable.Expressions {
 				// We need to inspect the expression to understand how it relates to the
 				// resources defined in the resource graph definition.
-				err := validateCELExpressionContext(env, expression, resourceNames)
+				err := validateCELExpressionContext(variableEnv, expression, resourceNames)
 				if err != nil {
 					return nil, fmt.Errorf("failed to validate expression context: %w", err)
 				}
 
 				// We need to extract the dependencies from the expression.
-				resourceDependencies, isStatic, err := extractDependencies(env, expression, resourceNames)
+				resourceDependencies, isStatic, err := extractDependencies(variableEnv, expression, resourceNames)
 				if err != nil {
 					return nil, fmt.Errorf("failed to extract dependencies: %w", err)
 				}
@@ -408,6 +587,26 @@ func (b *Builder) buildDependencyGraph(
 				}
 			}
 		}
+
+		// The readiness dependencies need to be processed first, they're
+		// graph dependencies, but don't resolve the resource variables.
+		for _, dependency := range resource.readinessDependencies {
+			if _, ok := resources[dependency]; !ok || dependency == resource.id {
+				return nil, fmt.Errorf("resource %s waits for the readiness of unknown resource %s", resource.id, dependency)
+			}
+		}
+		if err := directedAcyclicGraph.AddDependencies(resource.id, resource.readinessDependencies); err != nil {
+			return nil, err
+		}
+
+		// The deletion policy and priority expressions are evaluated against
+		// the current state of the runtime, they don't add dependencies.
+		if err := validateTemplateExpressions(env, resource.deletionPolicy, resourceNames); err != nil {
+			return nil, fmt.Errorf("failed to validate deletion policy of resource %s: %w", resource.id, err)
+		}
+		if err := validateTemplateExpressions(env, resource.priority, resourceNames); err != nil {
+			return nil, fmt.Errorf("failed to validate priority of resource %s: %w", resource.id, err)
+		}
 	}
 
 	return directedAcyclicGraph, nil
@@ -470,10 +669,15 @@ func (b *Builder) buildInstanceResource(
 	}
 
 	resourceNames := maps.Keys(resources)
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
+	env, err := krocel.DefaultEnvironment(
+		krocel.WithResourceIDs(resourceNames),
+		krocel.WithResourceLists([]string{resourcesVariableName}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
+	// Status expressions can also refer to the list of all the resources.
+	resourceNames = append(resourceNames, resourcesVariableName)
 
 	// The instance resource has a set of variables that need to be resolved.
 	instance := &Resource{
@@ -497,6 +701,15 @@ func (b *Builder) buildInstanceResource(
 		if isStatic {
 			return nil, fmt.Errorf("instance status field must refer to a resource: %s", statusVariable.Path)
 		}
+		if i := slices.Index(instanceDependencies, resourcesVariableName); i >= 0 {
+			// The list of resources depends on every resource.
+			instanceDependencies = slices.Delete(instanceDependencies, i, i+1)
+			for _, id := range sortedResourceIDs(resources) {
+				if !slices.Contains(instanceDependencies, id) {
+					instanceDependencies = append(instanceDependencies, id)
+				}
+			}
+		}
 		instance.addDependencies(instanceDependencies...)
 
 		instanceStatusVariables = append(instanceStatusVariables, &variable.ResourceField{
@@ -556,11 +769,14 @@ func buildStatusSchema(
 
 	// Inspection of the CEL expressions to infer the types of the status fields.
 	resourceNames := maps.Keys(resources)
-
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
+	env, err := krocel.DefaultEnvironment(
+		krocel.WithResourceIDs(resourceNames),
+		krocel.WithResourceLists([]string{resourcesVariableName}),
+	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create CEL environment: %w", err)
 	}
+	resourceNames = append(resourceNames, resourcesVariableName)
 
 	// statusStructureParts := make([]schema.FieldDescriptor, 0, len(extracted))
 	statusDryRunResults := make(map[string][]ref.Val, len(fieldDescriptors))
@@ -594,6 +810,96 @@ func buildStatusSchema(
 	return statusSchema, fieldDescriptors, nil
 }
 
+// instanceVariableNames are the CEL variables referring to the instance
+// itself. "schema" exposes the whole instance object while "instance" only
+// exposes its identity, labels and annotations. "resourceGroup" exposes the
+// identity of the resource graph definition. Expressions that only refer to
+// these variables are static.
+var instanceVariableNames = []string{"schema", "instance", "resourceGroup"}
+
+// contextVariableNames are the CEL variables provided by the controller at
+// runtime, next to the instance variables. "kroCluster" exposes the identity of
+// the cluster the instance is reconciled in, "reconcileContext" the
+// contextual information of the reconcile, e.g its id, and "previous" the
+// resolved resources of the previous reconcile, keyed by resource id. Their
+// content is only known at runtime, they are unknown to the dry-runs. Like
+// the instance variables, they don't make an expression dynamic.
+var contextVariableNames = []string{"kroCluster", "reconcileContext", "previous"}
+
+// readyVariableName is the CEL variable exposing the readiness of the
+// dependencies of the resource expressions, keyed by resource id. Reading the
+// readiness of a resource, e.g "${ready.database ? 3 : 1}", makes it a
+// dependency of the expression. The readiness is only known at runtime, it's
+// unknown to the dry-runs.
+const readyVariableName = "ready"
+
+// readinessTarget returns the id of the resource whose readiness is read by
+// the given "ready" path, e.g "database" for "ready.database.x". It's empty
+// if the path doesn't select a resource, e.g `ready["database"]`.
+func readinessTarget(path string) string {
+	target, ok := strings.CutPrefix(path, readyVariableName+".")
+	if !ok {
+		return ""
+	}
+	if i := strings.IndexAny(target, ".["); i >= 0 {
+		target = target[:i]
+	}
+	return target
+}
+
+// isContextVariable returns true if the given name is an instance or a
+// context variable.
+func isContextVariable(name string) bool {
+	return slices.Contains(instanceVariableNames, name) || slices.Contains(contextVariableNames, name)
+}
+
+// resourcesVariableName is the CEL variable exposing the list of all the
+// resources to the instance status expressions.
+// e.g "${countWhere(resources, r, r.kind == 'Pod')}"
+const resourcesVariableName = "resources"
+
+// sortedResourceIDs returns the ids of the given resources, sorted.
+func sortedResourceIDs(resources map[string]*Resource) []string {
+	ids := maps.Keys(resources)
+	slices.Sort(ids)
+	return ids
+}
+
+// emulatedInstanceMetadata returns the emulated "instance" variable used to
+// dry-run expressions. The identity of the instance is only known at runtime
+// and labels and annotations are user provided, so we can only emulate them
+// as empty strings and maps.
+func emulatedInstanceMetadata() *Resource {
+	return &Resource{
+		emulatedObject: &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "",
+				"kind":       "",
+				"metadata": map[string]interface{}{
+					"name":        "",
+					"namespace":   "",
+					"uid":         "",
+					"labels":      map[string]interface{}{},
+					"annotations": map[string]interface{}{},
+				},
+			},
+		},
+	}
+}
+
+// emulatedResourceGroup returns the emulated "resourceGroup" variable used to
+// dry-run expressions.
+func emulatedResourceGroup() *Resource {
+	return &Resource{
+		emulatedObject: &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"name":    "",
+				"version": "",
+			},
+		},
+	}
+}
+
 // validateCELExpressionContext validates the given CEL expression in the context
 // of the resources defined in the resource graph definition.
 func validateCELExpressionContext(env *cel.Env, expression string, resources []string) error {
@@ -614,6 +920,43 @@ func validateCELExpressionContext(env *cel.Env, expression string, resources []s
 	return nil
 }
 
+// validateReadyWhenReferences makes sure that the readyWhen expressions of the
+// resource, and the expressions embedded in their messages, only reference the
+// resource itself. They are evaluated with the resource top-level fields as
+// only context, so references to other resources or to the instance can never
+// be satisfied.
+func validateReadyWhenReferences(env *cel.Env, resource *Resource, resourceNames []string) error {
+	expressions := slices.Clone(resource.readyWhenExpressions)
+	for _, message := range resource.readyWhenMessages {
+		// The messages are validated when the resource is built.
+		messageExpressions, _ := parser.ExtractExpressions(message)
+		expressions = append(expressions, messageExpressions...)
+	}
+
+	inspector := ast.NewInspectorWithEnv(env, resourceNames, nil)
+	for _, expression := range expressions {
+		inspectionResult, err := inspector.Inspect(expression)
+		if err != nil {
+			return fmt.Errorf("failed to inspect readyWhen expression %s: %w", expression, err)
+		}
+		for _, dependency := range inspectionResult.ResourceDependencies {
+			if dependency.ID != resource.id {
+				return fmt.Errorf(
+					"readyWhen expression %s of resource %s references %s: readyWhen expressions can only reference the resource itself",
+					expression, resource.id, dependency.ID,
+				)
+			}
+		}
+		if len(inspectionResult.UnknownResources) > 0 {
+			return fmt.Errorf(
+				"readyWhen expression %s of resource %s references unknown resource %s",
+				expression, resource.id, inspectionResult.UnknownResources[0].ID,
+			)
+		}
+	}
+	return nil
+}
+
 // dryRunExpression executes the given CEL expression in the context of a set
 // of emulated resources. We could've called this function evaluateExpression
 // but we chose to call it dryRunExpression to indicate that we are not actually
@@ -621,27 +964,77 @@ func validateCELExpressionContext(env *cel.Env, expression string, resources []s
 func dryRunExpression(env *cel.Env, expression string, resources map[string]*Resource) (ref.Val, error) {
 	ast, issues := env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
+		if err := krocel.UnknownFunctionError(env, expression); err != nil {
+			return nil, fmt.Errorf("failed to compile expression: %w", err)
+		}
 		return nil, fmt.Errorf("failed to compile expression: %w", issues.Err())
 	}
 
 	// TODO(a-hilaly): thinking about a creating a library to hide this...
-	program, err := env.Program(ast)
+	program, err := env.Program(ast, cel.EvalOptions(cel.OptPartialEval))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create program: %w", err)
 	}
 
 	context := map[string]interface{}{}
-	for resourceName, resource := range resources {
-		context[resourceName] = resource.emulatedObject.Object
+	list := make([]interface{}, 0, len(resources))
+	for _, resourceName := range sortedResourceIDs(resources) {
+		context[resourceName] = resources[resourceName].emulatedObject.Object
+		list = append(list, resources[resourceName].emulatedObject.Object)
+	}
+	// "resources" is a reserved word, it can't collide with a resource id.
+	context[resourcesVariableName] = list
+
+	// The context variables and the readiness are only known at runtime.
+	unknowns := []*interpreter.AttributePattern{cel.AttributePattern(readyVariableName)}
+	for _, name := range contextVariableNames {
+		unknowns = append(unknowns, cel.AttributePattern(name))
+	}
+	activation, err := cel.PartialVars(context, unknowns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create activation: %w", err)
 	}
 
-	output, _, err := program.Eval(context)
+	output, _, err := program.Eval(activation)
 	if err != nil {
+		// kro library functions validate their arguments (e.g a CIDR), which
+		// emulated values are unlikely to satisfy. Fall back to the zero value
+		// of the expression output type.
+		if errors.Is(err, krocel.ErrInvalidArgument) {
+			if zero, ok := zeroValue(ast.OutputType()); ok {
+				return zero, nil
+			}
+		}
 		return nil, fmt.Errorf("failed to evaluate expression: %w", err)
 	}
+	if types.IsUnknown(output) {
+		// The expression depends on context variables, fall back to the zero
+		// value of its output type when known.
+		if zero, ok := zeroValue(ast.OutputType()); ok {
+			return zero, nil
+		}
+	}
 	return output, nil
 }
 
+// zeroValue returns the zero value of the given primitive CEL type.
+func zeroValue(t *cel.Type) (ref.Val, bool) {
+	switch {
+	case t.IsExactType(cel.StringType):
+		return types.String(""), true
+	case t.IsExactType(cel.BoolType):
+		return types.False, true
+	case t.IsExactType(cel.IntType):
+		return types.Int(0), true
+	case t.IsExactType(cel.UintType):
+		return types.Uint(0), true
+	case t.IsExactType(cel.DoubleType):
+		return types.Double(0), true
+	default:
+		return nil, false
+	}
+}
+
 // extractDependencies extracts the dependencies from the given CEL expression.
 // It returns a list of dependencies and a boolea indicating if the expression
 // is static or not.
@@ -659,15 +1052,30 @@ func extractDependencies(env *cel.Env, expression string, resourceNames []string
 	isStatic := true
 	dependencies := make([]string, 0)
 	for _, resource := range inspectionResult.ResourceDependencies {
-		if resource.ID != "schema" && !slices.Contains(dependencies, resource.ID) {
+		id := resource.ID
+		if id == readyVariableName {
+			// The readiness map only holds the dependencies of the
+			// expression, the resources it reads are dependencies too.
+			id = readinessTarget(resource.Path)
+			if id == "" {
+				continue
+			}
+			if !slices.Contains(resourceNames, id) || isContextVariable(id) || id == readyVariableName {
+				return nil, false, fmt.Errorf("expression reads the readiness of unknown resource %s", id)
+			}
+		}
+		if !isContextVariable(id) && !slices.Contains(dependencies, id) {
 			isStatic = false
-			dependencies = append(dependencies, resource.ID)
+			dependencies = append(dependencies, id)
 		}
 	}
 	if len(inspectionResult.UnknownResources) > 0 {
 		return nil, false, fmt.Errorf("found unknown resources in CEL expression: [%v]", inspectionResult.UnknownResources)
 	}
 	if len(inspectionResult.UnknownFunctions) > 0 {
+		if err := krocel.UnknownFunctionError(env, expression); err != nil {
+			return nil, false, fmt.Errorf("found unknown functions in CEL expression: %w", err)
+		}
 		return nil, false, fmt.Errorf("found unknown functions in CEL expression: [%v]", inspectionResult.UnknownFunctions)
 	}
 	return dependencies, isStatic, nil
@@ -683,11 +1091,15 @@ func extractDependencies(env *cel.Env, expression string, resourceNames []string
 // on.
 func validateResourceCELExpressions(resources map[string]*Resource, instance *Resource) error {
 	resourceNames := maps.Keys(resources)
-	// We also want to allow users to refer to the instance spec in their expressions.
-	resourceNames = append(resourceNames, "schema")
-	conditionFieldNames := []string{"schema"}
-
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resourceNames))
+	// We also want to allow users to refer to the instance in their expressions.
+	resourceNames = append(resourceNames, instanceVariableNames...)
+	resourceNames = append(resourceNames, contextVariableNames...)
+	conditionFieldNames := append(slices.Clone(instanceVariableNames), contextVariableNames...)
+
+	// Unlike the includeWhen expressions, the resource expressions can refer
+	// to the readiness of their dependencies.
+	variableNames := append(slices.Clone(resourceNames), readyVariableName)
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(variableNames))
 	if err != nil {
 		return fmt.Errorf("failed to create CEL environment: %w", err)
 	}
@@ -699,9 +1111,16 @@ func validateResourceCELExpressions(resources map[string]*Resource, instance *Re
 	}
 
 	for _, resource := range resources {
+		if err := validateReadyWhenReferences(env, resource, resourceNames); err != nil {
+			return err
+		}
 		for _, resourceVariable := range resource.variables {
+			variableEnv, err := featureEnvironment(env, resourceVariable.RequiredFeatures)
+			if err != nil {
+				return fmt.Errorf("failed to create CEL environment for field %s of resource %s: %w", resourceVariable.Path, resource.id, err)
+			}
 			for _, expression := range resourceVariable.Expressions {
-				err := validateCELExpressionContext(env, expression, resourceNames)
+				err := validateCELExpressionContext(variableEnv, expression, variableNames)
 				if err != nil {
 					return fmt.Errorf("failed to validate expression context: '%s' %w", expression, err)
 				}
@@ -720,8 +1139,10 @@ func validateResourceCELExpressions(resources map[string]*Resource, instance *Re
 						Object: instanceEmulatedCopy.Object,
 					},
 				}
+				context["instance"] = emulatedInstanceMetadata()
+				context["resourceGroup"] = emulatedResourceGroup()
 
-				_, err = dryRunExpression(env, expression, context)
+				_, err = dryRunExpression(variableEnv, expression, context)
 				if err != nil {
 					return fmt.Errorf("failed to dry-run expression %s: %w", expression, err)
 				}
@@ -786,6 +1207,8 @@ func validateResourceCELExpressions(resources map[string]*Resource, instance *Re
 						Object: instanceEmulatedCopy.Object,
 					},
 				}
+				context["instance"] = emulatedInstanceMetadata()
+				context["resourceGroup"] = emulatedResourceGroup()
 
 				output, err := dryRunExpression(instanceEnv, includeWhenExpression, context)
 				if err != nil {
@@ -14,18 +14,36 @@
 package graph
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 
+	krocel "github.com/kro-run/kro/pkg/cel"
 	"github.com/kro-run/kro/pkg/graph/emulator"
 	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime"
 	"github.com/kro-run/kro/pkg/testutil/generator"
 	"github.com/kro-run/kro/pkg/testutil/k8s"
 )
 
+func init() {
+	krocel.RegisterFeature("builder-test-shout", cel.Function("shout",
+		cel.Overload("shout_string", []*cel.Type{cel.StringType}, cel.StringType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val {
+				return types.String(strings.ToUpper(string(v.(types.String))))
+			}),
+		),
+	))
+}
+
 func TestGraphBuilder_Validation(t *testing.T) {
 	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
 	builder := &Builder{
@@ -120,6 +138,151 @@ func TestGraphBuilder_Validation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "failed to parse readyWhen expressions",
 		},
+		{
+			name: "readyWhen referencing the resource itself",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+					"spec": map[string]interface{}{
+						"vpcID": "${vpc.status.vpcID}",
+					},
+				}, []string{"${subnet.status.state == 'available'}"}, nil),
+			},
+			wantErr: false,
+			errMsg:  "",
+		},
+		{
+			name: "readyWhen referencing another resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+					"spec": map[string]interface{}{
+						"vpcID": "${vpc.status.vpcID}",
+					},
+				}, []string{"${vpc.status.state == 'available'}"}, nil),
+			},
+			wantErr: true,
+			errMsg:  "readyWhen expressions can only reference the resource itself",
+		},
+		{
+			name: "readyWhen referencing the instance",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+					"spec": map[string]interface{}{
+						"vpcID": "${vpc.status.vpcID}",
+					},
+				}, []string{"${schema.spec.name != ''}"}, nil),
+			},
+			wantErr: true,
+			errMsg:  "readyWhen expressions can only reference the resource itself",
+		},
+		{
+			name: "readyWhen referencing an unknown resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+					"spec": map[string]interface{}{
+						"vpcID": "${vpc.status.vpcID}",
+					},
+				}, []string{"${gateway.status.ready}"}, nil),
+			},
+			wantErr: true,
+			errMsg:  "references unknown resource gateway",
+		},
+		{
+			name: "unknown function with a near-miss name",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${checksun(schema.spec.name)}",
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "unknown function 'checksun', did you mean 'checksum'?",
+		},
 		{
 			name: "invalid CEL syntax in includeWhen expression",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
@@ -377,40 +540,8 @@ func TestGraphBuilder_Validation(t *testing.T) {
 			},
 			wantErr: false,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rgd := generator.NewResourceGraphDefinition("test-group", tt.resourceGraphDefinitionOpts...)
-			_, err := builder.NewResourceGraphDefinition(rgd)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errMsg)
-				return
-			}
-			require.NoError(t, err)
-		})
-	}
-}
-
-func TestGraphBuilder_DependencyValidation(t *testing.T) {
-	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
-	builder := &Builder{
-		schemaResolver:   fakeResolver,
-		discoveryClient:  fakeDiscovery,
-		resourceEmulator: emulator.NewEmulator(),
-	}
-
-	tests := []struct {
-		name                        string
-		resourceGraphDefinitionOpts []generator.ResourceGraphDefinitionOption
-		wantErr                     bool
-		errMsg                      string
-		validateDeps                func(*testing.T, *Graph)
-	}{
 		{
-			name: "complex eks setup dependencies",
+			name: "unknown deletion policy",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -419,101 +550,86 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
-				// First layer: Base resources with no dependencies
 				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
 					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "testvpc",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+						"name": "test-vpc",
 					},
 				}, nil, nil),
-				generator.WithResource("clusterpolicy", map[string]interface{}{
-					"apiVersion": "iam.services.k8s.aws/v1alpha1",
-					"kind":       "Policy",
-					"metadata": map[string]interface{}{
-						"name": "clusterpolicy",
-					},
-					"spec": map[string]interface{}{
-						"name":     "testclusterpolicy",
-						"document": "{}",
+				generator.WithDeletionPolicy("vpc", "Keep"),
+			},
+			wantErr: true,
+			errMsg:  "unknown deletion policy",
+		},
+		{
+			name: "deletion policy expression referencing an unknown resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
 					},
-				}, nil, nil),
-				// Second layer: Resources depending on first layer
-				generator.WithResource("clusterrole", map[string]interface{}{
-					"apiVersion": "iam.services.k8s.aws/v1alpha1",
-					"kind":       "Role",
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "clusterrole",
-					},
-					"spec": map[string]interface{}{
-						"name":                     "${clusterpolicy.status.policyID}role",
-						"assumeRolePolicyDocument": "{}",
+						"name": "test-vpc",
 					},
 				}, nil, nil),
-				generator.WithResource("subnet1", map[string]interface{}{
+				generator.WithDeletionPolicy("vpc", "${database.spec.retain ? 'Retain' : 'Delete'}"),
+			},
+			wantErr: true,
+			errMsg:  "failed to validate deletion policy of resource vpc",
+		},
+		{
+			name: "priority that isn't an integer",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "Subnet",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "subnet1",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.1.0/24",
-						"vpcID":     "${vpc.status.vpcID}",
+						"name": "test-vpc",
 					},
 				}, nil, nil),
-				generator.WithResource("subnet2", map[string]interface{}{
+				generator.WithPriority("vpc", "high"),
+			},
+			wantErr: true,
+			errMsg:  "is not an integer",
+		},
+		{
+			name: "readiness dependency on an unknown resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "Subnet",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "subnet2",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.2.0/24",
-						"vpcID":     "${vpc.status.vpcID}",
+						"name": "test-vpc",
 					},
 				}, nil, nil),
-				// Third layer: EKS Cluster depending on roles and subnets
-				generator.WithResource("cluster", map[string]interface{}{
-					"apiVersion": "eks.services.k8s.aws/v1alpha1",
-					"kind":       "Cluster",
-					"metadata": map[string]interface{}{
-						"name": "cluster",
-					},
-					"spec": map[string]interface{}{
-						"name":    "testcluster",
-						"roleARN": "${clusterrole.status.roleID}",
-						"resourcesVPCConfig": map[string]interface{}{
-							"subnetIDs": []interface{}{
-								"${subnet1.status.subnetID}",
-								"${subnet2.status.subnetID}",
-							},
-						},
-					},
-				}, nil, nil)},
-			validateDeps: func(t *testing.T, g *Graph) {
-				// Validate dependencies
-				assert.Empty(t, g.Resources["vpc"].GetDependencies())
-				assert.Empty(t, g.Resources["clusterpolicy"].GetDependencies())
-
-				assert.Equal(t, []string{"vpc"}, g.Resources["subnet1"].GetDependencies())
-				assert.Equal(t, []string{"vpc"}, g.Resources["subnet2"].GetDependencies())
-				assert.Equal(t, []string{"clusterpolicy"}, g.Resources["clusterrole"].GetDependencies())
-
-				clusterDeps := g.Resources["cluster"].GetDependencies()
-				assert.Len(t, clusterDeps, 3)
-				assert.Contains(t, clusterDeps, "clusterrole")
-				assert.Contains(t, clusterDeps, "subnet1")
-				assert.Contains(t, clusterDeps, "subnet2")
-
-				// Validate topological order
-				assert.Equal(t, []string{"vpc", "clusterpolicy", "clusterrole", "subnet1", "subnet2", "cluster"}, g.TopologicalOrder)
+				generator.WithReadinessDependencies("vpc", "database"),
 			},
+			wantErr: true,
+			errMsg:  "resource vpc waits for the readiness of unknown resource database",
 		},
 		{
-			name: "missing dependency",
+			name: "readiness dependency cycle",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -522,23 +638,27 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${subnet.metadata.name}",
+					},
+				}, nil, nil),
 				generator.WithResource("subnet", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
 					"kind":       "Subnet",
 					"metadata": map[string]interface{}{
-						"name": "subnet",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.0.0/24",
-						"vpcID":     "${missingvpc.status.vpcID}",
+						"name": "test-subnet",
 					},
 				}, nil, nil),
+				generator.WithReadinessDependencies("subnet", "vpc"),
 			},
 			wantErr: true,
-			errMsg:  "undeclared reference to 'missingvpc'",
+			errMsg:  "cycle",
 		},
 		{
-			name: "cyclic dependency",
+			name: "readyWhen message for an unknown condition",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -547,34 +667,20 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
-				generator.WithResource("role1", map[string]interface{}{
-					"apiVersion": "iam.services.k8s.aws/v1alpha1",
-					"kind":       "Role",
-					"metadata": map[string]interface{}{
-						"name": "${role2.metadata.name}1",
-					},
-					"spec": map[string]interface{}{
-						"name":                     "testrole1",
-						"assumeRolePolicyDocument": "{}",
-					},
-				}, nil, nil),
-				generator.WithResource("role2", map[string]interface{}{
-					"apiVersion": "iam.services.k8s.aws/v1alpha1",
-					"kind":       "Role",
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "${role1.metadata.name}2",
-					},
-					"spec": map[string]interface{}{
-						"name":                     "testrole2",
-						"assumeRolePolicyDocument": "{}",
+						"name": "test-vpc",
 					},
-				}, nil, nil),
+				}, []string{"${vpc.status.state == 'available'}"}, nil),
+				generator.WithReadyWhenMessage("vpc", "${vpc.status.state == 'ready'}", "vpc isn't ready"),
 			},
 			wantErr: true,
-			errMsg:  "graph contains a cycle",
+			errMsg:  "found message for unknown readyWhen condition",
 		},
 		{
-			name: "independent pods",
+			name: "readyWhen message referencing the instance",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -583,79 +689,20 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
-				generator.WithResource("pod1", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "pod1",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx1",
-								"image": "nginx:latest",
-							},
-						},
-					},
-				}, nil, nil),
-				generator.WithResource("pod2", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "pod2",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx2",
-								"image": "nginx:latest",
-							},
-						},
-					},
-				}, nil, nil),
-				generator.WithResource("pod3", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "pod3",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx3",
-								"image": "nginx:latest",
-							},
-						},
-					},
-				}, nil, nil),
-				generator.WithResource("pod4", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "pod4",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx4",
-								"image": "nginx:latest",
-							},
-						},
+						"name": "test-vpc",
 					},
-				}, nil, nil),
-			},
-			validateDeps: func(t *testing.T, g *Graph) {
-				assert.Len(t, g.Resources, 4)
-				assert.Empty(t, g.Resources["pod1"].GetDependencies())
-				assert.Empty(t, g.Resources["pod2"].GetDependencies())
-				assert.Empty(t, g.Resources["pod3"].GetDependencies())
-				assert.Empty(t, g.Resources["pod4"].GetDependencies())
-				// Order doesn't matter as they're all independent
-				assert.Len(t, g.TopologicalOrder, 4)
+				}, []string{"${vpc.status.state == 'available'}"}, nil),
+				generator.WithReadyWhenMessage("vpc", "${vpc.status.state == 'available'}", "${schema.spec.name} isn't ready"),
 			},
+			wantErr: true,
+			errMsg:  "readyWhen expressions can only reference the resource itself",
 		},
 		{
-			name: "cyclic pod dependencies",
+			name: "patch field without expression",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -664,72 +711,48 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
-				generator.WithResource("pod1", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "${pod4.status.podIP}app1",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx1",
-								"image": "nginx:latest",
-							},
-						},
-					},
-				}, nil, nil),
-				generator.WithResource("pod2", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "${pod1.status.podIP}app2",
+						"name": "test-vpc",
 					},
 					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx2",
-								"image": "nginx:latest",
-							},
-						},
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
 					},
 				}, nil, nil),
-				generator.WithResource("pod3", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "${pod2.status.podIP}app3",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx3",
-								"image": "nginx:latest",
-							},
-						},
+				generator.WithPatchField("vpc", "spec.tags", "json"),
+			},
+			wantErr: true,
+			errMsg:  "field spec.tags doesn't hold any expression",
+		},
+		{
+			name: "patch field that isn't a standalone expression",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
 					},
-				}, nil, nil),
-				generator.WithResource("pod4", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "${pod3.status.podIP}app4",
+						"name": "test-vpc",
 					},
 					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "nginx4",
-								"image": "nginx:latest",
-							},
-						},
+						"cidrBlocks": []interface{}{"10.0.${schema.spec.name}.0/16"},
 					},
 				}, nil, nil),
+				generator.WithPatchField("vpc", "spec.cidrBlocks[0]", "merge"),
 			},
 			wantErr: true,
-			errMsg:  "graph contains a cycle",
+			errMsg:  "patch field spec.cidrBlocks[0] must be a standalone expression",
 		},
 		{
-			name: "shared infrastructure dependencies",
+			name: "function gated behind a feature flag",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
@@ -738,259 +761,209 @@ func TestGraphBuilder_DependencyValidation(t *testing.T) {
 					},
 					nil,
 				),
-				// Base infrastructure
 				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
 					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "vpc",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+						"name": "${shout(schema.spec.name)}",
 					},
 				}, nil, nil),
-				generator.WithResource("subnet1", map[string]interface{}{
-					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "Subnet",
-					"metadata": map[string]interface{}{
-						"name": "subnet1",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.1.0/24",
-						"vpcID":     "${vpc.status.vpcID}",
+			},
+			wantErr: true,
+			errMsg:  "unknown function",
+		},
+		{
+			name: "unknown feature flag",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
 					},
-				}, nil, nil),
-				generator.WithResource("subnet2", map[string]interface{}{
+					nil,
+				),
+				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "Subnet",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "subnet2",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.2.0/24",
-						"vpcID":     "${vpc.status.vpcID}",
+						"name": "${shout(schema.spec.name)}",
 					},
 				}, nil, nil),
-				generator.WithResource("subnet3", map[string]interface{}{
-					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "Subnet",
-					"metadata": map[string]interface{}{
-						"name": "subnet3",
-					},
-					"spec": map[string]interface{}{
-						"cidrBlock": "10.0.3.0/24",
-						"vpcID":     "${vpc.status.vpcID}",
+				generator.WithRequiredFeatures("vpc", "metadata.name", "builder-test-unknown"),
+			},
+			wantErr: true,
+			errMsg:  "unknown feature flag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("test-group", tt.resourceGraphDefinitionOpts...)
+			_, err := builder.NewResourceGraphDefinition(rgd)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGraphBuilder_DependencyValidation(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name                        string
+		resourceGraphDefinitionOpts []generator.ResourceGraphDefinitionOption
+		wantErr                     bool
+		errMsg                      string
+		validateDeps                func(*testing.T, *Graph)
+	}{
+		{
+			name: "complex eks setup dependencies",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
 					},
-				}, nil, nil),
-				generator.WithResource("secgroup", map[string]interface{}{
+					nil,
+				),
+				// First layer: Base resources with no dependencies
+				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
-					"kind":       "SecurityGroup",
+					"kind":       "VPC",
 					"metadata": map[string]interface{}{
-						"name": "secgroup",
+						"name": "testvpc",
 					},
 					"spec": map[string]interface{}{
-						"vpcID": "${vpc.status.vpcID}",
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
 					},
 				}, nil, nil),
-				generator.WithResource("policy", map[string]interface{}{
+				generator.WithResource("clusterpolicy", map[string]interface{}{
 					"apiVersion": "iam.services.k8s.aws/v1alpha1",
 					"kind":       "Policy",
 					"metadata": map[string]interface{}{
-						"name": "policy",
+						"name": "clusterpolicy",
 					},
 					"spec": map[string]interface{}{
+						"name":     "testclusterpolicy",
 						"document": "{}",
 					},
 				}, nil, nil),
-				generator.WithResource("role", map[string]interface{}{
+				// Second layer: Resources depending on first layer
+				generator.WithResource("clusterrole", map[string]interface{}{
 					"apiVersion": "iam.services.k8s.aws/v1alpha1",
 					"kind":       "Role",
 					"metadata": map[string]interface{}{
-						"name": "role",
+						"name": "clusterrole",
 					},
 					"spec": map[string]interface{}{
-						"name":                     "${policy.status.policyID}role",
+						"name":                     "${clusterpolicy.status.policyID}role",
 						"assumeRolePolicyDocument": "{}",
 					},
 				}, nil, nil),
-				// Three clusters using the same infrastructure
-				generator.WithResource("cluster1", map[string]interface{}{
-					"apiVersion": "eks.services.k8s.aws/v1alpha1",
-					"kind":       "Cluster",
+				generator.WithResource("subnet1", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
 					"metadata": map[string]interface{}{
-						"name": "cluster1",
+						"name": "subnet1",
 					},
 					"spec": map[string]interface{}{
-						"roleARN": "${role.status.roleID}",
-						"resourcesVPCConfig": map[string]interface{}{
-							"subnetIDs": []interface{}{
-								"${subnet1.status.subnetID}",
-								"${subnet2.status.subnetID}",
-								"${subnet3.status.subnetID}",
-							},
-						},
+						"cidrBlock": "10.0.1.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
 					},
 				}, nil, nil),
-				generator.WithResource("cluster2", map[string]interface{}{
-					"apiVersion": "eks.services.k8s.aws/v1alpha1",
-					"kind":       "Cluster",
+				generator.WithResource("subnet2", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
 					"metadata": map[string]interface{}{
-						"name": "cluster2",
+						"name": "subnet2",
 					},
 					"spec": map[string]interface{}{
-						"roleARN": "${role.status.roleID}",
-						"resourcesVPCConfig": map[string]interface{}{
-							"subnetIDs": []interface{}{
-								"${subnet1.status.subnetID}",
-								"${subnet2.status.subnetID}",
-								"${subnet3.status.subnetID}",
-							},
-						},
+						"cidrBlock": "10.0.2.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
 					},
 				}, nil, nil),
-				generator.WithResource("cluster3", map[string]interface{}{
+				// Third layer: EKS Cluster depending on roles and subnets
+				generator.WithResource("cluster", map[string]interface{}{
 					"apiVersion": "eks.services.k8s.aws/v1alpha1",
 					"kind":       "Cluster",
 					"metadata": map[string]interface{}{
-						"name": "cluster3",
+						"name": "cluster",
 					},
 					"spec": map[string]interface{}{
-						"roleARN": "${role.status.roleID}",
+						"name":    "testcluster",
+						"roleARN": "${clusterrole.status.roleID}",
 						"resourcesVPCConfig": map[string]interface{}{
 							"subnetIDs": []interface{}{
 								"${subnet1.status.subnetID}",
 								"${subnet2.status.subnetID}",
-								"${subnet3.status.subnetID}",
-							},
-						},
-					},
-				}, nil, nil),
-				// Pod depending on all clusters
-				generator.WithResource("monitor", map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "Pod",
-					"metadata": map[string]interface{}{
-						"name": "monitor",
-					},
-					"spec": map[string]interface{}{
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":  "monitor",
-								"image": "monitor:latest",
-								"env": []interface{}{
-									map[string]interface{}{
-										"name":  "CLUSTER1_ARN",
-										"value": "${cluster1.status.ackResourceMetadata.arn}",
-									},
-									map[string]interface{}{
-										"name":  "CLUSTER2_ARN",
-										"value": "${cluster2.status.ackResourceMetadata.arn}",
-									},
-									map[string]interface{}{
-										"name":  "CLUSTER3_ARN",
-										"value": "${cluster3.status.ackResourceMetadata.arn}",
-									},
-								},
 							},
 						},
 					},
-				}, nil, nil),
-			},
+				}, nil, nil)},
 			validateDeps: func(t *testing.T, g *Graph) {
-				// Base infrastructure dependencies
+				// Validate dependencies
 				assert.Empty(t, g.Resources["vpc"].GetDependencies())
-				assert.Empty(t, g.Resources["policy"].GetDependencies())
+				assert.Empty(t, g.Resources["clusterpolicy"].GetDependencies())
 
 				assert.Equal(t, []string{"vpc"}, g.Resources["subnet1"].GetDependencies())
 				assert.Equal(t, []string{"vpc"}, g.Resources["subnet2"].GetDependencies())
-				assert.Equal(t, []string{"vpc"}, g.Resources["subnet3"].GetDependencies())
-				assert.Equal(t, []string{"vpc"}, g.Resources["secgroup"].GetDependencies())
-				assert.Equal(t, []string{"policy"}, g.Resources["role"].GetDependencies())
-
-				// Cluster dependencies
-				clusterDeps := []string{"role", "subnet1", "subnet2", "subnet3"}
-				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster1"].GetDependencies())
-				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster2"].GetDependencies())
-				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster3"].GetDependencies())
+				assert.Equal(t, []string{"clusterpolicy"}, g.Resources["clusterrole"].GetDependencies())
 
-				// Monitor pod dependencies
-				monitorDeps := []string{"cluster1", "cluster2", "cluster3"}
-				assert.ElementsMatch(t, monitorDeps, g.Resources["monitor"].GetDependencies())
+				clusterDeps := g.Resources["cluster"].GetDependencies()
+				assert.Len(t, clusterDeps, 3)
+				assert.Contains(t, clusterDeps, "clusterrole")
+				assert.Contains(t, clusterDeps, "subnet1")
+				assert.Contains(t, clusterDeps, "subnet2")
 
 				// Validate topological order
-				assert.Equal(t, []string{
-					"vpc",
-					"subnet1",
-					"subnet2",
-					"subnet3",
-					"secgroup",
-					"policy",
-					"role",
-					"cluster1",
-					"cluster2",
-					"cluster3",
-					"monitor",
-				}, g.TopologicalOrder)
+				assert.Equal(t, []string{"vpc", "clusterpolicy", "clusterrole", "subnet1", "subnet2", "cluster"}, g.TopologicalOrder)
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rgd := generator.NewResourceGraphDefinition("testrgd", tt.resourceGraphDefinitionOpts...)
-			g, err := builder.NewResourceGraphDefinition(rgd)
-
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errMsg)
-				return
-			}
-
-			require.NoError(t, err)
-			if tt.validateDeps != nil {
-				tt.validateDeps(t, g)
-			}
-		})
-	}
-}
-
-func TestGraphBuilder_ExpressionParsing(t *testing.T) {
-	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
-	builder := &Builder{
-		schemaResolver:   fakeResolver,
-		discoveryClient:  fakeDiscovery,
-		resourceEmulator: emulator.NewEmulator(),
-	}
-
-	tests := []struct {
-		name                        string
-		resourceGraphDefinitionOpts []generator.ResourceGraphDefinitionOption
-		validateVars                func(*testing.T, *Graph)
-	}{
 		{
-			name: "complex resource variable parsing",
+			name: "missing dependency",
 			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
 				generator.WithSchema(
 					"Test", "v1alpha1",
 					map[string]interface{}{
-						"replicas":         "integer | default=3",
-						"environment":      "string | default=dev",
-						"createMonitoring": "boolean | default=false",
+						"name": "string",
 					},
 					nil,
 				),
-				// Resource with no expressions
-				generator.WithResource("policy", map[string]interface{}{
-					"apiVersion": "iam.services.k8s.aws/v1alpha1",
-					"kind":       "Policy",
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
 					"metadata": map[string]interface{}{
-						"name": "policy",
+						"name": "subnet",
 					},
 					"spec": map[string]interface{}{
-						"document": "{}",
+						"cidrBlock": "10.0.0.0/24",
+						"vpcID":     "${missingvpc.status.vpcID}",
 					},
 				}, nil, nil),
-				// Resource with only readyWhen expressions
+			},
+			wantErr: true,
+			errMsg:  "undeclared reference to 'missingvpc'",
+		},
+		{
+			name: "readiness dependency",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
 				generator.WithResource("vpc", map[string]interface{}{
 					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
 					"kind":       "VPC",
@@ -1000,9 +973,520 @@ func TestGraphBuilder_ExpressionParsing(t *testing.T) {
 					"spec": map[string]interface{}{
 						"cidrBlocks": []interface{}{"10.0.0.0/16"},
 					},
-				}, []string{
-					"${vpc.status.state == 'available'}",
-					"${vpc.status.vpcID != ''}",
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+						"annotations": map[string]interface{}{
+							"vpc": "${ready.vpc ? 'ready' : 'waiting'}",
+						},
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.0.0/24",
+					},
+				}, nil, nil),
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet"].GetDependencies())
+				assert.Equal(t, variable.ResourceVariableKindDynamic, g.Resources["subnet"].variables[0].Kind)
+				assert.Equal(t, []string{"vpc", "subnet"}, g.TopologicalOrder)
+			},
+		},
+		{
+			name: "readiness of unknown resource",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet",
+						"annotations": map[string]interface{}{
+							"vpc": "${ready.missingvpc ? 'ready' : 'waiting'}",
+						},
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.0.0/24",
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "reads the readiness of unknown resource missingvpc",
+		},
+		{
+			name: "cyclic dependency",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("role1", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Role",
+					"metadata": map[string]interface{}{
+						"name": "${role2.metadata.name}1",
+					},
+					"spec": map[string]interface{}{
+						"name":                     "testrole1",
+						"assumeRolePolicyDocument": "{}",
+					},
+				}, nil, nil),
+				generator.WithResource("role2", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Role",
+					"metadata": map[string]interface{}{
+						"name": "${role1.metadata.name}2",
+					},
+					"spec": map[string]interface{}{
+						"name":                     "testrole2",
+						"assumeRolePolicyDocument": "{}",
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "graph contains a cycle",
+		},
+		{
+			name: "independent pods",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod1", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod1",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx1",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod2", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod2",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx2",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod3", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod3",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx3",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod4", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod4",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx4",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				assert.Len(t, g.Resources, 4)
+				assert.Empty(t, g.Resources["pod1"].GetDependencies())
+				assert.Empty(t, g.Resources["pod2"].GetDependencies())
+				assert.Empty(t, g.Resources["pod3"].GetDependencies())
+				assert.Empty(t, g.Resources["pod4"].GetDependencies())
+				// Order doesn't matter as they're all independent
+				assert.Len(t, g.TopologicalOrder, 4)
+			},
+		},
+		{
+			name: "cyclic pod dependencies",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod1", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${pod4.status.podIP}app1",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx1",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod2", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${pod1.status.podIP}app2",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx2",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod3", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${pod2.status.podIP}app3",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx3",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("pod4", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${pod3.status.podIP}app4",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "nginx4",
+								"image": "nginx:latest",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			wantErr: true,
+			errMsg:  "graph contains a cycle",
+		},
+		{
+			name: "shared infrastructure dependencies",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				// Base infrastructure
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "vpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+					},
+				}, nil, nil),
+				generator.WithResource("subnet1", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet1",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.1.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet2", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet2",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.2.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet3", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "subnet3",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlock": "10.0.3.0/24",
+						"vpcID":     "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+				generator.WithResource("secgroup", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "SecurityGroup",
+					"metadata": map[string]interface{}{
+						"name": "secgroup",
+					},
+					"spec": map[string]interface{}{
+						"vpcID": "${vpc.status.vpcID}",
+					},
+				}, nil, nil),
+				generator.WithResource("policy", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Policy",
+					"metadata": map[string]interface{}{
+						"name": "policy",
+					},
+					"spec": map[string]interface{}{
+						"document": "{}",
+					},
+				}, nil, nil),
+				generator.WithResource("role", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Role",
+					"metadata": map[string]interface{}{
+						"name": "role",
+					},
+					"spec": map[string]interface{}{
+						"name":                     "${policy.status.policyID}role",
+						"assumeRolePolicyDocument": "{}",
+					},
+				}, nil, nil),
+				// Three clusters using the same infrastructure
+				generator.WithResource("cluster1", map[string]interface{}{
+					"apiVersion": "eks.services.k8s.aws/v1alpha1",
+					"kind":       "Cluster",
+					"metadata": map[string]interface{}{
+						"name": "cluster1",
+					},
+					"spec": map[string]interface{}{
+						"roleARN": "${role.status.roleID}",
+						"resourcesVPCConfig": map[string]interface{}{
+							"subnetIDs": []interface{}{
+								"${subnet1.status.subnetID}",
+								"${subnet2.status.subnetID}",
+								"${subnet3.status.subnetID}",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("cluster2", map[string]interface{}{
+					"apiVersion": "eks.services.k8s.aws/v1alpha1",
+					"kind":       "Cluster",
+					"metadata": map[string]interface{}{
+						"name": "cluster2",
+					},
+					"spec": map[string]interface{}{
+						"roleARN": "${role.status.roleID}",
+						"resourcesVPCConfig": map[string]interface{}{
+							"subnetIDs": []interface{}{
+								"${subnet1.status.subnetID}",
+								"${subnet2.status.subnetID}",
+								"${subnet3.status.subnetID}",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("cluster3", map[string]interface{}{
+					"apiVersion": "eks.services.k8s.aws/v1alpha1",
+					"kind":       "Cluster",
+					"metadata": map[string]interface{}{
+						"name": "cluster3",
+					},
+					"spec": map[string]interface{}{
+						"roleARN": "${role.status.roleID}",
+						"resourcesVPCConfig": map[string]interface{}{
+							"subnetIDs": []interface{}{
+								"${subnet1.status.subnetID}",
+								"${subnet2.status.subnetID}",
+								"${subnet3.status.subnetID}",
+							},
+						},
+					},
+				}, nil, nil),
+				// Pod depending on all clusters
+				generator.WithResource("monitor", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "monitor",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "monitor",
+								"image": "monitor:latest",
+								"env": []interface{}{
+									map[string]interface{}{
+										"name":  "CLUSTER1_ARN",
+										"value": "${cluster1.status.ackResourceMetadata.arn}",
+									},
+									map[string]interface{}{
+										"name":  "CLUSTER2_ARN",
+										"value": "${cluster2.status.ackResourceMetadata.arn}",
+									},
+									map[string]interface{}{
+										"name":  "CLUSTER3_ARN",
+										"value": "${cluster3.status.ackResourceMetadata.arn}",
+									},
+								},
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateDeps: func(t *testing.T, g *Graph) {
+				// Base infrastructure dependencies
+				assert.Empty(t, g.Resources["vpc"].GetDependencies())
+				assert.Empty(t, g.Resources["policy"].GetDependencies())
+
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet1"].GetDependencies())
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet2"].GetDependencies())
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet3"].GetDependencies())
+				assert.Equal(t, []string{"vpc"}, g.Resources["secgroup"].GetDependencies())
+				assert.Equal(t, []string{"policy"}, g.Resources["role"].GetDependencies())
+
+				// Cluster dependencies
+				clusterDeps := []string{"role", "subnet1", "subnet2", "subnet3"}
+				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster1"].GetDependencies())
+				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster2"].GetDependencies())
+				assert.ElementsMatch(t, clusterDeps, g.Resources["cluster3"].GetDependencies())
+
+				// Monitor pod dependencies
+				monitorDeps := []string{"cluster1", "cluster2", "cluster3"}
+				assert.ElementsMatch(t, monitorDeps, g.Resources["monitor"].GetDependencies())
+
+				// Validate topological order
+				assert.Equal(t, []string{
+					"vpc",
+					"subnet1",
+					"subnet2",
+					"subnet3",
+					"secgroup",
+					"policy",
+					"role",
+					"cluster1",
+					"cluster2",
+					"cluster3",
+					"monitor",
+				}, g.TopologicalOrder)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgd := generator.NewResourceGraphDefinition("testrgd", tt.resourceGraphDefinitionOpts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.validateDeps != nil {
+				tt.validateDeps(t, g)
+			}
+		})
+	}
+}
+
+func TestGraphBuilder_ExpressionParsing(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name                        string
+		resourceGraphDefinitionOpts []generator.ResourceGraphDefinitionOption
+		validateVars                func(*testing.T, *Graph)
+	}{
+		{
+			name: "complex resource variable parsing",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"replicas":         "integer | default=3",
+						"environment":      "string | default=dev",
+						"createMonitoring": "boolean | default=false",
+					},
+					nil,
+				),
+				// Resource with no expressions
+				generator.WithResource("policy", map[string]interface{}{
+					"apiVersion": "iam.services.k8s.aws/v1alpha1",
+					"kind":       "Policy",
+					"metadata": map[string]interface{}{
+						"name": "policy",
+					},
+					"spec": map[string]interface{}{
+						"document": "{}",
+					},
+				}, nil, nil),
+				// Resource with only readyWhen expressions
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "vpc",
+					},
+					"spec": map[string]interface{}{
+						"cidrBlocks": []interface{}{"10.0.0.0/16"},
+					},
+				}, []string{
+					"${vpc.status.state == 'available'}",
+					"${vpc.status.vpcID != ''}",
 				}, nil),
 				// Resource with mix of static and dynamic expressions
 				generator.WithResource("subnet", map[string]interface{}{
@@ -1131,56 +1615,408 @@ func TestGraphBuilder_ExpressionParsing(t *testing.T) {
 						kind:                 variable.ResourceVariableKindDynamic,
 						standaloneExpression: true,
 					},
-				})
-				assert.Equal(t, []string{"schema.spec.createMonitoring"}, cluster.GetIncludeWhenExpressions())
-
-				// Verify monitor pod with all types of expressions
-				monitor := g.Resources["monitor"]
-				assert.Len(t, monitor.variables, 7)
-				validateVariables(t, monitor.variables, []expectedVar{
+				})
+				assert.Equal(t, []string{"schema.spec.createMonitoring"}, cluster.GetIncludeWhenExpressions())
+
+				// Verify monitor pod with all types of expressions
+				monitor := g.Resources["monitor"]
+				assert.Len(t, monitor.variables, 7)
+				validateVariables(t, monitor.variables, []expectedVar{
+					{
+						path:                 "metadata.labels.environment",
+						expressions:          []string{"schema.spec.environment"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "metadata.labels.cluster",
+						expressions:          []string{"cluster.metadata.name"},
+						kind:                 variable.ResourceVariableKindDynamic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "metadata.labels.combined",
+						expressions:          []string{"cluster.metadata.name", "schema.spec.environment"},
+						kind:                 variable.ResourceVariableKindDynamic,
+						standaloneExpression: false,
+					},
+					{
+						path:                 "metadata.labels[\"two.statics\"]",
+						expressions:          []string{"schema.spec.environment", "schema.spec.replicas"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: false,
+					},
+					{
+						path:                 "metadata.labels[\"two.dynamics\"]",
+						expressions:          []string{"vpc.metadata.name", "cluster.status.ackResourceMetadata.arn"},
+						kind:                 variable.ResourceVariableKindDynamic,
+						standaloneExpression: false,
+					},
+					{
+						path:                 "spec.containers[0].env[0].value",
+						expressions:          []string{"cluster.status.ackResourceMetadata.arn"},
+						kind:                 variable.ResourceVariableKindDynamic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "spec.containers[0].env[1].value",
+						expressions:          []string{"schema.spec.replicas"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+				})
+			},
+		},
+		{
+			name: "instance labels and annotations are static",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name":        "${schema.spec.name}",
+						"labels":      "${instance.metadata.labels}",
+						"annotations": "${instance.metadata.annotations}",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				pod := g.Resources["pod"]
+				assert.Empty(t, pod.GetDependencies())
+				validateVariables(t, pod.variables, []expectedVar{
+					{
+						path:                 "metadata.name",
+						expressions:          []string{"schema.spec.name"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "metadata.labels",
+						expressions:          []string{"instance.metadata.labels"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "metadata.annotations",
+						expressions:          []string{"instance.metadata.annotations"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+				})
+			},
+		},
+		{
+			name: "network library functions",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name":        "string",
+						"vpcCidr":     "string",
+						"subnetIndex": "integer",
+					},
+					nil,
+				),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+						"annotations": map[string]interface{}{
+							"subnet": "${cidr.subnet(schema.spec.vpcCidr, 24, schema.spec.subnetIndex)}",
+						},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				pod := g.Resources["pod"]
+				assert.Empty(t, pod.GetDependencies())
+				validateVariables(t, pod.variables, []expectedVar{
+					{
+						path:                 "metadata.name",
+						expressions:          []string{"schema.spec.name"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+					{
+						path:                 "metadata.annotations.subnet",
+						expressions:          []string{"cidr.subnet(schema.spec.vpcCidr, 24, schema.spec.subnetIndex)"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+				})
+			},
+		},
+		{
+			name: "resource group identity",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${resourceGroup.name + '-' + schema.spec.name}",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				assert.Equal(t, "testrgd", g.Name)
+				assert.Equal(t, "v1alpha1", g.Version)
+				pod := g.Resources["pod"]
+				assert.Empty(t, pod.GetDependencies())
+				validateVariables(t, pod.variables, []expectedVar{
+					{
+						path:                 "metadata.name",
+						expressions:          []string{"resourceGroup.name + '-' + schema.spec.name"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+				})
+			},
+		},
+		{
+			name: "cluster information",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name + '-' + kroCluster.region}",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "${kroCluster.registry}/nginx",
+							},
+						},
+					},
+				}, nil, []string{"${kroCluster.provider == 'aws'}"}),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				pod := g.Resources["pod"]
+				assert.Empty(t, pod.GetDependencies())
+				validateVariables(t, pod.variables, []expectedVar{
+					{
+						path:                 "metadata.name",
+						expressions:          []string{"schema.spec.name + '-' + kroCluster.region"},
+						kind:                 variable.ResourceVariableKindStatic,
+						standaloneExpression: true,
+					},
+					{
+						path:        "spec.containers[0].image",
+						expressions: []string{"kroCluster.registry"},
+						kind:        variable.ResourceVariableKindStatic,
+					},
+				})
+				assert.Equal(t, []string{"kroCluster.provider == 'aws'"}, pod.GetIncludeWhenExpressions())
+			},
+		},
+		{
+			name: "reconcile context",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+				generator.WithResource("pod", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "${schema.spec.name}",
+						"labels": map[string]interface{}{
+							"cluster": "${has(reconcileContext.clusterName) ? reconcileContext.clusterName : 'local'}",
+						},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				pod := g.Resources["pod"]
+				assert.Empty(t, pod.GetDependencies())
+				validateVariables(t, pod.variables, []expectedVar{
 					{
-						path:                 "metadata.labels.environment",
-						expressions:          []string{"schema.spec.environment"},
+						path:                 "metadata.name",
+						expressions:          []string{"schema.spec.name"},
 						kind:                 variable.ResourceVariableKindStatic,
 						standaloneExpression: true,
 					},
 					{
 						path:                 "metadata.labels.cluster",
-						expressions:          []string{"cluster.metadata.name"},
-						kind:                 variable.ResourceVariableKindDynamic,
+						expressions:          []string{"has(reconcileContext.clusterName) ? reconcileContext.clusterName : 'local'"},
+						kind:                 variable.ResourceVariableKindStatic,
 						standaloneExpression: true,
 					},
-					{
-						path:                 "metadata.labels.combined",
-						expressions:          []string{"cluster.metadata.name", "schema.spec.environment"},
-						kind:                 variable.ResourceVariableKindDynamic,
-						standaloneExpression: false,
+				})
+			},
+		},
+		{
+			name: "previous state",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
 					},
-					{
-						path:                 "metadata.labels[\"two.statics\"]",
-						expressions:          []string{"schema.spec.environment", "schema.spec.replicas"},
-						kind:                 variable.ResourceVariableKindStatic,
-						standaloneExpression: false,
+					nil,
+				),
+				generator.WithResource("podA", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod-a",
 					},
-					{
-						path:                 "metadata.labels[\"two.dynamics\"]",
-						expressions:          []string{"vpc.metadata.name", "cluster.status.ackResourceMetadata.arn"},
-						kind:                 variable.ResourceVariableKindDynamic,
-						standaloneExpression: false,
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("podB", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod-b",
+						"annotations": map[string]interface{}{
+							"placement": "${has(previous.podA) && previous.podA.spec.nodeName != podA.spec.nodeName ? 'moved' : 'stable'}",
+						},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
 					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				podB := g.Resources["podB"]
+				assert.Equal(t, []string{"podA"}, podB.GetDependencies())
+				validateVariables(t, podB.variables, []expectedVar{
 					{
-						path:                 "spec.containers[0].env[0].value",
-						expressions:          []string{"cluster.status.ackResourceMetadata.arn"},
+						path:                 "metadata.annotations.placement",
+						expressions:          []string{"has(previous.podA) && previous.podA.spec.nodeName != podA.spec.nodeName ? 'moved' : 'stable'"},
 						kind:                 variable.ResourceVariableKindDynamic,
 						standaloneExpression: true,
 					},
+				})
+			},
+		},
+		{
+			name: "status counting resources",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					map[string]interface{}{
+						"runningPods": "${countWhere(resources, r, r.status.phase == 'Running')}",
+					},
+				),
+				generator.WithResource("podA", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod-a",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+				generator.WithResource("podB", map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata": map[string]interface{}{
+						"name": "pod-b",
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "main",
+								"image": "nginx",
+							},
+						},
+					},
+				}, nil, nil),
+			},
+			validateVars: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"podA", "podB"}, g.Instance.GetDependencies())
+				validateVariables(t, g.Instance.variables, []expectedVar{
 					{
-						path:                 "spec.containers[0].env[1].value",
-						expressions:          []string{"schema.spec.replicas"},
-						kind:                 variable.ResourceVariableKindStatic,
+						path:                 "status.runningPods",
+						expressions:          []string{"countWhere(resources, r, r.status.phase == 'Running')"},
+						kind:                 variable.ResourceVariableKindDynamic,
 						standaloneExpression: true,
 					},
 				})
+				assert.Equal(t, []string{"podA", "podB"}, g.Instance.variables[0].Dependencies)
+				statusSchema := g.Instance.crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["status"]
+				assert.Equal(t, "integer", statusSchema.Properties["runningPods"].Type)
 			},
 		},
 	}
@@ -1221,6 +2057,227 @@ func validateVariables(t *testing.T, actual []*variable.ResourceField, expected
 	assert.ElementsMatch(t, expected, actualVars)
 }
 
+func TestGraphBuilder_ResourceSettings(t *testing.T) {
+	fakeResolver, fakeDiscovery := k8s.NewFakeResolver()
+	builder := &Builder{
+		schemaResolver:   fakeResolver,
+		discoveryClient:  fakeDiscovery,
+		resourceEmulator: emulator.NewEmulator(),
+	}
+
+	tests := []struct {
+		name                        string
+		resourceGraphDefinitionOpts []generator.ResourceGraphDefinitionOption
+		validate                    func(t *testing.T, g *Graph)
+	}{
+		{
+			name: "deletion policies",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+				}, nil, nil),
+				generator.WithDeletionPolicy("vpc", "Retain"),
+				generator.WithDeletionPolicy("subnet", "${schema.spec.name == 'prod' ? 'Orphan' : 'Delete'}"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				assert.Equal(t, "Retain", g.Resources["vpc"].GetDeletionPolicy())
+				assert.Equal(t, "${schema.spec.name == 'prod' ? 'Orphan' : 'Delete'}", g.Resources["subnet"].GetDeletionPolicy())
+			},
+		},
+		{
+			name: "priorities",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+				}, nil, nil),
+				generator.WithPriority("vpc", "10"),
+				generator.WithPriority("subnet", "${schema.spec.name == 'critical' ? 20 : 0}"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				assert.Equal(t, "10", g.Resources["vpc"].GetPriority())
+				assert.Equal(t, "${schema.spec.name == 'critical' ? 20 : 0}", g.Resources["subnet"].GetPriority())
+
+				rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{"name": "critical"},
+				}})
+				require.NoError(t, err)
+				assert.Equal(t, []string{"subnet", "vpc"}, rt.CreationOrder())
+			},
+		},
+		{
+			name: "readiness dependencies",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("subnet", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "Subnet",
+					"metadata": map[string]interface{}{
+						"name": "test-subnet",
+					},
+				}, nil, nil),
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, nil, nil),
+				generator.WithReadinessDependencies("subnet", "vpc"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				assert.Equal(t, []string{"vpc"}, g.Resources["subnet"].GetReadinessDependencies())
+				assert.Empty(t, g.Resources["subnet"].GetDependencies())
+				assert.Equal(t, []string{"vpc", "subnet"}, g.TopologicalOrder)
+
+				rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{}})
+				require.NoError(t, err)
+				assert.Equal(t, [][]string{{"vpc"}, {"subnet"}}, rt.TopologicalLevels())
+			},
+		},
+		{
+			name: "readyWhen messages",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, []string{"${vpc.status.state == 'available'}"}, nil),
+				generator.WithReadyWhenMessage("vpc", "${vpc.status.state == 'available'}", "vpc is ${vpc.status.state}"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				assert.Equal(t, map[string]string{
+					"vpc.status.state == 'available'": "vpc is ${vpc.status.state}",
+				}, g.Resources["vpc"].GetReadyWhenMessages())
+
+				rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{}})
+				require.NoError(t, err)
+				rt.SetResource("vpc", &unstructured.Unstructured{Object: map[string]interface{}{
+					"status": map[string]interface{}{"state": "pending"},
+				}})
+				ready, reason, err := rt.IsResourceReady("vpc")
+				require.NoError(t, err)
+				assert.False(t, ready)
+				assert.Equal(t, "vpc is pending", reason)
+			},
+		},
+		{
+			name: "reconcile behavior",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+				}, []string{"${vpc.status.state == 'available'}"}, nil),
+				generator.WithSkipReadiness("vpc"),
+				generator.WithIgnoreUpdateErrors("vpc"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				assert.True(t, g.Resources["vpc"].GetSkipReadiness())
+				assert.True(t, g.Resources["vpc"].GetIgnoreUpdateErrors())
+
+				rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{}})
+				require.NoError(t, err)
+				rt.SetResource("vpc", &unstructured.Unstructured{Object: map[string]interface{}{
+					"status": map[string]interface{}{"state": "pending"},
+				}})
+				ready, _, err := rt.IsResourceReady("vpc")
+				require.NoError(t, err)
+				assert.True(t, ready)
+			},
+		},
+		{
+			name: "patch fields",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "test-vpc",
+					},
+					"spec": map[string]interface{}{
+						"tags": "${[{'op': 'add', 'path': '/-', 'value': {'key': 'name', 'value': schema.spec.name}}]}",
+					},
+				}, nil, nil),
+				generator.WithPatchField("vpc", "spec.tags", "json"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				require.Len(t, g.Resources["vpc"].GetVariables(), 1)
+				assert.Equal(t, k8stypes.JSONPatchType, g.Resources["vpc"].GetVariables()[0].PatchType)
+			},
+		},
+		{
+			name: "required features",
+			resourceGraphDefinitionOpts: []generator.ResourceGraphDefinitionOption{
+				generator.WithResource("vpc", map[string]interface{}{
+					"apiVersion": "ec2.services.k8s.aws/v1alpha1",
+					"kind":       "VPC",
+					"metadata": map[string]interface{}{
+						"name": "${shout(schema.spec.name)}",
+					},
+				}, nil, nil),
+				generator.WithRequiredFeatures("vpc", "metadata.name", "builder-test-shout"),
+			},
+			validate: func(t *testing.T, g *Graph) {
+				require.Len(t, g.Resources["vpc"].GetVariables(), 1)
+				assert.Equal(t, []string{"builder-test-shout"}, g.Resources["vpc"].GetVariables()[0].RequiredFeatures)
+
+				rt, err := g.NewGraphRuntime(&unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{"name": "test"},
+				}}, runtime.WithFeatureFlags(map[string]bool{"builder-test-shout": true}))
+				require.NoError(t, err)
+				_, err = rt.Synchronize()
+				require.NoError(t, err)
+				vpc, state := rt.GetResource("vpc")
+				require.Equal(t, runtime.ResourceStateResolved, state)
+				assert.Equal(t, "TEST", vpc.GetName())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]generator.ResourceGraphDefinitionOption{
+				generator.WithSchema(
+					"Test", "v1alpha1",
+					map[string]interface{}{
+						"name": "string",
+					},
+					nil,
+				),
+			}, tt.resourceGraphDefinitionOpts...)
+			rgd := generator.NewResourceGraphDefinition("test-group", opts...)
+			g, err := builder.NewResourceGraphDefinition(rgd)
+			require.NoError(t, err)
+			tt.validate(t, g)
+		})
+	}
+}
+
 func TestNewBuilder(t *testing.T) {
 	builder, err := NewBuilder(&rest.Config{})
 	assert.Nil(t, err)
@@ -31,10 +31,15 @@ type Graph struct {
 	Resources map[string]*Resource
 	// TopologicalOrder is the topological order of the resources in the resource graph definition.
 	TopologicalOrder []string
+	// Name and Version identify the resource graph definition, they are
+	// exposed to the expressions as the "resourceGroup" variable. Version is
+	// the apiVersion of the instances, e.g "v1alpha1".
+	Name    string
+	Version string
 }
 
 // NewGraphRuntime creates a new runtime resource graph definition from the resource graph definition instance.
-func (rgd *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runtime.ResourceGraphDefinitionRuntime, error) {
+func (rgd *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured, opts ...runtime.Option) (*runtime.ResourceGraphDefinitionRuntime, error) {
 	// we need to copy the resources to the runtime resources, mainly focusing
 	// on the variables and dependencies.
 	resources := make(map[string]runtime.Resource)
@@ -44,7 +49,8 @@ func (rgd *Graph) NewGraphRuntime(newInstance *unstructured.Unstructured) (*runt
 
 	instance := rgd.Instance.DeepCopy()
 	instance.originalObject = newInstance
-	rt, err := runtime.NewResourceGraphDefinitionRuntime(instance, resources, rgd.TopologicalOrder)
+	opts = append([]runtime.Option{runtime.WithResourceGroup(rgd.Name, rgd.Version)}, opts...)
+	rt, err := runtime.NewResourceGraphDefinitionRuntime(instance, resources, rgd.TopologicalOrder, opts...)
 	if err != nil {
 		return nil, err
 	}
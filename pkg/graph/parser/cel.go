@@ -26,6 +26,12 @@ const (
 
 var ErrNestedExpression = errors.New("nested expressions are not allowed")
 
+// ExtractExpressions extracts all the CEL expressions (without the "${" and
+// "}" markers) found in the given string template.
+func ExtractExpressions(str string) ([]string, error) {
+	return extractExpressions(str)
+}
+
 // extractExpressions extracts all non-nested CEL expressions from a string.
 // It returns an error if it encounters a nested expression.
 func extractExpressions(str string) ([]string, error) {
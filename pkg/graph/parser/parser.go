@@ -171,6 +171,14 @@ func parseObject(field map[string]interface{}, schema *spec.Schema, path string,
 		if err != nil {
 			return nil, err
 		}
+		// The fields the schema requires must not resolve to null.
+		if slices.Contains(schema.Required, fieldName) {
+			for i := range fieldExpressions {
+				if fieldExpressions[i].Path == fieldPath {
+					fieldExpressions[i].Required = true
+				}
+			}
+		}
 		expressionsFields = append(expressionsFields, fieldExpressions...)
 	}
 	return expressionsFields, nil
@@ -1495,3 +1495,57 @@ func TestPreserveUnknownFields(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRequiredFields(t *testing.T) {
+	resource := map[string]interface{}{
+		"requiredField": "${required.value}",
+		"optionalField": "${optional.value}",
+		"nestedObject": map[string]interface{}{
+			"requiredNested": "${required.nested}",
+		},
+	}
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:     []string{"object"},
+			Required: []string{"requiredField", "nestedObject"},
+			Properties: map[string]spec.Schema{
+				"requiredField": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"optionalField": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+				"nestedObject": {
+					SchemaProps: spec.SchemaProps{
+						Type:     []string{"object"},
+						Required: []string{"requiredNested"},
+						Properties: map[string]spec.Schema{
+							"requiredNested": {SchemaProps: spec.SchemaProps{Type: []string{"string"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expressions, err := ParseResource(resource, schema)
+	if err != nil {
+		t.Fatalf("ParseResource() error = %v", err)
+	}
+
+	want := map[string]bool{
+		"requiredField":               true,
+		"optionalField":               false,
+		"nestedObject.requiredNested": true,
+	}
+	if len(expressions) != len(want) {
+		t.Fatalf("expected %d expressions, got %d", len(want), len(expressions))
+	}
+	for _, expr := range expressions {
+		required, ok := want[expr.Path]
+		if !ok {
+			t.Errorf("unexpected expression at path %s", expr.Path)
+			continue
+		}
+		if expr.Required != required {
+			t.Errorf("Path %s: expected required %v, got %v", expr.Path, required, expr.Required)
+		}
+	}
+}
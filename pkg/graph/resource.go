@@ -14,6 +14,7 @@
 package graph
 
 import (
+	"maps"
 	"slices"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -60,9 +61,21 @@ type Resource struct {
 	variables []*variable.ResourceField
 	// dependencies is a list of the resources this resource depends on.
 	dependencies []string
+	// readinessDependencies is a list of the resources that need to be ready
+	// before this resource is processed.
+	readinessDependencies []string
 	// readyWhenExpressions is a list of the expressions that need to be evaluated
 	// before the resource is considered ready.
 	readyWhenExpressions []string
+	// readyWhenMessages holds the messages to report when a readyWhen
+	// expression evaluates to false, keyed by expression.
+	readyWhenMessages map[string]string
+	// deletionPolicy is the policy (or the expression computing it) deciding
+	// what to do with the resource when the instance is deleted.
+	deletionPolicy string
+	// priority is the creation priority (or the expression computing it) of
+	// the resource among the resources of its topological level.
+	priority string
 	// includeWhenExpressions is a list of the expresisons that need to be evaluated
 	// to decide whether to create a resource graph definition or not
 	includeWhenExpressions []string
@@ -70,6 +83,12 @@ type Resource struct {
 	// This is useful when initiating the dynamic client to interact with the
 	// resource.
 	namespaced bool
+	// skipReadiness indicates that the readiness of the resource isn't
+	// checked, the resource is ready as soon as it's resolved.
+	skipReadiness bool
+	// ignoreUpdateErrors indicates that update failures are reported but
+	// don't fail the reconciliation.
+	ignoreUpdateErrors bool
 	// order reflects the original order in which the resources were specified,
 	// and lets us keep the client-specified ordering where the dependencies allow.
 	order int
@@ -144,11 +163,32 @@ func (r *Resource) GetEmulatedObject() *unstructured.Unstructured {
 	return r.emulatedObject
 }
 
+// GetReadinessDependencies returns the resources that need to be ready before
+// the resource is processed.
+func (r *Resource) GetReadinessDependencies() []string {
+	return r.readinessDependencies
+}
+
 // GetReadyWhenExpressions returns the readyWhen expressions of the resource.
 func (r *Resource) GetReadyWhenExpressions() []string {
 	return r.readyWhenExpressions
 }
 
+// GetReadyWhenMessages returns the readyWhen failure messages of the resource.
+func (r *Resource) GetReadyWhenMessages() map[string]string {
+	return r.readyWhenMessages
+}
+
+// GetDeletionPolicy returns the deletion policy of the resource.
+func (r *Resource) GetDeletionPolicy() string {
+	return r.deletionPolicy
+}
+
+// GetPriority returns the creation priority of the resource.
+func (r *Resource) GetPriority() string {
+	return r.priority
+}
+
 // GetIncludeWhenExpressions returns the condition expressions of the resource.
 func (r *Resource) GetIncludeWhenExpressions() []string {
 	return r.includeWhenExpressions
@@ -164,6 +204,17 @@ func (r *Resource) IsNamespaced() bool {
 	return r.namespaced
 }
 
+// GetSkipReadiness returns true if the readiness of the resource isn't checked.
+func (r *Resource) GetSkipReadiness() bool {
+	return r.skipReadiness
+}
+
+// GetIgnoreUpdateErrors returns true if the update failures of the resource
+// are non-fatal.
+func (r *Resource) GetIgnoreUpdateErrors() bool {
+	return r.ignoreUpdateErrors
+}
+
 // DeepCopy returns a deep copy of the resource.
 func (r *Resource) DeepCopy() *Resource {
 	return &Resource{
@@ -174,8 +225,14 @@ func (r *Resource) DeepCopy() *Resource {
 		originalObject:         r.originalObject.DeepCopy(),
 		variables:              slices.Clone(r.variables),
 		dependencies:           slices.Clone(r.dependencies),
+		readinessDependencies:  slices.Clone(r.readinessDependencies),
 		readyWhenExpressions:   slices.Clone(r.readyWhenExpressions),
+		readyWhenMessages:      maps.Clone(r.readyWhenMessages),
+		deletionPolicy:         r.deletionPolicy,
+		priority:               r.priority,
 		includeWhenExpressions: slices.Clone(r.includeWhenExpressions),
 		namespaced:             r.namespaced,
+		skipReadiness:          r.skipReadiness,
+		ignoreUpdateErrors:     r.ignoreUpdateErrors,
 	}
 }
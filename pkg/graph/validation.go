@@ -46,10 +46,15 @@ var (
 		"graph",
 		"instance",
 		"kind",
+		"kroCluster",
 		"metadata",
 		"namespace",
 		"object",
+		"previous",
+		"ready",
+		"reconcileContext",
 		"resource",
+		"resourceGroup",
 		"resourcegraphdefinition",
 		"resources",
 		"runtime",
@@ -71,6 +71,17 @@ func TestValidateRGResourceNames(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "Context variable as resource id",
+			rgd: &v1alpha1.ResourceGraphDefinition{
+				Spec: v1alpha1.ResourceGraphDefinitionSpec{
+					Resources: []*v1alpha1.Resource{
+						{ID: "resourceGroup"},
+					},
+				},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,6 +101,9 @@ func TestIsKROReservedWord(t *testing.T) {
 	}{
 		{"resourcegraphdefinition", true},
 		{"instance", true},
+		{"kroCluster", true},
+		{"cluster", false},
+		{"resourceGroup", true},
 		{"notReserved", false},
 		{"RESOURCEGRAPHDEFINITION", false}, // Case-sensitive check
 	}
@@ -16,6 +16,7 @@ package variable
 import (
 	"slices"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
@@ -41,6 +42,19 @@ type FieldDescriptor struct {
 	// that is not part of a larger string. example: "${foo}" is a standalone expression
 	// but not "hello-${foo}" or "${foo}${bar}"
 	StandaloneExpression bool
+	// PatchType is set when the resolved value of the (standalone) expression
+	// is a patch to apply to the observed value of the field, rather than the
+	// value of the field itself. Supported types are types.JSONPatchType and
+	// types.MergePatchType.
+	PatchType types.PatchType
+	// RequiredFeatures are the feature flags the expressions opt in to. The
+	// expressions get access to the CEL declarations gated behind them, and
+	// fail to compile if one of them is disabled.
+	RequiredFeatures []string
+	// Required is true if the field must not be null. With the strict null
+	// handling, a required field resolving to null is an error, while an
+	// optional field resolving to null is omitted.
+	Required bool
 }
 
 // ResourceVariable represents a variable in a resource. Variables are any
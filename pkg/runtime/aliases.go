@@ -0,0 +1,83 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+)
+
+// buildKindAliases registers the kind of every resource that is unique in the
+// graph as an alias of the resource id. Kinds shared by multiple resources
+// are recorded as ambiguous, and never aliased.
+//
+// Kinds colliding with a resource id or a context variable (e.g "schema")
+// are not aliased either, the resource id always wins.
+func (rt *ResourceGraphDefinitionRuntime) buildKindAliases() {
+	byKind := make(map[string][]string)
+	for id, resource := range rt.resources {
+		kind := resource.Unstructured().GetKind()
+		if kind == "" {
+			continue
+		}
+		byKind[kind] = append(byKind[kind], id)
+	}
+
+	rt.kindAliases = make(map[string]string)
+	rt.ambiguousKinds = make(map[string][]string)
+	for kind, ids := range byKind {
+		if _, ok := rt.resources[kind]; ok || slices.Contains(contextVariableNames, kind) {
+			continue
+		}
+		if len(ids) > 1 {
+			slices.Sort(ids)
+			rt.ambiguousKinds[kind] = ids
+			continue
+		}
+		rt.kindAliases[kind] = ids[0]
+	}
+}
+
+// resolveDependencyAliases returns the given dependencies with the kind
+// aliases replaced by the ids of the resources they refer to. Referencing an
+// ambiguous kind is an error.
+func (rt *ResourceGraphDefinitionRuntime) resolveDependencyAliases(dependencies []string) ([]string, error) {
+	if len(rt.kindAliases) == 0 && len(rt.ambiguousKinds) == 0 {
+		return dependencies, nil
+	}
+	resolved := make([]string, 0, len(dependencies))
+	for _, dep := range dependencies {
+		if ids, ok := rt.ambiguousKinds[dep]; ok {
+			return nil, fmt.Errorf("kind %s is ambiguous, it can't be referenced by kind: matching resources %v", dep, ids)
+		}
+		if id, ok := rt.kindAliases[dep]; ok {
+			dep = id
+		}
+		if !slices.Contains(resolved, dep) {
+			resolved = append(resolved, dep)
+		}
+	}
+	return resolved, nil
+}
+
+// kindAliasesOf returns the kind aliases of the given resource ids.
+func (rt *ResourceGraphDefinitionRuntime) kindAliasesOf(ids []string) map[string]string {
+	aliases := make(map[string]string)
+	for alias, id := range rt.kindAliases {
+		if slices.Contains(ids, id) {
+			aliases[alias] = id
+		}
+	}
+	return aliases
+}
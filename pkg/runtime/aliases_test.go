@@ -0,0 +1,110 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_KindAliases(t *testing.T) {
+	newResources := func(kinds map[string]string) map[string]Resource {
+		resources := map[string]Resource{
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"data": map[string]interface{}{
+						"replicas": "${Deployment.spec.replicas}",
+					},
+				}),
+				withDependencies([]string{"Deployment"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "data.replicas",
+							Expressions:          []string{"Deployment.spec.replicas"},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"Deployment"},
+					},
+				}),
+			),
+		}
+		for id, kind := range kinds {
+			resources[id] = newTestResource(withObject(map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       kind,
+			}))
+		}
+		return resources
+	}
+
+	t.Run("unique kind", func(t *testing.T) {
+		rt, err := NewResourceGraphDefinitionRuntime(
+			newTestResource(),
+			newResources(map[string]string{"deployment": "Deployment"}),
+			[]string{"deployment", "consumer"},
+			WithKindAliases(),
+		)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+
+		rt.SetResource("deployment", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(3)},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+
+		got := rt.expressionsCache["Deployment.spec.replicas"]
+		if !got.Resolved || got.ResolvedValue != int64(3) {
+			t.Errorf("Deployment.spec.replicas = %v (resolved %v), want 3", got.ResolvedValue, got.Resolved)
+		}
+		if want := []string{"deployment"}; !slices.Equal(got.Dependencies, want) {
+			t.Errorf("dependencies = %v, want %v", got.Dependencies, want)
+		}
+	})
+
+	t.Run("ambiguous kind", func(t *testing.T) {
+		_, err := NewResourceGraphDefinitionRuntime(
+			newTestResource(),
+			newResources(map[string]string{"frontend": "Deployment", "backend": "Deployment"}),
+			[]string{"frontend", "backend", "consumer"},
+			WithKindAliases(),
+		)
+		if err == nil || !strings.Contains(err.Error(), "kind Deployment is ambiguous") {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v, want ambiguous kind error", err)
+		}
+	})
+
+	t.Run("aliases disabled", func(t *testing.T) {
+		rt, err := NewResourceGraphDefinitionRuntime(
+			newTestResource(),
+			newResources(map[string]string{"deployment": "Deployment"}),
+			[]string{"deployment", "consumer"},
+		)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		if len(rt.kindAliases) != 0 {
+			t.Errorf("kindAliases = %v, want none", rt.kindAliases)
+		}
+	})
+}
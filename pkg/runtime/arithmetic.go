@@ -0,0 +1,47 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrArithmeticOverflow is returned when an expression overflows the
+	// range of its integer type, e.g int64.
+	ErrArithmeticOverflow = errors.New("arithmetic overflow")
+	// ErrDivisionByZero is returned when an expression divides (or takes
+	// the modulus of) an integer by zero.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// classifyArithmeticError returns a terminal error with a clear message if
+// the CEL evaluation error is an integer overflow or a division by zero, and
+// nil otherwise. Unlike incomplete data, retrying these evaluations with the
+// same inputs is never going to succeed.
+func classifyArithmeticError(expression string, err error) error {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "unsigned integer overflow"):
+		return fmt.Errorf("%w: expression %s overflowed uint64", ErrArithmeticOverflow, expression)
+	case strings.Contains(message, "integer overflow"):
+		return fmt.Errorf("%w: expression %s overflowed int64", ErrArithmeticOverflow, expression)
+	case strings.Contains(message, "division by zero"), strings.Contains(message, "modulus by zero"):
+		return fmt.Errorf("%w in expression %s", ErrDivisionByZero, expression)
+	default:
+		return nil
+	}
+}
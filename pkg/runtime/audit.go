@@ -0,0 +1,142 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime/resolver"
+)
+
+// RedactedValue replaces the values of the redacted fields in the audit
+// trail, see WithAuditRedaction.
+const RedactedValue = "<redacted>"
+
+// ResolvedField is a field set by expressions, as reported by AuditTrail.
+type ResolvedField struct {
+	// ResourceID is the id of the resource holding the field, "instance"
+	// for the instance status fields.
+	ResourceID string
+	// Path is the path of the field, e.g "spec.replicas".
+	Path string
+	// Expressions are the expressions producing the field.
+	Expressions []string
+	// Value is the resolved value of the field, or RedactedValue if the field
+	// is redacted.
+	Value interface{}
+	// Sources are the ids of the resources the expressions read, sorted.
+	// It's empty for the fields only depending on the instance.
+	Sources []string
+	// Redacted is true if the value of the field was redacted.
+	Redacted bool
+}
+
+// AuditTrail returns every field resolved by the runtime, with the
+// expressions and source resources producing it, e.g to prove what produced
+// each field of a deployed object. The fields are listed by resource, in
+// topological order, followed by the instance status fields, and sorted by
+// path. The fields whose expressions aren't all resolved yet are omitted, and
+// the values of the fields matched by WithAuditRedaction are redacted.
+func (rt *ResourceGraphDefinitionRuntime) AuditTrail() []ResolvedField {
+	var trail []ResolvedField
+	for _, id := range append(slices.Clone(rt.topologicalOrder), "instance") {
+		resource, template := rt.instance, rt.instanceTemplate
+		if id != "instance" {
+			resource, template = rt.resources[id], rt.templates[id]
+		}
+		if resource == nil {
+			continue
+		}
+		if template == nil {
+			template = resource.Unstructured().Object
+		}
+
+		var fields []ResolvedField
+		for _, v := range resource.GetVariables() {
+			if field, ok := rt.resolvedField(id, template, v.FieldDescriptor); ok {
+				fields = append(fields, field)
+			}
+		}
+		slices.SortFunc(fields, func(a, b ResolvedField) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+		trail = append(trail, fields...)
+	}
+	return trail
+}
+
+// resolvedField returns the audit trail entry of the field, and false if the
+// field isn't resolved yet.
+func (rt *ResourceGraphDefinitionRuntime) resolvedField(
+	id string,
+	template map[string]interface{},
+	descriptor variable.FieldDescriptor,
+) (ResolvedField, bool) {
+	field := ResolvedField{
+		ResourceID:  id,
+		Path:        descriptor.Path,
+		Expressions: slices.Clone(descriptor.Expressions),
+	}
+	values := make(map[string]interface{}, len(descriptor.Expressions))
+	for _, expr := range descriptor.Expressions {
+		state, ok := rt.expressionsCache[expr]
+		if !ok || !state.Resolved {
+			return ResolvedField{}, false
+		}
+		values[expr] = state.ResolvedValue
+		for _, dep := range state.Dependencies {
+			if !slices.Contains(field.Sources, dep) {
+				field.Sources = append(field.Sources, dep)
+			}
+		}
+	}
+	slices.Sort(field.Sources)
+
+	if descriptor.StandaloneExpression {
+		field.Value = values[descriptor.Expressions[0]]
+	} else {
+		// String templates are rendered from the template of the field,
+		// the resource itself may not be rendered yet.
+		value, err := renderField(template, values, descriptor)
+		if err != nil {
+			return ResolvedField{}, false
+		}
+		field.Value = value
+	}
+	if rt.auditRedaction != nil && rt.auditRedaction(field) {
+		field.Value = RedactedValue
+		field.Redacted = true
+	}
+	return field, true
+}
+
+// renderField renders the field of the template with the given expression
+// values.
+func renderField(template, values map[string]interface{}, descriptor variable.FieldDescriptor) (interface{}, error) {
+	fieldTemplate, err := resolver.NewResolver(template, nil).ValueAtPath(descriptor.Path)
+	if err != nil {
+		return nil, err
+	}
+	rs := resolver.NewResolver(map[string]interface{}{}, values)
+	if err := rs.UpsertValueAtPath(descriptor.Path, fieldTemplate); err != nil {
+		return nil, err
+	}
+	summary := rs.Resolve([]variable.FieldDescriptor{descriptor})
+	if len(summary.Errors) > 0 {
+		return nil, summary.Errors[0]
+	}
+	return rs.ValueAtPath(descriptor.Path)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_AuditTrail(t *testing.T) {
+	newField := func(path string, standalone bool, kind variable.ResourceVariableKind, deps []string, exprs ...string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          exprs,
+				StandaloneExpression: standalone,
+			},
+			Kind:         kind,
+			Dependencies: deps,
+		}
+	}
+	static, dynamic := variable.ResourceVariableKindStatic, variable.ResourceVariableKindDynamic
+
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec":   map[string]interface{}{"name": "app", "replicas": int64(2)},
+			"status": map[string]interface{}{"endpoint": "${service.spec.clusterIP}"},
+		}),
+		withVariables([]*variable.ResourceField{
+			newField("status.endpoint", true, dynamic, []string{"service"}, "service.spec.clusterIP"),
+		}),
+	)
+	resources := map[string]Resource{
+		"secret": newTestResource(),
+		"service": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${schema.spec.name}-svc"},
+			}),
+			withVariables([]*variable.ResourceField{
+				newField("metadata.name", false, static, nil, "schema.spec.name"),
+			}),
+		),
+		"deployment": newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": "${schema.spec.replicas}",
+					"password": "${secret.data.password}",
+					"url":      "http://${service.spec.clusterIP}:${schema.spec.replicas}",
+				},
+			}),
+			withDependencies([]string{"secret", "service"}),
+			withVariables([]*variable.ResourceField{
+				newField("spec.replicas", true, static, nil, "schema.spec.replicas"),
+				newField("spec.password", true, dynamic, []string{"secret"}, "secret.data.password"),
+				newField("spec.url", false, dynamic, []string{"service"}, "service.spec.clusterIP", "schema.spec.replicas"),
+			}),
+		),
+	}
+	redactSecrets := func(field ResolvedField) bool {
+		for _, source := range field.Sources {
+			if source == "secret" {
+				return true
+			}
+		}
+		return false
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"secret", "service", "deployment"}, WithAuditRedaction(redactSecrets))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// Only the static fields are resolved before the dependencies are.
+	want := []ResolvedField{
+		{ResourceID: "service", Path: "metadata.name", Expressions: []string{"schema.spec.name"}, Value: "app-svc"},
+		{ResourceID: "deployment", Path: "spec.replicas", Expressions: []string{"schema.spec.replicas"}, Value: int64(2)},
+	}
+	if got := rt.AuditTrail(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditTrail() = %+v, want %+v", got, want)
+	}
+
+	rt.SetResource("secret", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"password": "hunter2"},
+	}})
+	rt.SetResource("service", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"clusterIP": "10.0.0.1"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	want = []ResolvedField{
+		{ResourceID: "service", Path: "metadata.name", Expressions: []string{"schema.spec.name"}, Value: "app-svc"},
+		{
+			ResourceID:  "deployment",
+			Path:        "spec.password",
+			Expressions: []string{"secret.data.password"},
+			Value:       RedactedValue,
+			Sources:     []string{"secret"},
+			Redacted:    true,
+		},
+		{ResourceID: "deployment", Path: "spec.replicas", Expressions: []string{"schema.spec.replicas"}, Value: int64(2)},
+		{
+			ResourceID:  "deployment",
+			Path:        "spec.url",
+			Expressions: []string{"service.spec.clusterIP", "schema.spec.replicas"},
+			Value:       "http://10.0.0.1:2",
+			Sources:     []string{"service"},
+		},
+		{
+			ResourceID:  "instance",
+			Path:        "status.endpoint",
+			Expressions: []string{"service.spec.clusterIP"},
+			Value:       "10.0.0.1",
+			Sources:     []string{"service"},
+		},
+	}
+	got := rt.AuditTrail()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditTrail() = %+v, want %+v", got, want)
+	}
+}
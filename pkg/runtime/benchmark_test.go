@@ -0,0 +1,413 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// newHeavyExpressionsRuntime returns a runtime with a "source" resource and
+// n consumers, each of them using an expensive expression reading the source.
+func newHeavyExpressionsRuntime(b *testing.B, n int) *ResourceGraphDefinitionRuntime {
+	resources := map[string]Resource{
+		"source": newTestResource(),
+	}
+	order := []string{"source"}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("consumer%d", i)
+		expr := fmt.Sprintf(`source.data.items.map(x, {"key": x, "value": x * %d})`, i+1)
+		resources[id] = newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{"items": "${" + expr + "}"},
+			}),
+			withDependencies([]string{"source"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "spec.items",
+						Expressions:          []string{expr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"source"},
+				},
+			}),
+		)
+		order = append(order, id)
+	}
+
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+	if err != nil {
+		b.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = int64(i)
+	}
+	rt.SetResource("source", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"items": items},
+	}})
+	return rt
+}
+
+// BenchmarkSynchronize_LazyExpressions compares the cost of Synchronize when
+// the consumers of expensive expressions are included and when they are
+// ignored by conditions, in which case the expressions are never evaluated.
+func BenchmarkSynchronize_LazyExpressions(b *testing.B) {
+	for _, ignored := range []bool{false, true} {
+		b.Run(fmt.Sprintf("ignored=%v", ignored), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				rt := newHeavyExpressionsRuntime(b, 10)
+				if ignored {
+					for id := range rt.resources {
+						if id != "source" {
+							rt.IgnoreResource(id)
+						}
+					}
+				}
+				b.StartTimer()
+
+				if _, err := rt.Synchronize(); err != nil {
+					b.Fatalf("Synchronize() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynchronize_MemoCache compares the cost of Synchronize with and
+// without a memoization cache shared by the runtimes. The source carries a
+// large status the expressions don't reference, which stays out of the
+// memoization keys.
+func BenchmarkSynchronize_MemoCache(b *testing.B) {
+	conditions := make([]interface{}, 5000)
+	for i := range conditions {
+		conditions[i] = map[string]interface{}{
+			"type":    fmt.Sprintf("Condition%d", i),
+			"status":  "True",
+			"message": "the condition is met",
+		}
+	}
+	for _, memoized := range []bool{false, true} {
+		b.Run(fmt.Sprintf("memoized=%v", memoized), func(b *testing.B) {
+			cache := &countingMemoCache{values: map[string]interface{}{}}
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				rt := newHeavyExpressionsRuntime(b, 10)
+				if memoized {
+					WithMemoCache(cache)(rt)
+				}
+				source, _ := rt.GetResource("source")
+				source.Object["status"] = map[string]interface{}{"conditions": conditions}
+				b.StartTimer()
+
+				if _, err := rt.Synchronize(); err != nil {
+					b.Fatalf("Synchronize() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// newStaticExpressionsResources returns a resource using n independent static
+// expressions, each of them reading the instance spec.
+func newStaticExpressionsResources(n int) map[string]Resource {
+	fields := make([]*variable.ResourceField, 0, n)
+	data := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("key%d", i)
+		expr := fmt.Sprintf(`schema.spec.items.map(x, x * %d).filter(x, x %% 3 == 0).size()`, i+1)
+		data[key] = "${" + expr + "}"
+		fields = append(fields, &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "data." + key,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind: variable.ResourceVariableKindStatic,
+		})
+	}
+	return map[string]Resource{
+		"configmap": newTestResource(
+			withObject(map[string]interface{}{"data": data}),
+			withVariables(fields),
+		),
+	}
+}
+
+// BenchmarkNewResourceGraphDefinitionRuntime_StaticExpressions compares the
+// construction time of a runtime with 500 static expressions, evaluated
+// sequentially and by the default worker pool.
+func BenchmarkNewResourceGraphDefinitionRuntime_StaticExpressions(b *testing.B) {
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = int64(i)
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"items": items},
+	}))
+	resources := newStaticExpressionsResources(500)
+
+	for _, workers := range []int{1, 0} {
+		name := "sequential"
+		if workers == 0 {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := NewResourceGraphDefinitionRuntime(
+					instance, resources, []string{"configmap"},
+					WithStaticEvaluationWorkers(workers),
+				)
+				if err != nil {
+					b.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynchronize_TypedResources compares the cost of evaluating
+// expressions reading a deployment, exposed as an unstructured object and as
+// a typed *appsv1.Deployment.
+func BenchmarkSynchronize_TypedResources(b *testing.B) {
+	exprs := make([]string, 0, 100)
+	for i := 0; i < 25; i++ {
+		exprs = append(exprs,
+			fmt.Sprintf("deployment.spec.replicas + %d", i),
+			fmt.Sprintf("deployment.status.readyReplicas == deployment.spec.replicas + %d", i),
+			fmt.Sprintf("deployment.metadata.labels.app + '-%d'", i),
+			fmt.Sprintf("deployment.spec.template.spec.containers.map(c, c.image + '-%d')", i),
+		)
+	}
+	for _, typed := range []bool{false, true} {
+		b.Run(fmt.Sprintf("typed=%v", typed), func(b *testing.B) {
+			var opts []Option
+			if typed {
+				opts = append(opts, WithTypedResource(appsv1.SchemeGroupVersion.WithKind("Deployment"), &appsv1.Deployment{}))
+			}
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				rt := newDeploymentConsumerRuntime(b, exprs, opts...)
+				rt.SetResource("deployment", newObservedDeployment())
+				b.StartTimer()
+
+				if _, err := rt.Synchronize(); err != nil {
+					b.Fatalf("Synchronize() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynchronize_LargeGraph measures the cost of Synchronize on a graph
+// of 200 resources: 20 sources, each read by 9 consumers. Every dynamic
+// expression is compiled in an environment only declaring its dependencies.
+func BenchmarkSynchronize_LargeGraph(b *testing.B) {
+	const sources, consumersPerSource = 20, 9
+	resources := map[string]Resource{}
+	var order []string
+	for i := 0; i < sources; i++ {
+		id := fmt.Sprintf("source%d", i)
+		resources[id] = newTestResource()
+		order = append(order, id)
+	}
+	for i := 0; i < sources*consumersPerSource; i++ {
+		source := fmt.Sprintf("source%d", i%sources)
+		id := fmt.Sprintf("consumer%d", i)
+		expr := fmt.Sprintf("%s.data.name + '-%d'", source, i)
+		resources[id] = newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"name": "${" + expr + "}"},
+			}),
+			withDependencies([]string{source}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.name",
+						Expressions:          []string{expr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{source},
+				},
+			}),
+		)
+		order = append(order, id)
+	}
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+		if err != nil {
+			b.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		for j := 0; j < sources; j++ {
+			id := fmt.Sprintf("source%d", j)
+			rt.SetResource(id, &unstructured.Unstructured{Object: map[string]interface{}{
+				"data": map[string]interface{}{"name": id},
+			}})
+		}
+		b.StartTimer()
+
+		if _, err := rt.Synchronize(); err != nil {
+			b.Fatalf("Synchronize() error = %v", err)
+		}
+	}
+}
+
+// newMixedCostResources returns a "source" resource and consumers using
+// either a cheap expression, or an expensive one waiting for a field the
+// source doesn't have yet.
+func newMixedCostResources(cheap, expensive int) (map[string]Resource, []string, []string) {
+	resources := map[string]Resource{
+		"source": newTestResource(),
+	}
+	order := []string{"source"}
+	var cheapExprs []string
+	addConsumer := func(id, expr string) {
+		resources[id] = newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${" + expr + "}"},
+			}),
+			withDependencies([]string{"source"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{expr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"source"},
+				},
+			}),
+		)
+		order = append(order, id)
+	}
+	for i := 0; i < cheap; i++ {
+		expr := fmt.Sprintf("source.data.name + '-%d'", i)
+		cheapExprs = append(cheapExprs, expr)
+		addConsumer(fmt.Sprintf("cheap%d", i), expr)
+	}
+	for i := 0; i < expensive; i++ {
+		expr := fmt.Sprintf(`source.data.items.map(x, x * %d).filter(x, x %% 3 == 0).size() > 0 ? source.data.pending : 0`, i+1)
+		addConsumer(fmt.Sprintf("expensive%d", i), expr)
+	}
+	return resources, order, cheapExprs
+}
+
+// BenchmarkSynchronize_CostAwareOrdering compares the time spent in
+// Synchronize until all the cheap expressions of a graph mixing cheap and
+// expensive expressions are resolved, while the expensive ones wait for
+// incomplete data. Every pass stops at the first incomplete data, with cost
+// aware ordering the cheap expressions are all resolved by the first pass.
+func BenchmarkSynchronize_CostAwareOrdering(b *testing.B) {
+	resources, order, cheapExprs := newMixedCostResources(50, 10)
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = int64(i)
+	}
+
+	for _, costAware := range []bool{false, true} {
+		b.Run(fmt.Sprintf("costAware=%v", costAware), func(b *testing.B) {
+			passes := 0
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				var opts []Option
+				if costAware {
+					opts = append(opts, WithCostAwareOrdering())
+				}
+				rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order, opts...)
+				if err != nil {
+					b.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+				}
+				rt.SetResource("source", &unstructured.Unstructured{Object: map[string]interface{}{
+					"data": map[string]interface{}{"name": "source", "items": items},
+				}})
+				b.StartTimer()
+
+				for !cheapExpressionsResolved(rt, cheapExprs) {
+					_, _ = rt.Synchronize()
+					passes++
+				}
+			}
+			b.ReportMetric(float64(passes)/float64(b.N), "passes/op")
+		})
+	}
+}
+
+func cheapExpressionsResolved(rt *ResourceGraphDefinitionRuntime, exprs []string) bool {
+	for _, expr := range exprs {
+		if !rt.expressionsCache[expr].Resolved {
+			return false
+		}
+	}
+	return true
+}
+
+// newReadinessRuntime returns a runtime with n observed resources, each of
+// them with a readyWhen expression.
+func newReadinessRuntime(b *testing.B, n int) *ResourceGraphDefinitionRuntime {
+	resources := make(map[string]Resource, n)
+	order := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("deployment%d", i)
+		resources[id] = newTestResource(withReadyExpressions([]string{
+			id + ".status.readyReplicas == " + id + ".spec.replicas",
+		}))
+		order = append(order, id)
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+	if err != nil {
+		b.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	for _, id := range order {
+		rt.SetResource(id, &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{"readyReplicas": int64(3)},
+		}})
+	}
+	return rt
+}
+
+// BenchmarkReadiness compares the readiness evaluation of 100 resources,
+// one IsResourceReady call per resource and with EvaluateAllReadiness.
+func BenchmarkReadiness(b *testing.B) {
+	rt := newReadinessRuntime(b, 100)
+	b.Run("per-resource", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for id := range rt.resources {
+				if _, _, err := rt.IsResourceReady(id); err != nil {
+					b.Fatalf("IsResourceReady() error = %v", err)
+				}
+			}
+		}
+	})
+	b.Run("bulk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := rt.EvaluateAllReadiness(); err != nil {
+				b.Fatalf("EvaluateAllReadiness() error = %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// reclassifyStaticExpressions reclassifies the dynamic expressions that don't
+// reference any resource as static, e.g "${schema.spec.name}" wrongly
+// classified as dynamic. Dynamic expressions wait for Synchronize to be
+// evaluated, while static ones are evaluated at construction, and don't delay
+// the resolution of the resources using them.
+//
+// Only the expressions referencing nothing but the context variables are
+// reclassified. Expressions that can't be inspected, or referencing unknown
+// variables (e.g the resources list or kind aliases) are left as is.
+func (rt *ResourceGraphDefinitionRuntime) reclassifyStaticExpressions() {
+	var candidates []*expressionEvaluationState
+	for _, ees := range rt.expressionsCache {
+		if !ees.Kind.IsDynamic() {
+			continue
+		}
+		if prefix, _, found := strings.Cut(ees.Expression, ":"); found {
+			if _, ok := rt.evaluators[prefix]; ok {
+				continue
+			}
+		}
+		candidates = append(candidates, ees)
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	inspector, err := rt.newInspector()
+	if err != nil {
+		return
+	}
+	for _, ees := range candidates {
+		inspection, err := inspector.Inspect(ees.Expression)
+		if err != nil || len(inspection.UnknownResources) > 0 || len(inspection.UnknownFunctions) > 0 {
+			continue
+		}
+		referencesResource := false
+		for _, dependency := range inspection.ResourceDependencies {
+			if !slices.Contains(contextVariableNames, dependency.ID) {
+				referencesResource = true
+				break
+			}
+		}
+		if referencesResource {
+			continue
+		}
+		ees.Kind = variable.ResourceVariableKindStatic
+		ees.Dependencies = nil
+		rt.reclassifiedExpressions = append(rt.reclassifiedExpressions, ees.Expression)
+	}
+	slices.Sort(rt.reclassifiedExpressions)
+}
+
+// ReclassifiedExpressions returns the expressions classified as dynamic while
+// they don't reference any resource, and that were reclassified as static.
+// They usually point to a classification bug, that callers may want to warn
+// about.
+func (rt *ResourceGraphDefinitionRuntime) ReclassifiedExpressions() []string {
+	return rt.reclassifiedExpressions
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ReclassifyStaticExpressions(t *testing.T) {
+	const (
+		misclassifiedExpr = "schema.spec.name + '-config'"
+		dependentExpr     = "dep.spec.value"
+		resourcesExpr     = "size(resources)"
+	)
+	field := func(path, expr string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"dep"},
+		}
+	}
+	resources := map[string]Resource{
+		"dep": newTestResource(),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${" + misclassifiedExpr + "}"},
+				"data": map[string]interface{}{
+					"value": "${" + dependentExpr + "}",
+					"count": "${" + resourcesExpr + "}",
+				},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				field("metadata.name", misclassifiedExpr),
+				field("data.value", dependentExpr),
+				field("data.count", resourcesExpr),
+			}),
+		),
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"name": "app"},
+	}))
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"dep", "consumer"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	if got, want := rt.ReclassifiedExpressions(), []string{misclassifiedExpr}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReclassifiedExpressions() = %v, want %v", got, want)
+	}
+	// The misclassified expression is evaluated at construction, without
+	// waiting for its declared dependency.
+	resolved, value, err := rt.ExpressionState(misclassifiedExpr)
+	if err != nil || !resolved || value != "app-config" {
+		t.Errorf("ExpressionState(%q) = %v, %v, %v, want resolved to %q", misclassifiedExpr, resolved, value, err, "app-config")
+	}
+	for _, expr := range []string{dependentExpr, resourcesExpr} {
+		if kind := rt.expressionsCache[expr].Kind; !kind.IsDynamic() {
+			t.Errorf("%s kind = %v, want dynamic", expr, kind)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kro-run/kro/api/v1alpha1"
+)
+
+const (
+	// ReasonAllResourcesReady is the reason of the aggregated Ready condition
+	// when all the resources are ready.
+	ReasonAllResourcesReady = "AllResourcesReady"
+	// ReasonResourcesNotReady is the reason of the aggregated Ready condition
+	// when at least one resource isn't ready.
+	ReasonResourcesNotReady = "ResourcesNotReady"
+)
+
+// AggregateReadyCondition rolls up the readiness of all the resources into a
+// single Ready condition: the instance is ready if all its resources are
+// ready. Resources ignored by conditions are not taken into account. When
+// some resources aren't ready, the condition message lists them, in
+// topological order, along with the reason they aren't ready.
+//
+// The LastTransitionTime of the condition is left to the caller, e.g
+// meta.SetStatusCondition sets it when the condition status changes. The
+// readiness checks aren't recorded as attempts (see ReadinessAttempts), the
+// resources were already checked by the reconciliation.
+func (rt *ResourceGraphDefinitionRuntime) AggregateReadyCondition() (metav1.Condition, error) {
+	var notReady []string
+	for _, id := range rt.topologicalOrder {
+		if rt.ignoredByConditionsResources[id] {
+			continue
+		}
+		ready, reason, err := rt.isResourceReady(id)
+		if err != nil {
+			return metav1.Condition{}, fmt.Errorf("failed checking readiness of resource %s: %w", id, err)
+		}
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", id, reason))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               string(v1alpha1.InstanceConditionTypeReady),
+		ObservedGeneration: rt.instance.Unstructured().GetGeneration(),
+	}
+	if len(notReady) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonAllResourcesReady
+		condition.Message = "all resources are ready"
+		return condition, nil
+	}
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = ReasonResourcesNotReady
+	condition.Message = fmt.Sprintf("resources not ready: %s", strings.Join(notReady, ", "))
+	return condition, nil
+}
+
+// isConditionsPath returns true if the status path holds the conditions of
+// the instance, i.e status.conditions.
+func isConditionsPath(path string) bool {
+	return path == "status.conditions"
+}
+
+// currentTime returns the current time, as returned by the runtime clock.
+func (rt *ResourceGraphDefinitionRuntime) currentTime() time.Time {
+	if rt.now != nil {
+		return rt.now()
+	}
+	return time.Now()
+}
+
+// setTransitionTimes returns a copy of the conditions produced by an
+// expression, with their lastTransitionTime set following the Kubernetes
+// conventions: a condition keeps the lastTransitionTime of the old condition
+// of the same type when its status is unchanged, and is stamped with now
+// otherwise. Null values are returned as is, other values that aren't lists
+// of conditions with a string type are rejected. Malformed old conditions,
+// e.g written by another controller, are ignored.
+func setTransitionTimes(oldValue, newValue interface{}, now time.Time) (interface{}, error) {
+	if newValue == nil {
+		return nil, nil
+	}
+	conditions, ok := newValue.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of conditions, got %T", newValue)
+	}
+	oldConditions := map[string]map[string]interface{}{}
+	if old, ok := oldValue.([]interface{}); ok {
+		for _, c := range old {
+			if condition, ok := c.(map[string]interface{}); ok {
+				if conditionType, ok := condition["type"].(string); ok {
+					oldConditions[conditionType] = condition
+				}
+			}
+		}
+	}
+
+	stamped := make([]interface{}, 0, len(conditions))
+	for i, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("condition %d: expected an object, got %T", i, c)
+		}
+		conditionType, ok := condition["type"].(string)
+		if !ok {
+			return nil, fmt.Errorf("condition %d: expected a string type, got %T", i, condition["type"])
+		}
+		copied := make(map[string]interface{}, len(condition)+1)
+		for k, v := range condition {
+			copied[k] = v
+		}
+		copied["lastTransitionTime"] = now.UTC().Format(time.RFC3339)
+		if old, ok := oldConditions[conditionType]; ok && old["status"] == condition["status"] {
+			if transition, ok := old["lastTransitionTime"]; ok {
+				copied["lastTransitionTime"] = transition
+			}
+		}
+		stamped = append(stamped, copied)
+	}
+	return stamped, nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_AggregateReadyCondition(t *testing.T) {
+	tests := []struct {
+		name              string
+		resolvedResources map[string]map[string]interface{}
+		ignored           map[string]bool
+		want              metav1.Condition
+		wantErr           bool
+	}{
+		{
+			name: "all resources ready",
+			resolvedResources: map[string]map[string]interface{}{
+				"configmap":  {},
+				"deployment": {"status": map[string]interface{}{"ready": true}},
+				"service":    {"status": map[string]interface{}{"ready": true}},
+			},
+			want: metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 3,
+				Reason:             ReasonAllResourcesReady,
+				Message:            "all resources are ready",
+			},
+		},
+		{
+			name: "some resources not ready",
+			resolvedResources: map[string]map[string]interface{}{
+				"configmap":  {},
+				"deployment": {"status": map[string]interface{}{"ready": false}},
+			},
+			want: metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 3,
+				Reason:             ReasonResourcesNotReady,
+				Message: "resources not ready: " +
+					"deployment (expression deployment.status.ready evaluated to false), " +
+					"service (resource service is not resolved)",
+			},
+		},
+		{
+			name: "ignored resources are skipped",
+			resolvedResources: map[string]map[string]interface{}{
+				"configmap":  {},
+				"deployment": {"status": map[string]interface{}{"ready": true}},
+			},
+			ignored: map[string]bool{"service": true},
+			want: metav1.Condition{
+				Type:               "Ready",
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 3,
+				Reason:             ReasonAllResourcesReady,
+				Message:            "all resources are ready",
+			},
+		},
+		{
+			name: "readiness evaluation error",
+			resolvedResources: map[string]map[string]interface{}{
+				"configmap":  {},
+				"deployment": {},
+				"service":    {"status": map[string]interface{}{"ready": true}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ResourceGraphDefinitionRuntime{
+				instance: newTestResource(
+					withObject(map[string]interface{}{
+						"metadata": map[string]interface{}{"generation": int64(3)},
+					}),
+				),
+				resources: map[string]Resource{
+					"configmap":  newTestResource(),
+					"deployment": newTestResource(withReadyExpressions([]string{"deployment.status.ready"})),
+					"service":    newTestResource(withReadyExpressions([]string{"service.status.ready"})),
+				},
+				topologicalOrder:             []string{"configmap", "deployment", "service"},
+				resolvedResources:            map[string]*unstructured.Unstructured{},
+				ignoredByConditionsResources: tt.ignored,
+			}
+			for id, obj := range tt.resolvedResources {
+				rt.resolvedResources[id] = &unstructured.Unstructured{Object: obj}
+			}
+
+			got, err := rt.AggregateReadyCondition()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AggregateReadyCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AggregateReadyCondition() = %+v, want %+v", got, tt.want)
+			}
+			for id := range rt.resources {
+				if attempts := rt.ReadinessAttempts(id); attempts != 0 {
+					t.Errorf("AggregateReadyCondition() recorded %d readiness attempts for %s", attempts, id)
+				}
+			}
+		})
+	}
+}
+
+func Test_ConditionTransitionTimes(t *testing.T) {
+	const oldTransition = "2025-01-01T00:00:00Z"
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	nowTransition := now.Format(time.RFC3339)
+
+	condition := func(conditionType, status string) map[string]interface{} {
+		return map[string]interface{}{"type": conditionType, "status": status}
+	}
+
+	tests := []struct {
+		name      string
+		old       []interface{}
+		resolved  []interface{}
+		wantTimes map[string]interface{}
+	}{
+		{
+			name: "unchanged status preserves the timestamp",
+			old: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": oldTransition},
+			},
+			resolved:  []interface{}{condition("Ready", "True")},
+			wantTimes: map[string]interface{}{"Ready": oldTransition},
+		},
+		{
+			name: "changed status updates the timestamp",
+			old: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "lastTransitionTime": oldTransition},
+			},
+			resolved:  []interface{}{condition("Ready", "True")},
+			wantTimes: map[string]interface{}{"Ready": nowTransition},
+		},
+		{
+			name: "new and unchanged conditions",
+			old: []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": oldTransition},
+			},
+			resolved:  []interface{}{condition("Ready", "True"), condition("Degraded", "False")},
+			wantTimes: map[string]interface{}{"Ready": oldTransition, "Degraded": nowTransition},
+		},
+		{
+			name:      "no old status",
+			resolved:  []interface{}{condition("Ready", "True")},
+			wantTimes: map[string]interface{}{"Ready": nowTransition},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object := map[string]interface{}{}
+			if tt.old != nil {
+				object["status"] = map[string]interface{}{"conditions": tt.old}
+			}
+			clock := now
+			rt := &ResourceGraphDefinitionRuntime{
+				instance: newTestResource(
+					withObject(object),
+					withVariables([]*variable.ResourceField{
+						{
+							FieldDescriptor: variable.FieldDescriptor{
+								Path:                 "status.conditions",
+								Expressions:          []string{"conditions"},
+								StandaloneExpression: true,
+							},
+						},
+					}),
+				),
+				expressionsCache: map[string]*expressionEvaluationState{
+					"conditions": {Expression: "conditions", Resolved: true, ResolvedValue: tt.resolved},
+				},
+				now: func() time.Time { return clock },
+			}
+
+			// Evaluating twice must not move the timestamps further.
+			for i := 0; i < 2; i++ {
+				if err := rt.evaluateInstanceStatuses(); err != nil {
+					t.Fatalf("evaluateInstanceStatuses() error = %v", err)
+				}
+				clock = clock.Add(time.Hour)
+			}
+
+			status := rt.instance.Unstructured().Object["status"].(map[string]interface{})
+			got := map[string]interface{}{}
+			for _, c := range status["conditions"].([]interface{}) {
+				condition := c.(map[string]interface{})
+				got[condition["type"].(string)] = condition["lastTransitionTime"]
+			}
+			if !reflect.DeepEqual(got, tt.wantTimes) {
+				t.Errorf("lastTransitionTime = %v, want %v", got, tt.wantTimes)
+			}
+			// The cached value is left untouched.
+			for _, c := range tt.resolved {
+				if _, ok := c.(map[string]interface{})["lastTransitionTime"]; ok {
+					t.Errorf("resolved condition %v was modified", c)
+				}
+			}
+		})
+	}
+}
+
+func Test_MalformedConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolved interface{}
+		wantErr  bool
+	}{
+		{name: "null conditions", resolved: nil},
+		{name: "not a list", resolved: "Ready", wantErr: true},
+		{name: "object instead of a list", resolved: map[string]interface{}{"type": "Ready"}, wantErr: true},
+		{name: "item is not an object", resolved: []interface{}{"Ready"}, wantErr: true},
+		{name: "missing type", resolved: []interface{}{map[string]interface{}{"status": "True"}}, wantErr: true},
+		{name: "type is not a string", resolved: []interface{}{map[string]interface{}{"type": int64(1), "status": "True"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ResourceGraphDefinitionRuntime{
+				instance: newTestResource(
+					withObject(map[string]interface{}{
+						// Malformed old conditions are ignored.
+						"status": map[string]interface{}{"conditions": "invalid"},
+					}),
+					withVariables([]*variable.ResourceField{
+						{
+							FieldDescriptor: variable.FieldDescriptor{
+								Path:                 "status.conditions",
+								Expressions:          []string{"conditions"},
+								StandaloneExpression: true,
+							},
+						},
+					}),
+				),
+				expressionsCache: map[string]*expressionEvaluationState{
+					"conditions": {Expression: "conditions", Resolved: true, ResolvedValue: tt.resolved},
+				},
+			}
+			err := rt.evaluateInstanceStatuses()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateInstanceStatuses() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_IsConditionsPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "status.conditions", want: true},
+		{path: "status.database.conditions", want: false},
+		{path: "status.conditionsCount", want: false},
+		{path: "conditions", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isConditionsPath(tt.path); got != tt.want {
+				t.Errorf("isConditionsPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
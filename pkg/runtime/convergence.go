@@ -0,0 +1,89 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxSynchronizeIterations is the default maximum number of
+// Synchronize passes, see WithMaxSynchronizeIterations.
+const DefaultMaxSynchronizeIterations = 100
+
+// ErrMaxSynchronizeIterations is returned when the runtime doesn't converge
+// within the maximum number of Synchronize passes.
+var ErrMaxSynchronizeIterations = errors.New("maximum synchronize iterations reached")
+
+// checkConvergence counts the Synchronize passes that didn't converge since
+// the last change of the inputs of the runtime. Synchronizing again without
+// new inputs can only resolve so much: once the maximum number of iterations
+// is reached, the caller is going around in circles, and the result of the
+// pass is replaced by a diagnostic of the unresolved expressions.
+func (rt *ResourceGraphDefinitionRuntime) checkConvergence(more bool, err error) (bool, error) {
+	if !more {
+		rt.synchronizeIterations = 0
+		return false, err
+	}
+	rt.synchronizeIterations++
+	maxIterations := rt.maxSynchronizeIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxSynchronizeIterations
+	}
+	if rt.synchronizeIterations < maxIterations {
+		return true, err
+	}
+	diagnostic := rt.unresolvedDiagnostic(rt.synchronizeIterations)
+	if err != nil {
+		return true, fmt.Errorf("%w, last error: %v", diagnostic, err)
+	}
+	return true, diagnostic
+}
+
+// unresolvedDiagnostic returns an error wrapping ErrMaxSynchronizeIterations,
+// listing the unresolved expressions, sorted, along with the reason they
+// couldn't be resolved: their last evaluation error, or the dependencies
+// they are still waiting on.
+func (rt *ResourceGraphDefinitionRuntime) unresolvedDiagnostic(iterations int) error {
+	var unresolved []string
+	for _, state := range rt.expressionsCache {
+		if state.Resolved || !state.Kind.IsDynamic() || !rt.isExpressionNeeded(state.Expression) {
+			continue
+		}
+		unresolved = append(unresolved, fmt.Sprintf("%s (%s)", state.Expression, rt.unresolvedReason(state)))
+	}
+	slices.Sort(unresolved)
+	return fmt.Errorf("%w: no convergence after %d iterations, "+
+		"there may be a dependency cycle or an unresolvable dependency, unresolved expressions: %s",
+		ErrMaxSynchronizeIterations, iterations, strings.Join(unresolved, ", "))
+}
+
+// unresolvedReason describes why the expression isn't resolved.
+func (rt *ResourceGraphDefinitionRuntime) unresolvedReason(state *expressionEvaluationState) string {
+	if history := rt.ErrorHistory(state.Expression); len(history) > 0 {
+		return history[len(history)-1].Err.Error()
+	}
+	var waiting []string
+	for _, dependency := range state.Dependencies {
+		if _, ok := rt.resolvedResources[dependency]; !ok && !rt.ignoredByConditionsResources[dependency] {
+			waiting = append(waiting, dependency)
+		}
+	}
+	if len(waiting) > 0 {
+		return "waiting on " + strings.Join(waiting, ", ")
+	}
+	return "not evaluated yet"
+}
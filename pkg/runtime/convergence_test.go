@@ -0,0 +1,141 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_MaxSynchronizeIterations(t *testing.T) {
+	consumer := func(dependency string) Resource {
+		expression := dependency + ".spec.value"
+		return newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${" + expression + "}"},
+			}),
+			withDependencies([]string{dependency}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{expression},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{dependency},
+				},
+			}),
+		)
+	}
+	newRuntime := func(opts ...Option) *ResourceGraphDefinitionRuntime {
+		resources := map[string]Resource{
+			"database":    newTestResource(),
+			"queue":       newTestResource(),
+			"application": consumer("database"),
+			// The queue is never observed.
+			"worker": consumer("queue"),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources,
+			[]string{"database", "queue", "application", "worker"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": "postgres"},
+		}})
+		return rt
+	}
+
+	// The first pass makes progress, the cap is hit before the runtime
+	// notices that the worker expression can't be resolved.
+	err := newRuntime(WithMaxSynchronizeIterations(1)).synchronizeUntilStable()
+	if !errors.Is(err, ErrMaxSynchronizeIterations) {
+		t.Fatalf("synchronizeUntilStable() error = %v, want %v", err, ErrMaxSynchronizeIterations)
+	}
+	for _, want := range []string{"after 1 iterations", "dependency cycle", "queue.spec.value (waiting on queue)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("synchronizeUntilStable() error = %v, want it to contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "database.spec.value") {
+		t.Errorf("synchronizeUntilStable() error = %v, want only the unresolved expressions", err)
+	}
+
+	// Converges within the default cap.
+	if err := newRuntime().synchronizeUntilStable(); err != nil {
+		t.Errorf("synchronizeUntilStable() error = %v", err)
+	}
+}
+
+func Test_Synchronize_MaxIterations(t *testing.T) {
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), map[string]Resource{
+		"queue": newTestResource(),
+		"worker": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${queue.spec.value}"},
+			}),
+			withDependencies([]string{"queue"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{"queue.spec.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"queue"},
+				},
+			}),
+		),
+	}, []string{"queue", "worker"}, WithMaxSynchronizeIterations(3))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// Synchronizing again and again without observing the queue can't
+	// resolve the worker expression.
+	for i := 1; i < 3; i++ {
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() #%d error = %v", i, err)
+		}
+	}
+	_, err = rt.Synchronize()
+	if !errors.Is(err, ErrMaxSynchronizeIterations) {
+		t.Fatalf("Synchronize() #3 error = %v, want %v", err, ErrMaxSynchronizeIterations)
+	}
+	for _, want := range []string{"after 3 iterations", "dependency cycle", "queue.spec.value (waiting on queue)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Synchronize() error = %v, want it to contain %q", err, want)
+		}
+	}
+
+	// New inputs start a new count.
+	rt.SetResource("queue", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": "sqs"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() after observing the queue error = %v", err)
+	}
+	rt.SetResource("worker", &unstructured.Unstructured{Object: map[string]interface{}{}})
+	more, err := rt.Synchronize()
+	if err != nil || more {
+		t.Errorf("Synchronize() = %v, %v, want false, nil", more, err)
+	}
+}
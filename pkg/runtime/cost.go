@@ -0,0 +1,90 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/checker"
+)
+
+// defaultSizeEstimate is the size assumed for the lists, maps and strings of
+// the resources when estimating the cost of the expressions. Their actual
+// size is unknown at compile time, and CEL otherwise assumes the largest
+// possible size, making every comprehension equally (infinitely) expensive.
+var defaultSizeEstimate = checker.SizeEstimate{Min: 0, Max: 100}
+
+// nominalCostEstimator is a checker.CostEstimator assuming that every value
+// of unknown size has the default size estimate.
+type nominalCostEstimator struct{}
+
+// EstimateSize implements checker.CostEstimator.
+func (nominalCostEstimator) EstimateSize(checker.AstNode) *checker.SizeEstimate {
+	return &defaultSizeEstimate
+}
+
+// EstimateCallCost implements checker.CostEstimator.
+func (nominalCostEstimator) EstimateCallCost(string, string, *checker.AstNode, []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
+
+// dynamicEvaluationOrder returns the dynamic expressions in the order they
+// should be evaluated by evaluateDynamicVariables. With cost aware ordering
+// (see WithCostAwareOrdering), the cheapest expressions are evaluated first:
+// an evaluation pass stops at the first incomplete data, so evaluating the
+// cheap expressions first resolves as many of them as possible before
+// spending time on the expensive ones.
+func (rt *ResourceGraphDefinitionRuntime) dynamicEvaluationOrder() []*expressionEvaluationState {
+	variables := make([]*expressionEvaluationState, 0, len(rt.expressionsCache))
+	for _, variable := range rt.expressionsCache {
+		if variable.Kind.IsDynamic() && !variable.Resolved {
+			variables = append(variables, variable)
+		}
+	}
+	if !rt.costAwareOrdering {
+		return variables
+	}
+	slices.SortFunc(variables, func(a, b *expressionEvaluationState) int {
+		return cmp.Or(
+			cmp.Compare(rt.estimatedCost(a), rt.estimatedCost(b)),
+			strings.Compare(a.Expression, b.Expression),
+		)
+	})
+	return variables
+}
+
+// estimatedCost returns the maximum estimated cost of the dynamic
+// expression, cached by expression. Expressions whose cost can't be
+// estimated, e.g because they don't compile, are the most expensive ones.
+func (rt *ResourceGraphDefinitionRuntime) estimatedCost(variable *expressionEvaluationState) uint64 {
+	if cost, ok := rt.expressionCosts[variable.Expression]; ok {
+		return cost
+	}
+	cost := uint64(math.MaxUint64)
+	if env, err := rt.dynamicEnvironment(variable.Dependencies); err == nil {
+		if ast, issues := env.Compile(variable.Expression); issues == nil || issues.Err() == nil {
+			if estimate, err := env.EstimateCost(ast, nominalCostEstimator{}); err == nil {
+				cost = estimate.Max
+			}
+		}
+	}
+	if rt.expressionCosts == nil {
+		rt.expressionCosts = make(map[string]uint64)
+	}
+	rt.expressionCosts[variable.Expression] = cost
+	return cost
+}
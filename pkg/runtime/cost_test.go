@@ -0,0 +1,51 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_CostAwareOrdering(t *testing.T) {
+	resources, order, cheapExprs := newMixedCostResources(5, 2)
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order, WithCostAwareOrdering())
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	evaluationOrder := rt.dynamicEvaluationOrder()
+	if len(evaluationOrder) != 7 {
+		t.Fatalf("dynamicEvaluationOrder() returned %d expressions, want 7", len(evaluationOrder))
+	}
+	for i, variable := range evaluationOrder {
+		if cheap := slices.Contains(cheapExprs, variable.Expression); cheap != (i < len(cheapExprs)) {
+			t.Errorf("dynamicEvaluationOrder()[%d] = %s, want the cheap expressions first", i, variable.Expression)
+		}
+	}
+
+	// A single pass resolves all the cheap expressions, even though the
+	// expensive ones hit incomplete data.
+	rt.SetResource("source", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"name": "source", "items": []interface{}{int64(3)}},
+	}})
+	if _, err := rt.Synchronize(); err == nil {
+		t.Fatalf("Synchronize() error = nil, want incomplete data")
+	}
+	if !cheapExpressionsResolved(rt, cheapExprs) {
+		t.Errorf("cheap expressions not resolved after the first pass")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+)
+
+// ReadyCount counts the ready resources of a group of resources.
+type ReadyCount struct {
+	Total int `json:"total"`
+	Ready int `json:"ready"`
+}
+
+// String returns the count in a human readable form, e.g "3/5 ready".
+func (c ReadyCount) String() string {
+	return fmt.Sprintf("%d/%d ready", c.Ready, c.Total)
+}
+
+// ResourceCounts are the readiness counts of the resources of an instance,
+// suitable for reporting in the instance status.
+type ResourceCounts struct {
+	// ReadyCount aggregates all the resources, except the ones ignored by
+	// conditions.
+	ReadyCount `json:",inline"`
+	// Ignored is the number of resources ignored by conditions.
+	Ignored int `json:"ignored"`
+	// ByKind holds the counts of the resources, keyed by kind. e.g a graph
+	// with multiple subnets reports "3/5 ready" for the Subnet kind.
+	ByKind map[string]ReadyCount `json:"byKind,omitempty"`
+	// ByTemplate holds the counts of the resources, keyed by the id of the
+	// resource template they are rendered from. Every template currently
+	// renders a single resource.
+	ByTemplate map[string]ReadyCount `json:"byTemplate,omitempty"`
+}
+
+// ResourceCounts returns the total and ready counts of the resources. Unlike
+// IsResourceReady, it doesn't count as a readiness attempt.
+func (rt *ResourceGraphDefinitionRuntime) ResourceCounts() (ResourceCounts, error) {
+	counts := ResourceCounts{
+		ByKind:     make(map[string]ReadyCount),
+		ByTemplate: make(map[string]ReadyCount),
+	}
+	for _, id := range rt.topologicalOrder {
+		if rt.ignoredByConditionsResources[id] {
+			counts.Ignored++
+			continue
+		}
+		ready, _, err := rt.isResourceReady(id)
+		if err != nil {
+			return ResourceCounts{}, fmt.Errorf("failed checking readiness of resource %s: %w", id, err)
+		}
+
+		kind := rt.resources[id].Unstructured().GetKind()
+		byKind, byTemplate := counts.ByKind[kind], counts.ByTemplate[id]
+		counts.Total++
+		byKind.Total++
+		byTemplate.Total++
+		if ready {
+			counts.Ready++
+			byKind.Ready++
+			byTemplate.Ready++
+		}
+		counts.ByKind[kind], counts.ByTemplate[id] = byKind, byTemplate
+	}
+	return counts, nil
+}
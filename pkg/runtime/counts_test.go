@@ -0,0 +1,75 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_ResourceCounts(t *testing.T) {
+	newResource := func(kind string, opts ...mockResourceOption) *mockResource {
+		opts = append(opts, withObject(map[string]interface{}{"kind": kind}))
+		return newTestResource(opts...)
+	}
+	resources := map[string]Resource{
+		"vpc":     newResource("VPC"),
+		"subnetA": newResource("Subnet", withReadyExpressions([]string{"subnetA.status.ready"})),
+		"subnetB": newResource("Subnet", withReadyExpressions([]string{"subnetB.status.ready"})),
+		"subnetC": newResource("Subnet", withReadyExpressions([]string{"subnetC.status.ready"})),
+		"cache":   newResource("Cache"),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources,
+		[]string{"cache", "subnetA", "subnetB", "subnetC", "vpc"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.IgnoreResource("cache")
+	rt.SetResource("vpc", &unstructured.Unstructured{Object: map[string]interface{}{}})
+	for id, ready := range map[string]bool{"subnetA": true, "subnetB": false, "subnetC": true} {
+		rt.SetResource(id, &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": ready},
+		}})
+	}
+
+	got, err := rt.ResourceCounts()
+	if err != nil {
+		t.Fatalf("ResourceCounts() error = %v", err)
+	}
+	want := ResourceCounts{
+		ReadyCount: ReadyCount{Total: 4, Ready: 3},
+		Ignored:    1,
+		ByKind: map[string]ReadyCount{
+			"VPC":    {Total: 1, Ready: 1},
+			"Subnet": {Total: 3, Ready: 2},
+		},
+		ByTemplate: map[string]ReadyCount{
+			"vpc":     {Total: 1, Ready: 1},
+			"subnetA": {Total: 1, Ready: 1},
+			"subnetB": {Total: 1, Ready: 0},
+			"subnetC": {Total: 1, Ready: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResourceCounts() = %+v, want %+v", got, want)
+	}
+	if s := got.ByKind["Subnet"].String(); s != "2/3 ready" {
+		t.Errorf("ByKind[Subnet].String() = %q, want %q", s, "2/3 ready")
+	}
+	if attempts := rt.ReadinessAttempts("subnetB"); attempts != 0 {
+		t.Errorf("ReadinessAttempts(subnetB) = %d, want 0", attempts)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Defaulter sets the default values of the unset fields of an observed
+// object in place, see WithDefaulter.
+type Defaulter func(object map[string]interface{})
+
+// defaultedObject returns the observed object with the defaults of its kind
+// applied. The observed object is left untouched, the defaults are applied to
+// a copy, cached until the resource is set again.
+func (rt *ResourceGraphDefinitionRuntime) defaultedObject(id string, observed *unstructured.Unstructured) map[string]interface{} {
+	defaulter, ok := rt.defaulters[observed.GroupVersionKind()]
+	if !ok {
+		return observed.Object
+	}
+	if defaulted, ok := rt.defaultedObjects[id]; ok {
+		return defaulted
+	}
+
+	defaulted := observed.DeepCopy().Object
+	defaulter(defaulted)
+	if rt.defaultedObjects == nil {
+		rt.defaultedObjects = make(map[string]map[string]interface{})
+	}
+	rt.defaultedObjects[id] = defaulted
+	return defaulted
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_Defaulter(t *testing.T) {
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	defaultReplicas := func(object map[string]interface{}) {
+		if _, found, _ := unstructured.NestedFieldNoCopy(object, "spec", "replicas"); !found {
+			_ = unstructured.SetNestedField(object, int64(1), "spec", "replicas")
+		}
+	}
+
+	tests := []struct {
+		name     string
+		observed map[string]interface{}
+		want     interface{}
+	}{
+		{
+			name: "default applied",
+			observed: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec":       map[string]interface{}{},
+			},
+			want: int64(1),
+		},
+		{
+			name: "set value kept",
+			observed: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec":       map[string]interface{}{"replicas": int64(3)},
+			},
+			want: int64(3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := map[string]Resource{
+				"deployment": newTestResource(),
+				"service": newTestResource(
+					withObject(map[string]interface{}{
+						"spec": map[string]interface{}{"replicas": "${deployment.spec.replicas}"},
+					}),
+					withDependencies([]string{"deployment"}),
+					withVariables([]*variable.ResourceField{
+						{
+							FieldDescriptor: variable.FieldDescriptor{
+								Path:                 "spec.replicas",
+								Expressions:          []string{"deployment.spec.replicas"},
+								StandaloneExpression: true,
+							},
+							Kind:         variable.ResourceVariableKindDynamic,
+							Dependencies: []string{"deployment"},
+						},
+					}),
+				),
+			}
+			rt, err := NewResourceGraphDefinitionRuntime(
+				newTestResource(), resources, []string{"deployment", "service"},
+				WithDefaulter(deploymentGVK, defaultReplicas),
+			)
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+			rt.SetResource("deployment", &unstructured.Unstructured{Object: tt.observed})
+			if _, err := rt.Synchronize(); err != nil {
+				t.Fatalf("Synchronize() error = %v", err)
+			}
+
+			service, state := rt.GetResource("service")
+			if state != ResourceStateResolved {
+				t.Fatalf("service state = %v, want %v", state, ResourceStateResolved)
+			}
+			got, _, _ := unstructured.NestedFieldNoCopy(service.Object, "spec", "replicas")
+			if got != tt.want {
+				t.Errorf("service spec.replicas = %v, want %v", got, tt.want)
+			}
+
+			// The observed object is left untouched.
+			if _, found, _ := unstructured.NestedFieldNoCopy(tt.observed, "spec", "replicas"); found != (tt.want == int64(3)) {
+				t.Errorf("observed object modified: %v", tt.observed)
+			}
+		})
+	}
+}
+
+func Test_DefaulterOtherKinds(t *testing.T) {
+	called := false
+	rt := &ResourceGraphDefinitionRuntime{
+		defaulters: map[schema.GroupVersionKind]Defaulter{
+			{Group: "apps", Version: "v1", Kind: "Deployment"}: func(map[string]interface{}) { called = true },
+		},
+	}
+	observed := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Service"}}
+	if _, err := rt.observedValue("service", observed); err != nil {
+		t.Fatalf("observedValue() error = %v", err)
+	}
+	if called {
+		t.Errorf("defaulter called for another kind")
+	}
+}
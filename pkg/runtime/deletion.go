@@ -0,0 +1,93 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kro-run/kro/pkg/graph/parser"
+)
+
+const (
+	// DeletionPolicyDelete deletes the resource along with the instance.
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyOrphan leaves the resource behind, after removing the
+	// references to the instance owning it.
+	DeletionPolicyOrphan = "Orphan"
+	// DeletionPolicyRetain leaves the resource untouched.
+	DeletionPolicyRetain = "Retain"
+)
+
+// GetDeletionPolicy returns the deletion policy of the resource (see
+// ResourceDescriptor.GetDeletionPolicy). The expressions embedded in the
+// policy are evaluated against the instance and the resolved resources,
+// ignored resources being null.
+func (rt *ResourceGraphDefinitionRuntime) GetDeletionPolicy(id string) (string, error) {
+	resource, ok := rt.resources[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownResource, id)
+	}
+
+	policy := resource.GetDeletionPolicy()
+	expressions, err := parser.ExtractExpressions(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed parsing deletion policy of resource %s: %w", id, err)
+	}
+	if len(expressions) > 0 {
+		policy, err = rt.renderWithCurrentState(policy)
+		if err != nil {
+			return "", fmt.Errorf("failed evaluating deletion policy of resource %s: %w", id, err)
+		}
+	}
+
+	switch policy {
+	case "":
+		return DeletionPolicyDelete, nil
+	case DeletionPolicyDelete, DeletionPolicyOrphan, DeletionPolicyRetain:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid deletion policy %q for resource %s, must be one of %s",
+			policy, id, strings.Join([]string{DeletionPolicyDelete, DeletionPolicyOrphan, DeletionPolicyRetain}, ", "))
+	}
+}
+
+// renderWithCurrentState evaluates the expressions embedded in the given
+// template against the instance and the resolved resources, the other
+// resources being null.
+func (rt *ResourceGraphDefinitionRuntime) renderWithCurrentState(template string) (string, error) {
+	resourcesList, err := rt.resolvedResourcesList()
+	if err != nil {
+		return "", err
+	}
+	ids := make([]string, 0, len(rt.resources))
+	context := rt.newEvalContext()
+	context[resourcesVariableName] = resourcesList
+	for id := range rt.resources {
+		ids = append(ids, id)
+		context[id] = nil
+		if observed, ok := rt.resolvedResources[id]; ok && observed != nil {
+			value, err := rt.observedValue(id, observed)
+			if err != nil {
+				return "", err
+			}
+			context[id] = value
+		}
+	}
+	env, err := rt.dynamicEnvironment(ids)
+	if err != nil {
+		return "", err
+	}
+	return rt.renderMessage(env, context, template)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_GetDeletionPolicy(t *testing.T) {
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"environment": "production"},
+	}))
+	resources := map[string]Resource{
+		"default": newTestResource(),
+		"deleted": newTestResource(withDeletionPolicy(DeletionPolicyDelete)),
+		"orphan":  newTestResource(withDeletionPolicy(DeletionPolicyOrphan)),
+		"retain":  newTestResource(withDeletionPolicy(DeletionPolicyRetain)),
+		"invalid": newTestResource(withDeletionPolicy("Archive")),
+		"database": newTestResource(withDeletionPolicy(
+			`${schema.spec.environment == "production" ? "Retain" : "Delete"}`,
+		)),
+		"volume": newTestResource(withDeletionPolicy(
+			`${database != null && database.metadata.labels.backup == "true" ? "Orphan" : "Delete"}`,
+		)),
+	}
+	order := []string{"default", "deleted", "orphan", "retain", "invalid", "database", "volume"}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"default", DeletionPolicyDelete},
+		{"deleted", DeletionPolicyDelete},
+		{"orphan", DeletionPolicyOrphan},
+		{"retain", DeletionPolicyRetain},
+		{"database", DeletionPolicyRetain},
+		// The database isn't resolved yet.
+		{"volume", DeletionPolicyDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := rt.GetDeletionPolicy(tt.id)
+			if err != nil {
+				t.Fatalf("GetDeletionPolicy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetDeletionPolicy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "database",
+			"labels": map[string]interface{}{"backup": "true"},
+		},
+	}})
+	if got, err := rt.GetDeletionPolicy("volume"); err != nil || got != DeletionPolicyOrphan {
+		t.Errorf("GetDeletionPolicy() = %s, %v, want %s", got, err, DeletionPolicyOrphan)
+	}
+
+	if _, err := rt.GetDeletionPolicy("invalid"); err == nil {
+		t.Errorf("GetDeletionPolicy() of an invalid policy error = nil, want error")
+	}
+	if _, err := rt.GetDeletionPolicy("unknown"); !errors.Is(err, ErrUnknownResource) {
+		t.Errorf("GetDeletionPolicy() of an unknown resource error = %v, want %v", err, ErrUnknownResource)
+	}
+}
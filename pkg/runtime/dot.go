@@ -0,0 +1,77 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Resolution states reported by DependencyGraphDOT.
+const (
+	dotStateResolved = "resolved"
+	dotStatePending  = "pending"
+	dotStateIgnored  = "ignored"
+	dotStateError    = "error"
+)
+
+// dotStateColors are the fill colors of the nodes, by resolution state.
+var dotStateColors = map[string]string{
+	dotStateResolved: "palegreen",
+	dotStatePending:  "lightgoldenrod",
+	dotStateIgnored:  "lightgrey",
+	dotStateError:    "salmon",
+}
+
+// DependencyGraphDOT returns the resource dependency graph in the Graphviz
+// DOT format, e.g to be piped to `dot -Tsvg`. Nodes are colored by
+// resolution state (resolved, pending, ignored or error), and edges go from
+// a dependency to the resources depending on it.
+func (rt *ResourceGraphDefinitionRuntime) DependencyGraphDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph resources {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled];\n")
+	for _, id := range rt.topologicalOrder {
+		state := rt.dotResourceState(id)
+		fmt.Fprintf(&b, "  %q [label=%q, fillcolor=%q];\n", id, id+"\n"+state, dotStateColors[state])
+	}
+	for _, id := range rt.topologicalOrder {
+		dependencies := slices.Clone(rt.resources[id].GetDependencies())
+		slices.Sort(dependencies)
+		for _, dep := range dependencies {
+			fmt.Fprintf(&b, "  %q -> %q;\n", dep, id)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotResourceState returns the resolution state of the resource, without
+// evaluating anything.
+func (rt *ResourceGraphDefinitionRuntime) dotResourceState(id string) string {
+	for _, state := range rt.runtimeVariables[id] {
+		if state.Failed {
+			return dotStateError
+		}
+	}
+	if rt.ignoredByConditionsResources[id] {
+		return dotStateIgnored
+	}
+	if _, ok := rt.resolvedResources[id]; ok || rt.canProcessResource(id) {
+		return dotStateResolved
+	}
+	return dotStatePending
+}
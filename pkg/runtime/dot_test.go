@@ -0,0 +1,61 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_DependencyGraphDOT(t *testing.T) {
+	resources := map[string]Resource{
+		"vpc":     newTestResource(),
+		"subnet":  newTestResource(withDependencies([]string{"vpc"})),
+		"cache":   newTestResource(),
+		"cluster": newTestResource(withDependencies([]string{"vpc", "subnet"})),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"cache", "vpc", "subnet", "cluster"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.IgnoreResource("cache")
+	rt.runtimeVariables["subnet"] = []*expressionEvaluationState{
+		{Expression: "vpc.status.id", Kind: variable.ResourceVariableKindDynamic, Resolved: true},
+	}
+	rt.runtimeVariables["cluster"] = []*expressionEvaluationState{
+		{Expression: "subnet.status.id", Kind: variable.ResourceVariableKindDynamic},
+	}
+
+	want := `digraph resources {
+  rankdir=LR;
+  node [shape=box, style=filled];
+  "cache" [label="cache\nignored", fillcolor="lightgrey"];
+  "vpc" [label="vpc\nresolved", fillcolor="palegreen"];
+  "subnet" [label="subnet\nresolved", fillcolor="palegreen"];
+  "cluster" [label="cluster\npending", fillcolor="lightgoldenrod"];
+  "vpc" -> "subnet";
+  "subnet" -> "cluster";
+  "vpc" -> "cluster";
+}
+`
+	if got := rt.DependencyGraphDOT(); got != want {
+		t.Errorf("DependencyGraphDOT() =\n%s\nwant\n%s", got, want)
+	}
+
+	rt.runtimeVariables["cluster"][0].Failed = true
+	if got := rt.dotResourceState("cluster"); got != dotStateError {
+		t.Errorf("dotResourceState(cluster) = %q, want %q", got, dotStateError)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Evaluator evaluates expressions against an evaluation context. The context
+// maps the variable names (e.g "schema", "instance" or resource ids) to
+// their values.
+//
+// CEL is the default evaluator. Alternative evaluators can be registered with
+// WithEvaluator, and are used for the expressions prefixed with their name.
+// e.g "tmpl:{{ .schema.spec.name }}" is evaluated by the evaluator registered
+// as "tmpl", with "{{ .schema.spec.name }}" as expression.
+//
+// Static expressions are evaluated concurrently at construction, the runtime
+// serializes the calls to the evaluators so that they don't have to be safe
+// for concurrent use.
+//
+// NOTE: The graph builder only understands CEL, alternative evaluators are
+// only supported by the runtime for now.
+type Evaluator interface {
+	// Evaluate evaluates the expression and returns its value as a Go native
+	// type. e.g string, int64, []interface{}, map[string]interface{}...
+	Evaluate(expression string, context map[string]interface{}) (interface{}, error)
+}
+
+// celEvaluator is the default Evaluator, evaluating CEL expressions.
+//
+// Compilation errors are not going to be fixed by resolving more resources,
+// so instead of compiling (and failing) again on every Synchronize call, the
+// first error is cached and returned for every subsequent evaluation of the
+// same expression with the same environment.
+type celEvaluator struct {
+	env *cel.Env
+	// compilationErrors caches the compilation errors, keyed by environment
+	// and expression.
+	compilationErrors map[compilationErrorKey]error
+	// featureEnvironment returns the environment extended with the
+	// declarations gated behind the feature flags required by an expression.
+	featureEnvironment func(env *cel.Env, expression string) (*cel.Env, error)
+}
+
+// compilationErrorKey identifies the compilation of an expression. The same
+// expression may compile with an environment and fail with another one, e.g
+// readyWhen expressions don't see the same functions as the resources ones.
+type compilationErrorKey struct {
+	env        *cel.Env
+	expression string
+}
+
+// Evaluate implements Evaluator.
+func (e *celEvaluator) Evaluate(expression string, context map[string]interface{}) (interface{}, error) {
+	key := compilationErrorKey{env: e.env, expression: expression}
+	if err, ok := e.compilationErrors[key]; ok {
+		return nil, err
+	}
+	env := e.env
+	if e.featureEnvironment != nil {
+		var err error
+		env, err = e.featureEnvironment(env, expression)
+		if err != nil {
+			e.compilationErrors[key] = err
+			return nil, err
+		}
+	}
+	program, err := compileExpression(env, expression)
+	if err != nil {
+		// Undeclared references depend on the environment, which grows as
+		// resources get resolved. Only cache errors that can't go away.
+		if !strings.Contains(err.Error(), "undeclared reference") {
+			e.compilationErrors[key] = err
+		}
+		return nil, err
+	}
+	return evaluateProgram(program, context, expression)
+}
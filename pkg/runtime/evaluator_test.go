@@ -0,0 +1,260 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// pathEvaluator is a trivial evaluator, looking up dot separated paths in the
+// evaluation context. e.g "schema.spec.name".
+type pathEvaluator struct{}
+
+func (pathEvaluator) Evaluate(expression string, context map[string]interface{}) (interface{}, error) {
+	value, found, err := unstructured.NestedFieldNoCopy(context, strings.Split(expression, ".")...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("path %s not found", expression)
+	}
+	return value, nil
+}
+
+func Test_celEvaluator(t *testing.T) {
+	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	if err != nil {
+		t.Fatalf("DefaultEnvironment() error = %v", err)
+	}
+	evaluator := &celEvaluator{env: env, compilationErrors: map[compilationErrorKey]error{}}
+	context := map[string]interface{}{
+		"schema": map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(2)},
+		},
+	}
+
+	got, err := evaluator.Evaluate("schema.spec.replicas * 2", context)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if got != int64(4) {
+		t.Errorf("Evaluate() = %v, want 4", got)
+	}
+
+	if _, err := evaluator.Evaluate("schema.spec.replicas *", context); err == nil {
+		t.Fatal("Evaluate() expected error, got none")
+	}
+	if _, ok := evaluator.compilationErrors[compilationErrorKey{env: env, expression: "schema.spec.replicas *"}]; !ok {
+		t.Error("Evaluate() didn't cache the compilation error")
+	}
+}
+
+func Test_celEvaluator_CompilationErrorsByEnvironment(t *testing.T) {
+	const expr = `twice("a")`
+	withInt, err := cel.NewEnv(cel.Function("twice",
+		cel.Overload("twice_int", []*cel.Type{cel.IntType}, cel.IntType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val { return v.(types.Int) * 2 }))))
+	if err != nil {
+		t.Fatalf("NewEnv() error = %v", err)
+	}
+	withString, err := cel.NewEnv(cel.Function("twice",
+		cel.Overload("twice_string", []*cel.Type{cel.StringType}, cel.StringType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val { return v.(types.String) + v.(types.String) }))))
+	if err != nil {
+		t.Fatalf("NewEnv() error = %v", err)
+	}
+
+	// Both evaluators share the cache, like the evaluators of a runtime.
+	compilationErrors := map[compilationErrorKey]error{}
+	if _, err := (&celEvaluator{env: withInt, compilationErrors: compilationErrors}).Evaluate(expr, nil); err == nil {
+		t.Fatal("Evaluate() expected error, got none")
+	}
+	got, err := (&celEvaluator{env: withString, compilationErrors: compilationErrors}).Evaluate(expr, nil)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v, the error of another environment was returned", err)
+	}
+	if got != "aa" {
+		t.Errorf("Evaluate() = %v, want aa", got)
+	}
+}
+
+func Test_WithEvaluator(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"name": "myapp"},
+		}),
+	)
+	configmap := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "${path:schema.spec.name}",
+			},
+			"data": map[string]interface{}{
+				"length": "${size(schema.spec.name)}",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.name",
+					Expressions:          []string{"path:schema.spec.name"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "data.length",
+					Expressions:          []string{"size(schema.spec.name)"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+		}),
+	)
+	deployment := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"configName": "${path:configmap.metadata.name}",
+			},
+		}),
+		withDependencies([]string{"configmap"}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "spec.configName",
+					Expressions:          []string{"path:configmap.metadata.name"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"configmap"},
+			},
+		}),
+	)
+
+	rt, err := NewResourceGraphDefinitionRuntime(
+		instance,
+		map[string]Resource{"configmap": configmap, "deployment": deployment},
+		[]string{"configmap", "deployment"},
+		WithEvaluator("path", pathEvaluator{}),
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	cm, state := rt.GetResource("configmap")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource(configmap) state = %v, want %v", state, ResourceStateResolved)
+	}
+	if cm.GetName() != "myapp" {
+		t.Errorf("configmap name = %q, want %q", cm.GetName(), "myapp")
+	}
+	// expressions without a registered prefix are still evaluated by CEL.
+	if length := cm.Object["data"].(map[string]interface{})["length"]; length != int64(5) {
+		t.Errorf("configmap data.length = %v, want 5", length)
+	}
+
+	rt.SetResource("configmap", cm)
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	obj, state := rt.GetResource("deployment")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource(deployment) state = %v, want %v", state, ResourceStateResolved)
+	}
+	if name := obj.Object["spec"].(map[string]interface{})["configName"]; name != "myapp" {
+		t.Errorf("deployment spec.configName = %v, want %q", name, "myapp")
+	}
+}
+
+// countingEvaluator is a pathEvaluator counting its calls, it isn't safe for
+// concurrent use.
+type countingEvaluator struct {
+	pathEvaluator
+	calls int
+}
+
+func (e *countingEvaluator) Evaluate(expression string, context map[string]interface{}) (interface{}, error) {
+	e.calls++
+	return e.pathEvaluator.Evaluate(expression, context)
+}
+
+func Test_evaluateStaticVariables_ConcurrentEvaluators(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	spec := map[string]interface{}{}
+	cache := map[string]*expressionEvaluationState{}
+	for i := 0; i < 50; i++ {
+		spec[fmt.Sprintf("field%d", i)] = int64(i)
+		for _, expr := range []string{
+			fmt.Sprintf("count:schema.spec.field%d", i),
+			fmt.Sprintf("schema.spec.field%d * 2", i),
+		} {
+			cache[expr] = &expressionEvaluationState{Expression: expr, Kind: variable.ResourceVariableKindStatic}
+		}
+	}
+	evaluator := &countingEvaluator{}
+	rt := &ResourceGraphDefinitionRuntime{
+		instance:                newTestResource(withObject(map[string]interface{}{"spec": spec})),
+		expressionsCache:        cache,
+		staticEvaluationWorkers: 8,
+		evaluators:              map[string]Evaluator{"count": evaluator},
+		tracer:                  provider.Tracer("test"),
+	}
+
+	// The evaluations are traced as children of the ongoing span.
+	ctx, parent := rt.tracer.Start(context.Background(), synchronizeSpanName)
+	rt.traceContext = ctx
+	err := rt.evaluateStaticVariables()
+	parent.End()
+	if err != nil {
+		t.Fatalf("evaluateStaticVariables() error = %v", err)
+	}
+
+	if evaluator.calls != 50 {
+		t.Errorf("evaluator calls = %d, want 50", evaluator.calls)
+	}
+	for i := 0; i < 50; i++ {
+		if got := cache[fmt.Sprintf("count:schema.spec.field%d", i)].ResolvedValue; got != int64(i) {
+			t.Errorf("count:schema.spec.field%d = %v, want %d", i, got, i)
+		}
+	}
+	evaluations := 0
+	for _, span := range exporter.GetSpans() {
+		if span.Name != evaluationSpanName {
+			continue
+		}
+		evaluations++
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("evaluation span parent = %v, want the ongoing span", span.Parent.SpanID())
+		}
+	}
+	if evaluations != 100 {
+		t.Errorf("evaluation spans = %d, want 100", evaluations)
+	}
+}
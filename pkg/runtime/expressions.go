@@ -0,0 +1,238 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+)
+
+// ErrUnknownExpression is returned when looking up an expression that isn't
+// used by the graph.
+var ErrUnknownExpression = errors.New("unknown expression")
+
+// ExpressionState returns whether the expression is resolved, and its
+// resolved value. It fails with ErrUnknownExpression if the expression isn't
+// used by the graph.
+func (rt *ResourceGraphDefinitionRuntime) ExpressionState(expression string) (bool, interface{}, error) {
+	state, ok := rt.expressionsCache[expression]
+	if !ok {
+		return false, nil, fmt.Errorf("%w: %s", ErrUnknownExpression, expression)
+	}
+	return state.Resolved, state.ResolvedValue, nil
+}
+
+// ResolvedValueType returns the type name of the resolved value of the
+// expression, following the CEL type names: null, bool, int, uint, double,
+// string, bytes, timestamp, duration, list or map. Structs, e.g typed
+// resources, are reported as maps. It returns false if the expression isn't
+// resolved, or isn't used by the graph.
+func (rt *ResourceGraphDefinitionRuntime) ResolvedValueType(expression string) (string, bool) {
+	state, ok := rt.expressionsCache[expression]
+	if !ok || !state.Resolved {
+		return "", false
+	}
+	return valueTypeName(state.ResolvedValue), true
+}
+
+// valueTypeName returns the CEL type name of the Go value.
+func valueTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil, krocel.ExplicitNullValue:
+		return "null"
+	case time.Time:
+		return "timestamp"
+	case time.Duration:
+		return "duration"
+	case []byte:
+		return "bytes"
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null"
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "map"
+	default:
+		return v.Kind().String()
+	}
+}
+
+// ClearExpression resets the expression to unresolved, e.g when its value is
+// known to be stale. Dynamic expressions are evaluated again on the next
+// Synchronize call, while static expressions, which Synchronize never
+// evaluates, are evaluated again right away.
+//
+// It fails with ErrUnknownExpression if the expression isn't used by the
+// graph.
+func (rt *ResourceGraphDefinitionRuntime) ClearExpression(expression string) error {
+	state, ok := rt.expressionsCache[expression]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExpression, expression)
+	}
+	state.Resolved = false
+	state.ResolvedValue = nil
+	state.ResolvedToNull = false
+	state.IncompleteDataAttempts = 0
+	state.Failed = false
+	for key := range rt.compilationErrors {
+		if key.expression == expression {
+			delete(rt.compilationErrors, key)
+		}
+	}
+
+	if !state.Kind.IsStatic() {
+		return nil
+	}
+	env, err := rt.staticEnvironment()
+	if err != nil {
+		return err
+	}
+	value, err := rt.evaluate(env, rt.newEvalContext(), expression)
+	if err != nil {
+		rt.markExpressionFailed(state, err)
+		return fmt.Errorf("failed to evaluate static expression %s: %w", expression, err)
+	}
+	state.Resolved = true
+	state.ResolvedValue = value
+	return nil
+}
+
+// ResetStaticVariables evaluates the static expressions again, e.g after the
+// instance spec changed, and returns the expressions whose resolved value
+// changed, sorted. The resources using them are rendered again from their
+// templates. An empty result means the spec change didn't affect any static
+// value.
+func (rt *ResourceGraphDefinitionRuntime) ResetStaticVariables() ([]string, error) {
+	previous := map[string]interface{}{}
+	for expression, state := range rt.expressionsCache {
+		if !state.Kind.IsStatic() {
+			continue
+		}
+		if state.Resolved {
+			previous[expression] = state.ResolvedValue
+		}
+		state.Resolved = false
+		state.ResolvedValue = nil
+		state.Failed = false
+	}
+
+	if err := rt.evaluateStaticVariables(); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for expression, state := range rt.expressionsCache {
+		if !state.Kind.IsStatic() {
+			continue
+		}
+		old, wasResolved := previous[expression]
+		if !wasResolved || !reflect.DeepEqual(old, state.ResolvedValue) {
+			changed = append(changed, expression)
+		}
+	}
+	slices.Sort(changed)
+
+	consumers := make(map[string]bool)
+	for _, expression := range changed {
+		for _, consumer := range rt.expressionConsumers[expression] {
+			consumers[consumer] = true
+		}
+	}
+	for _, consumer := range rt.topologicalOrder {
+		if !consumers[consumer] {
+			continue
+		}
+		// The rendered fields replaced their templates, which are needed to
+		// render the fields again.
+		if err := rt.restoreTemplateFields(consumer); err != nil {
+			return nil, fmt.Errorf("failed to restore the templates of resource %s: %w", consumer, err)
+		}
+		if !rt.canProcessResource(consumer) {
+			continue
+		}
+		if err := rt.evaluateResourceExpressions(consumer); err != nil {
+			return nil, fmt.Errorf("failed to evaluate resource variables for %s: %w", consumer, err)
+		}
+	}
+	if consumers["instance"] {
+		if err := rt.evaluateInstanceStatuses(); err != nil {
+			return nil, fmt.Errorf("failed to evaluate instance statuses: %w", err)
+		}
+	}
+	return changed, nil
+}
+
+// InjectEvaluationError makes the next evaluation of the expression fail with
+// the given error, whatever its inputs, e.g to test how controllers handle
+// and recover from evaluation errors. The error is returned once, the
+// following evaluations succeed again.
+func (rt *ResourceGraphDefinitionRuntime) InjectEvaluationError(expression string, err error) {
+	rt.injectedErrorsLock.Lock()
+	defer rt.injectedErrorsLock.Unlock()
+	if rt.injectedErrors == nil {
+		rt.injectedErrors = make(map[string]error)
+	}
+	rt.injectedErrors[expression] = err
+}
+
+// ClearInjectedError removes the error injected for the expression by
+// InjectEvaluationError, if it wasn't returned yet.
+func (rt *ResourceGraphDefinitionRuntime) ClearInjectedError(expression string) {
+	rt.injectedErrorsLock.Lock()
+	defer rt.injectedErrorsLock.Unlock()
+	delete(rt.injectedErrors, expression)
+}
+
+// hasInjectedError returns true if an error is injected for the expression.
+func (rt *ResourceGraphDefinitionRuntime) hasInjectedError(expression string) bool {
+	rt.injectedErrorsLock.Lock()
+	defer rt.injectedErrorsLock.Unlock()
+	_, ok := rt.injectedErrors[expression]
+	return ok
+}
+
+// takeInjectedError returns the error injected for the expression, if any,
+// and removes it.
+func (rt *ResourceGraphDefinitionRuntime) takeInjectedError(expression string) error {
+	rt.injectedErrorsLock.Lock()
+	defer rt.injectedErrorsLock.Unlock()
+	err, ok := rt.injectedErrors[expression]
+	if !ok {
+		return nil
+	}
+	delete(rt.injectedErrors, expression)
+	return err
+}
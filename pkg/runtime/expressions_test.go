@@ -0,0 +1,253 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ClearExpression(t *testing.T) {
+	const (
+		staticExpr  = "schema.spec.name"
+		dynamicExpr = "dep.spec.value"
+	)
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"name": "first"},
+	}))
+	resources := map[string]Resource{
+		"dep": newTestResource(),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${" + staticExpr + "}"},
+				"data":     map[string]interface{}{"value": "${" + dynamicExpr + "}"},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{staticExpr},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{dynamicExpr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"dep", "consumer"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	checkState := func(expr string, wantResolved bool, wantValue interface{}) {
+		t.Helper()
+		resolved, value, err := rt.ExpressionState(expr)
+		if err != nil {
+			t.Fatalf("ExpressionState(%q) error = %v", expr, err)
+		}
+		if resolved != wantResolved || value != wantValue {
+			t.Errorf("ExpressionState(%q) = (%v, %v), want (%v, %v)", expr, resolved, value, wantResolved, wantValue)
+		}
+	}
+	observeDep := func(value string) {
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": value},
+		}})
+	}
+
+	observeDep("old")
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	checkState(staticExpr, true, "first")
+	checkState(dynamicExpr, true, "old")
+
+	// The dependency changed, the resolved value is stale until cleared.
+	observeDep("new")
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	checkState(dynamicExpr, true, "old")
+
+	if err := rt.ClearExpression(dynamicExpr); err != nil {
+		t.Fatalf("ClearExpression() error = %v", err)
+	}
+	checkState(dynamicExpr, false, nil)
+	// Other expressions are left untouched.
+	checkState(staticExpr, true, "first")
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	checkState(dynamicExpr, true, "new")
+
+	// Static expressions are evaluated again right away.
+	rt.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"name": "second"},
+	}})
+	if err := rt.ClearExpression(staticExpr); err != nil {
+		t.Fatalf("ClearExpression() error = %v", err)
+	}
+	checkState(staticExpr, true, "second")
+}
+
+func Test_ClearExpression_Unknown(t *testing.T) {
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), map[string]Resource{}, []string{})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	if err := rt.ClearExpression("unknown.expression"); !errors.Is(err, ErrUnknownExpression) {
+		t.Errorf("ClearExpression() error = %v, want %v", err, ErrUnknownExpression)
+	}
+	if _, _, err := rt.ExpressionState("unknown.expression"); !errors.Is(err, ErrUnknownExpression) {
+		t.Errorf("ExpressionState() error = %v, want %v", err, ErrUnknownExpression)
+	}
+}
+
+func Test_ResetStaticVariables(t *testing.T) {
+	const (
+		nameExpr     = "schema.spec.name"
+		upperExpr    = "schema.spec.name.upperAscii()"
+		replicasExpr = "schema.spec.replicas * 2"
+	)
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"name": "first", "replicas": int64(1)},
+	}))
+	staticField := func(path, expression string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expression},
+				StandaloneExpression: true,
+			},
+			Kind: variable.ResourceVariableKindStatic,
+		}
+	}
+	resources := map[string]Resource{
+		"deployment": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${" + nameExpr + "}"},
+				"spec": map[string]interface{}{
+					"replicas": "${" + replicasExpr + "}",
+					"label":    "${" + upperExpr + "}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				staticField("metadata.name", nameExpr),
+				staticField("spec.replicas", replicasExpr),
+				staticField("spec.label", upperExpr),
+			}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// Nothing changed.
+	changed, err := rt.ResetStaticVariables()
+	if err != nil {
+		t.Fatalf("ResetStaticVariables() error = %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("ResetStaticVariables() = %v, want no changes", changed)
+	}
+
+	// Only the replicas are edited.
+	rt.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"name": "first", "replicas": int64(3)},
+	}})
+	changed, err = rt.ResetStaticVariables()
+	if err != nil {
+		t.Fatalf("ResetStaticVariables() error = %v", err)
+	}
+	if want := []string{replicasExpr}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("ResetStaticVariables() = %v, want %v", changed, want)
+	}
+	if _, value, _ := rt.ExpressionState(replicasExpr); value != int64(6) {
+		t.Errorf("%s = %v, want 6", replicasExpr, value)
+	}
+	deployment, _ := rt.GetResource("deployment")
+	if replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas"); replicas != 6 {
+		t.Errorf("deployment spec.replicas = %v, want 6", replicas)
+	}
+
+	// The name is edited, affecting the two expressions using it.
+	rt.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"name": "second", "replicas": int64(3)},
+	}})
+	changed, err = rt.ResetStaticVariables()
+	if err != nil {
+		t.Fatalf("ResetStaticVariables() error = %v", err)
+	}
+	if want := []string{nameExpr, upperExpr}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("ResetStaticVariables() = %v, want %v", changed, want)
+	}
+	deployment, _ = rt.GetResource("deployment")
+	if name := deployment.GetName(); name != "second" {
+		t.Errorf("deployment metadata.name = %q, want second", name)
+	}
+	if label, _, _ := unstructured.NestedString(deployment.Object, "spec", "label"); label != "SECOND" {
+		t.Errorf("deployment spec.label = %q, want SECOND", label)
+	}
+}
+
+func Test_ResolvedValueType(t *testing.T) {
+	want := map[string]string{
+		"dep.spec.value":            "string",
+		"dep.spec.count":            "int",
+		"double(dep.spec.count)":    "double",
+		"dep.spec.count > 1":        "bool",
+		"dep.spec":                  "map",
+		`dep.spec.value.split("l")`: "list",
+		"has(dep.spec.missing) ? dep.spec.missing : null": "null",
+	}
+	expressions := make([]string, 0, len(want))
+	for expression := range want {
+		expressions = append(expressions, expression)
+	}
+	rt := newConsumersRuntime(t, expressions...)
+
+	// Not resolved yet.
+	if got, ok := rt.ResolvedValueType("dep.spec.value"); ok {
+		t.Errorf("ResolvedValueType() = %q before resolution, want false", got)
+	}
+
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	for expression, wantType := range want {
+		got, ok := rt.ResolvedValueType(expression)
+		if !ok || got != wantType {
+			t.Errorf("ResolvedValueType(%q) = %q, %v, want %q", expression, got, ok, wantType)
+		}
+	}
+	if got, ok := rt.ResolvedValueType("unknown.expression"); ok {
+		t.Errorf("ResolvedValueType() = %q for an unknown expression, want false", got)
+	}
+}
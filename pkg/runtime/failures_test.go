@@ -0,0 +1,218 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_OnExpressionFailed(t *testing.T) {
+	newRuntime := func(t *testing.T, expr string, opts ...Option) *ResourceGraphDefinitionRuntime {
+		resources := map[string]Resource{
+			"dep": newTestResource(),
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"data": map[string]interface{}{"value": "${" + expr + "}"},
+				}),
+				withDependencies([]string{"dep"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "data.value",
+							Expressions:          []string{expr},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"dep"},
+					},
+				}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": "not a number"},
+		}})
+		return rt
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		threshold int
+		// wantIncomplete is the number of Synchronize calls expected to
+		// report incomplete data before the expression fails.
+		wantIncomplete int
+	}{
+		{
+			name:           "hard error fails immediately",
+			expr:           "dep.spec.value + 1",
+			wantIncomplete: 0,
+		},
+		{
+			name:           "incomplete data fails after the threshold",
+			expr:           "dep.status.value",
+			threshold:      3,
+			wantIncomplete: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failures []string
+			rt := newRuntime(t, tt.expr,
+				WithIncompleteDataThreshold(tt.threshold),
+				WithOnExpressionFailed(func(expression string, err error) {
+					failures = append(failures, expression)
+				}),
+			)
+
+			for i := 0; i < tt.wantIncomplete+3; i++ {
+				_, err := rt.Synchronize()
+				var evalErr *EvalError
+				if !errors.As(err, &evalErr) {
+					t.Fatalf("Synchronize() error = %v, want *EvalError", err)
+				}
+				if wantIncomplete := i < tt.wantIncomplete; evalErr.IsIncompleteData != wantIncomplete {
+					t.Fatalf("Synchronize() #%d IsIncompleteData = %v, want %v", i, evalErr.IsIncompleteData, wantIncomplete)
+				}
+				wantFailures := 0
+				if i >= tt.wantIncomplete {
+					wantFailures = 1
+				}
+				if len(failures) != wantFailures {
+					t.Fatalf("Synchronize() #%d failures = %v, want %d", i, failures, wantFailures)
+				}
+			}
+			if failures[0] != tt.expr {
+				t.Errorf("failed expression = %q, want %q", failures[0], tt.expr)
+			}
+			if !rt.expressionsCache[tt.expr].Failed {
+				t.Errorf("expression %q is not marked as failed", tt.expr)
+			}
+		})
+	}
+
+	t.Run("nil callback", func(t *testing.T) {
+		rt := newRuntime(t, "dep.spec.value + 1")
+		if _, err := rt.Synchronize(); err == nil {
+			t.Fatal("Synchronize() error = nil, want error")
+		}
+		if !rt.expressionsCache["dep.spec.value + 1"].Failed {
+			t.Error("expression is not marked as failed")
+		}
+	})
+}
+
+func Test_ArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		wantErr     error
+		wantMessage string
+	}{
+		{
+			name:        "division by zero",
+			expr:        "dep.spec.count / (dep.spec.count - 2)",
+			wantErr:     ErrDivisionByZero,
+			wantMessage: "division by zero in expression dep.spec.count / (dep.spec.count - 2)",
+		},
+		{
+			name:        "modulus by zero",
+			expr:        "dep.spec.count % (dep.spec.count - 2)",
+			wantErr:     ErrDivisionByZero,
+			wantMessage: "division by zero in expression dep.spec.count % (dep.spec.count - 2)",
+		},
+		{
+			name:        "int64 overflow",
+			expr:        "dep.spec.count * 9223372036854775807",
+			wantErr:     ErrArithmeticOverflow,
+			wantMessage: "expression dep.spec.count * 9223372036854775807 overflowed int64",
+		},
+		{
+			name:        "uint64 overflow",
+			expr:        "uint(dep.spec.count) - 3u",
+			wantErr:     ErrArithmeticOverflow,
+			wantMessage: "expression uint(dep.spec.count) - 3u overflowed uint64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// dep.spec.count is 2.
+			rt := newConsumersRuntime(t, tt.expr)
+			_, err := rt.Synchronize()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Synchronize() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("Synchronize() error = %v, want %q", err, tt.wantMessage)
+			}
+			var evalErr *EvalError
+			if !errors.As(err, &evalErr) || evalErr.IsIncompleteData {
+				t.Errorf("Synchronize() error = %v, want a terminal *EvalError", err)
+			}
+			if !rt.expressionsCache[tt.expr].Failed {
+				t.Errorf("expression %q is not marked as failed", tt.expr)
+			}
+		})
+	}
+}
+
+func Test_InjectEvaluationError(t *testing.T) {
+	const expr = "dep.spec.value"
+	injected := errors.New("injected failure")
+
+	t.Run("failure and recovery", func(t *testing.T) {
+		rt := newConsumersRuntime(t, expr)
+		// Memoized results must not hide the injected error.
+		WithMemoCache(&countingMemoCache{values: map[string]interface{}{}})(rt)
+
+		rt.InjectEvaluationError(expr, injected)
+		if _, err := rt.Synchronize(); !errors.Is(err, injected) {
+			t.Fatalf("Synchronize() error = %v, want %v", err, injected)
+		}
+		if _, state := rt.GetResource("consumerA"); state != ResourceStateWaitingOnDependencies {
+			t.Errorf("GetResource() state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+		}
+
+		// The error is only returned once.
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if _, state := rt.GetResource("consumerA"); state != ResourceStateResolved {
+			t.Errorf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+	})
+
+	t.Run("cleared", func(t *testing.T) {
+		rt := newConsumersRuntime(t, expr)
+		rt.InjectEvaluationError(expr, injected)
+		rt.ClearInjectedError(expr)
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if _, state := rt.GetResource("consumerA"); state != ResourceStateResolved {
+			t.Errorf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+	})
+}
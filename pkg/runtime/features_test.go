@@ -0,0 +1,128 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func init() {
+	krocel.RegisterFeature("runtime-test-twice", cel.Function("twice",
+		cel.Overload("twice_int", []*cel.Type{cel.IntType}, cel.IntType,
+			cel.UnaryBinding(func(v ref.Val) ref.Val {
+				return v.(types.Int) * 2
+			}),
+		),
+	))
+}
+
+func Test_FeatureFlags(t *testing.T) {
+	const expr = "twice(schema.spec.replicas)"
+	newRuntime := func(requiredFeatures []string, opts ...Option) (*ResourceGraphDefinitionRuntime, error) {
+		instance := newTestResource(withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(21)},
+		}))
+		resources := map[string]Resource{
+			"deployment": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{"replicas": "${" + expr + "}"},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.replicas",
+							Expressions:          []string{expr},
+							StandaloneExpression: true,
+							RequiredFeatures:     requiredFeatures,
+						},
+						Kind: variable.ResourceVariableKindStatic,
+					},
+				}),
+			),
+		}
+		return NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment"}, opts...)
+	}
+
+	tests := []struct {
+		name             string
+		flags            map[string]bool
+		requiredFeatures []string
+		wantErr          string
+	}{
+		{
+			name:             "required flag enabled",
+			flags:            map[string]bool{"runtime-test-twice": true},
+			requiredFeatures: []string{"runtime-test-twice"},
+		},
+		{
+			name:             "required flag disabled",
+			flags:            map[string]bool{"runtime-test-twice": false},
+			requiredFeatures: []string{"runtime-test-twice"},
+			wantErr:          `requires feature flag "runtime-test-twice", which is disabled`,
+		},
+		{
+			name:    "flag enabled but not required",
+			flags:   map[string]bool{"runtime-test-twice": true},
+			wantErr: "unknown function 'twice'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := newRuntime(tt.requiredFeatures, WithFeatureFlags(tt.flags))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v, want %q", err, tt.wantErr)
+				}
+				if strings.Contains(tt.wantErr, "disabled") && !errors.Is(err, krocel.ErrFeatureDisabled) {
+					t.Errorf("NewResourceGraphDefinitionRuntime() error = %v, want ErrFeatureDisabled", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+			got := rt.expressionsCache[expr]
+			if !got.Resolved || got.ResolvedValue != int64(42) {
+				t.Errorf("expression %q = %v (resolved %v), want 42", expr, got.ResolvedValue, got.Resolved)
+			}
+
+			// The extended environment is built once, and reused.
+			env, err := rt.staticEnvironment()
+			if err != nil {
+				t.Fatalf("staticEnvironment() error = %v", err)
+			}
+			first, err := rt.featureEnvironment(env, expr)
+			if err != nil {
+				t.Fatalf("featureEnvironment() error = %v", err)
+			}
+			second, err := rt.featureEnvironment(env, expr)
+			if err != nil {
+				t.Fatalf("featureEnvironment() error = %v", err)
+			}
+			if first == env || first != second {
+				t.Error("featureEnvironment() didn't reuse the extended environment")
+			}
+		})
+	}
+}
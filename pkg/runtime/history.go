@@ -0,0 +1,77 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"time"
+)
+
+// defaultErrorHistorySize is the default number of evaluation errors kept per
+// expression, see WithErrorHistorySize.
+const defaultErrorHistorySize = 5
+
+// TimestampedError is an evaluation error, along with the time it happened.
+type TimestampedError struct {
+	Time time.Time
+	Err  error
+}
+
+// errorHistory is a bounded ring buffer of evaluation errors.
+type errorHistory struct {
+	errors []TimestampedError
+	// next is the index the next error is written at, once the buffer is
+	// full.
+	next int
+}
+
+// ErrorHistory returns the recent evaluation errors of the expression, oldest
+// first, e.g to diagnose an expression flapping between resolved and error.
+// Only the last errors are kept, see WithErrorHistorySize.
+func (rt *ResourceGraphDefinitionRuntime) ErrorHistory(expression string) []TimestampedError {
+	history, ok := rt.errorHistories[expression]
+	if !ok {
+		return nil
+	}
+	ordered := make([]TimestampedError, 0, len(history.errors))
+	ordered = append(ordered, history.errors[history.next:]...)
+	return append(ordered, history.errors[:history.next]...)
+}
+
+// recordEvaluationError records an evaluation error of the expression in its
+// history, evicting the oldest error when the history is full.
+func (rt *ResourceGraphDefinitionRuntime) recordEvaluationError(expression string, err error) {
+	size := rt.errorHistorySize
+	if size == 0 {
+		size = defaultErrorHistorySize
+	}
+	if size < 0 {
+		return
+	}
+	if rt.errorHistories == nil {
+		rt.errorHistories = make(map[string]*errorHistory)
+	}
+	history, ok := rt.errorHistories[expression]
+	if !ok {
+		history = &errorHistory{}
+		rt.errorHistories[expression] = history
+	}
+
+	entry := TimestampedError{Time: rt.currentTime(), Err: err}
+	if len(history.errors) < size {
+		history.errors = append(history.errors, entry)
+		return
+	}
+	history.errors[history.next] = entry
+	history.next = (history.next + 1) % size
+}
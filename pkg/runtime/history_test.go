@@ -0,0 +1,95 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_ErrorHistory(t *testing.T) {
+	const expr = "dep.spec.missing"
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("errors are recorded and bounded", func(t *testing.T) {
+		rt := newConsumersRuntime(t, expr)
+		WithErrorHistorySize(3)(rt)
+		clock := start
+		rt.now = func() time.Time { return clock }
+
+		for i := 0; i < 5; i++ {
+			if _, err := rt.Synchronize(); err == nil {
+				t.Fatal("Synchronize() error = nil, want incomplete data")
+			}
+			clock = clock.Add(time.Minute)
+		}
+
+		history := rt.ErrorHistory(expr)
+		if len(history) != 3 {
+			t.Fatalf("ErrorHistory() has %d errors, want 3", len(history))
+		}
+		// Only the last 3 errors are kept, oldest first.
+		for i, entry := range history {
+			if want := start.Add(time.Duration(i+2) * time.Minute); !entry.Time.Equal(want) {
+				t.Errorf("ErrorHistory()[%d].Time = %v, want %v", i, entry.Time, want)
+			}
+			if !strings.Contains(entry.Err.Error(), "no such key") {
+				t.Errorf("ErrorHistory()[%d].Err = %v, want a no such key error", i, entry.Err)
+			}
+		}
+
+		// Resolving the expression doesn't record anything.
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"missing": "found"},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if got := len(rt.ErrorHistory(expr)); got != 3 {
+			t.Errorf("ErrorHistory() has %d errors, want 3", got)
+		}
+	})
+
+	t.Run("default size", func(t *testing.T) {
+		rt := newConsumersRuntime(t, expr)
+		for i := 0; i < 2*defaultErrorHistorySize; i++ {
+			_, _ = rt.Synchronize()
+		}
+		if got := len(rt.ErrorHistory(expr)); got != defaultErrorHistorySize {
+			t.Errorf("ErrorHistory() has %d errors, want %d", got, defaultErrorHistorySize)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rt := newConsumersRuntime(t, expr)
+		WithErrorHistorySize(-1)(rt)
+		_, _ = rt.Synchronize()
+		if got := rt.ErrorHistory(expr); got != nil {
+			t.Errorf("ErrorHistory() = %v, want nil", got)
+		}
+	})
+
+	t.Run("resolved expression", func(t *testing.T) {
+		rt := newConsumersRuntime(t, "dep.spec.value")
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if got := rt.ErrorHistory("dep.spec.value"); got != nil {
+			t.Errorf("ErrorHistory() = %v, want nil", got)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// identityFields are the fields of a resource known before its creation when
+// its name is deterministic, i.e rendered from the template rather than
+// generated by the API server (metadata.generateName).
+var identityFields = []string{"apiVersion", "kind", "metadata.name", "metadata.namespace"}
+
+// desiredIdentities returns the identity of the dependencies of the variable
+// that aren't observed yet, rendered from their desired state, keyed by
+// resource id. This lets expressions like "${configmap.metadata.name}"
+// resolve before the config map is created. It returns false if one of them
+// is referenced by more than its identity, or if its identity isn't known
+// yet.
+func (rt *ResourceGraphDefinitionRuntime) desiredIdentities(
+	variable *expressionEvaluationState,
+	resolvedResources []string,
+) (map[string]interface{}, bool) {
+	references := rt.identityReferencesOf(variable.Expression)
+	identities := make(map[string]interface{})
+	for _, dep := range variable.Dependencies {
+		if slices.Contains(resolvedResources, dep) {
+			continue
+		}
+		paths, ok := references[dep]
+		if !ok {
+			return nil, false
+		}
+		identity, ok := rt.desiredIdentity(dep)
+		if !ok {
+			return nil, false
+		}
+		// e.g the namespace of a resource defaulting to the namespace of
+		// the instance is only known once it's created.
+		for _, path := range paths {
+			if _, found, _ := unstructured.NestedFieldNoCopy(identity, strings.Split(path, ".")...); !found {
+				return nil, false
+			}
+		}
+		identities[dep] = identity
+	}
+	return identities, true
+}
+
+// desiredIdentity returns the identity fields of the rendered desired state
+// of the resource. It returns false if the resource can't be rendered yet, or
+// if its name is generated by the API server.
+func (rt *ResourceGraphDefinitionRuntime) desiredIdentity(id string) (map[string]interface{}, bool) {
+	if _, ok := rt.resources[id]; !ok {
+		return nil, false
+	}
+	desired, state := rt.RenderResource(id)
+	if state != ResourceStateResolved {
+		return nil, false
+	}
+	name := desired.GetName()
+	// The template may not be rendered yet.
+	if name == "" || strings.Contains(name, "${") {
+		return nil, false
+	}
+
+	metadata := map[string]interface{}{"name": name}
+	if namespace := desired.GetNamespace(); namespace != "" && !strings.Contains(namespace, "${") {
+		metadata["namespace"] = namespace
+	}
+	identity := map[string]interface{}{"metadata": metadata}
+	if apiVersion := desired.GetAPIVersion(); apiVersion != "" {
+		identity["apiVersion"] = apiVersion
+	}
+	if kind := desired.GetKind(); kind != "" {
+		identity["kind"] = kind
+	}
+	return identity, true
+}
+
+// identityReferencesOf returns the identity fields referenced by the
+// expression, keyed by the dependencies it only references by their
+// identity fields, e.g "configmap.metadata.name".
+func (rt *ResourceGraphDefinitionRuntime) identityReferencesOf(expression string) map[string][]string {
+	if references, ok := rt.identityReferences[expression]; ok {
+		return references
+	}
+
+	references := make(map[string][]string)
+	if inspection, err := rt.inspectReferences(expression); err == nil {
+		other := map[string]bool{}
+		for _, dependency := range inspection.ResourceDependencies {
+			path := strings.TrimPrefix(dependency.Path, dependency.ID+".")
+			if dependency.Path == dependency.ID || !slices.Contains(identityFields, path) {
+				other[dependency.ID] = true
+				continue
+			}
+			references[dependency.ID] = append(references[dependency.ID], path)
+		}
+		for id := range other {
+			delete(references, id)
+		}
+	}
+	if rt.identityReferences == nil {
+		rt.identityReferences = make(map[string]map[string][]string)
+	}
+	rt.identityReferences[expression] = references
+	return references
+}
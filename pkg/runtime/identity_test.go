@@ -0,0 +1,188 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_DesiredNameReferences(t *testing.T) {
+	configMap := func(metadata map[string]interface{}) Resource {
+		return newTestResource(withObject(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   metadata,
+		}))
+	}
+	tests := []struct {
+		name         string
+		configMap    Resource
+		expr         string
+		wantResolved bool
+		wantValue    interface{}
+	}{
+		{
+			name:         "deterministic name",
+			configMap:    configMap(map[string]interface{}{"name": "config"}),
+			expr:         `configmap.metadata.name + "-consumer"`,
+			wantResolved: true,
+			wantValue:    "config-consumer",
+		},
+		{
+			name:         "identity fields",
+			configMap:    configMap(map[string]interface{}{"name": "config", "namespace": "default"}),
+			expr:         `configmap.kind + "/" + configmap.metadata.namespace + "/" + configmap.metadata.name`,
+			wantResolved: true,
+			wantValue:    "ConfigMap/default/config",
+		},
+		{
+			name:      "generated name",
+			configMap: configMap(map[string]interface{}{"generateName": "config-"}),
+			expr:      "configmap.metadata.name",
+		},
+		{
+			name:      "namespace defaulted at creation",
+			configMap: configMap(map[string]interface{}{"name": "config"}),
+			expr:      "configmap.metadata.namespace",
+		},
+		{
+			name:      "other fields",
+			configMap: configMap(map[string]interface{}{"name": "config"}),
+			expr:      `configmap.metadata.name + configmap.metadata.uid`,
+		},
+		{
+			name:      "whole object",
+			configMap: configMap(map[string]interface{}{"name": "config"}),
+			expr:      "configmap",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := map[string]Resource{
+				"configmap": tt.configMap,
+				"consumer": newTestResource(
+					withObject(map[string]interface{}{
+						"data": map[string]interface{}{"config": "${" + tt.expr + "}"},
+					}),
+					withDependencies([]string{"configmap"}),
+					withVariables([]*variable.ResourceField{
+						{
+							FieldDescriptor: variable.FieldDescriptor{
+								Path:                 "data.config",
+								Expressions:          []string{tt.expr},
+								StandaloneExpression: true,
+							},
+							Kind:         variable.ResourceVariableKindDynamic,
+							Dependencies: []string{"configmap"},
+						},
+					}),
+				),
+			}
+			rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"configmap", "consumer"})
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+
+			// The config map is rendered on the first pass, and referenced
+			// by name on the second one, without being created.
+			for range 2 {
+				if _, err := rt.Synchronize(); err != nil {
+					t.Fatalf("Synchronize() error = %v", err)
+				}
+			}
+
+			resolved, value, err := rt.ExpressionState(tt.expr)
+			if err != nil {
+				t.Fatalf("ExpressionState() error = %v", err)
+			}
+			if resolved != tt.wantResolved || value != tt.wantValue {
+				t.Errorf("ExpressionState() = (%v, %v), want (%v, %v)", resolved, value, tt.wantResolved, tt.wantValue)
+			}
+			wantState := ResourceStateWaitingOnDependencies
+			if tt.wantResolved {
+				wantState = ResourceStateResolved
+			}
+			if _, state := rt.GetResource("consumer"); state != wantState {
+				t.Errorf("GetResource() state = %v, want %v", state, wantState)
+			}
+		})
+	}
+}
+
+func Test_DesiredNameReferences_ComputedName(t *testing.T) {
+	// The name of the config map depends on the secret, it can only be
+	// referenced once the secret is observed.
+	nameExpr := `secret.metadata.name + "-config"`
+	consumerExpr := "configmap.metadata.name"
+	newDynamicField := func(path, expr, dep string) []*variable.ResourceField {
+		return []*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 path,
+					Expressions:          []string{expr},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{dep},
+			},
+		}
+	}
+	resources := map[string]Resource{
+		"secret": newTestResource(),
+		"configmap": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${" + nameExpr + "}"},
+			}),
+			withDependencies([]string{"secret"}),
+			withVariables(newDynamicField("metadata.name", nameExpr, "secret")),
+		),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"config": "${" + consumerExpr + "}"},
+			}),
+			withDependencies([]string{"configmap"}),
+			withVariables(newDynamicField("data.config", consumerExpr, "configmap")),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"secret", "configmap", "consumer"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	synchronize := func() {
+		t.Helper()
+		for range 3 {
+			if _, err := rt.Synchronize(); err != nil {
+				t.Fatalf("Synchronize() error = %v", err)
+			}
+		}
+	}
+
+	synchronize()
+	if resolved, _, _ := rt.ExpressionState(consumerExpr); resolved {
+		t.Errorf("ExpressionState() resolved before the config map name is known")
+	}
+
+	rt.SetResource("secret", &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "credentials"},
+	}})
+	synchronize()
+	resolved, value, _ := rt.ExpressionState(consumerExpr)
+	if !resolved || value != "credentials-config" {
+		t.Errorf("ExpressionState() = (%v, %v), want (true, credentials-config)", resolved, value)
+	}
+}
@@ -47,13 +47,24 @@ type Interface interface {
 	// it returns nil and the appropriate ResourceState.
 	GetResource(resourceID string) (*unstructured.Unstructured, ResourceState)
 
+	// ReferencedState returns the part of the resolved state read by the
+	// expressions through the "previous" variable, or nil if none.
+	ReferencedState() map[string]interface{}
+
 	// SetResource updates or sets a resource in the runtime. This is typically
 	// called after a resource has been created or updated in the cluster.
+	// A nil object means that the resource is gone, and invalidates the
+	// expressions depending on it.
 	SetResource(resourceID string, obj *unstructured.Unstructured)
 
 	// GetInstance returns the main instance object managed by this runtime.
 	GetInstance() *unstructured.Unstructured
 
+	// ManagedStatus returns the instance status restricted to the fields
+	// owned by the runtime, leaving out the fields written by other
+	// controllers. Unresolved fields keep the value of the observed instance.
+	ManagedStatus() (map[string]interface{}, error)
+
 	// SetInstance updates the main instance object.
 	// This is typically called after the instance has been updated in the cluster.
 	SetInstance(obj *unstructured.Unstructured)
@@ -68,6 +79,10 @@ type Interface interface {
 	// IgnoreResource ignores resource that has a condition expressison that evaluated
 	// to false
 	IgnoreResource(resourceID string)
+
+	// GetDeletionPolicy returns what to do with the resource when the
+	// instance is deleted, evaluating the policy expressions if any.
+	GetDeletionPolicy(id string) (string, error)
 }
 
 // ResourceDescriptor provides metadata about a resource.
@@ -101,10 +116,34 @@ type ResourceDescriptor interface {
 	// depends on.
 	GetDependencies() []string
 
+	// GetReadinessDependencies returns the list of resource IDs that need to
+	// be ready (see IsResourceReady), and not only resolved, before this
+	// resource can be processed. e.g an application waiting for its database
+	// to accept connections.
+	GetReadinessDependencies() []string
+
 	// GetReadyWhenExpressions returns the list of expressions that need to be
 	// evaluated before the resource is considered ready.
 	GetReadyWhenExpressions() []string
 
+	// GetReadyWhenMessages returns the messages to report when a readyWhen
+	// expression evaluates to false, keyed by expression. Messages can embed
+	// expressions, e.g "${deployment.status.readyReplicas} replicas ready".
+	GetReadyWhenMessages() map[string]string
+
+	// GetDeletionPolicy returns what to do with the resource when the instance
+	// is deleted: DeletionPolicyDelete, DeletionPolicyOrphan or
+	// DeletionPolicyRetain. The policy can embed expressions, e.g
+	// `${schema.spec.environment == "production" ? "Retain" : "Delete"}`.
+	// An empty policy defaults to DeletionPolicyDelete.
+	GetDeletionPolicy() string
+
+	// GetPriority returns the creation priority of the resource among the
+	// resources of its topological level, higher priorities first, see
+	// CreationOrder. The priority can embed expressions, e.g
+	// `${schema.spec.critical ? 100 : 0}`. An empty priority defaults to 0.
+	GetPriority() string
+
 	// GetIncludeWhenExpressions returns the list of expressions that need to
 	// be evaluated before deciding whether to create a resource
 	GetIncludeWhenExpressions() []string
@@ -116,6 +155,14 @@ type ResourceDescriptor interface {
 	// IsNamespaced returns true if the resource is namespaced, and false if it's
 	// cluster-scoped.
 	IsNamespaced() bool
+
+	// GetSkipReadiness returns true if the readiness of the resource shouldn't
+	// be checked, IsResourceReady then always reports it as ready.
+	GetSkipReadiness() bool
+
+	// GetIgnoreUpdateErrors returns true if a failure to update the resource
+	// shouldn't fail the reconciliation of the instance.
+	GetIgnoreUpdateErrors() bool
 }
 
 // Resource extends `ResourceDescriptor` to include the actual resource data.
@@ -0,0 +1,190 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/kro-run/kro/pkg/cel/ast"
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// ExpressionsUsingSpecPath returns every expression (resource variables,
+// instance variables, readyWhen and includeWhen expressions) that refers to
+// the given instance spec path. e.g "spec.replicas" or "schema.spec.replicas".
+//
+// An expression refers to a path if it reads the path itself, one of its
+// parents (e.g `schema.spec`) or one of its children. The returned list is
+// sorted and doesn't contain duplicates.
+func (rt *ResourceGraphDefinitionRuntime) ExpressionsUsingSpecPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "."), "schema.")
+
+	inspector, err := rt.newInspector()
+	if err != nil {
+		return nil
+	}
+
+	var expressions []string
+	for _, expression := range rt.allExpressions() {
+		for _, ref := range specReferences(inspector, expression) {
+			if pathsOverlap(ref, path) {
+				expressions = append(expressions, expression)
+				break
+			}
+		}
+	}
+	slices.Sort(expressions)
+	return expressions
+}
+
+// allExpressions returns all the expressions known to the runtime, including
+// the includeWhen expressions that are not part of the expressions cache.
+func (rt *ResourceGraphDefinitionRuntime) allExpressions() []string {
+	expressions := maps.Keys(rt.expressionsCache)
+	for _, resource := range rt.resources {
+		for _, expression := range resource.GetIncludeWhenExpressions() {
+			if !slices.Contains(expressions, expression) {
+				expressions = append(expressions, expression)
+			}
+		}
+	}
+	return expressions
+}
+
+// newInspector returns an expression inspector aware of all the resources
+// and context variables of the runtime.
+func (rt *ResourceGraphDefinitionRuntime) newInspector() (*ast.Inspector, error) {
+	resourceIDs := append(maps.Keys(rt.resources), contextVariableNames...)
+	return ast.DefaultInspector(resourceIDs, nil)
+}
+
+// inspectReferences inspects the expression with an inspector shared by the
+// lookups of the expression references, created on first use.
+func (rt *ResourceGraphDefinitionRuntime) inspectReferences(expression string) (ast.ExpressionInspection, error) {
+	if rt.referencesInspector == nil {
+		inspector, err := rt.newInspector()
+		if err != nil {
+			return ast.ExpressionInspection{}, err
+		}
+		rt.referencesInspector = inspector
+	}
+	return rt.referencesInspector.Inspect(expression)
+}
+
+// withDerivedDependencies returns the dependencies of the dynamic expression
+// merged with the resources it references. It's a safety net for incomplete
+// dependency declarations: the undeclared resources would never be part of
+// the evaluation context, leaving the expression unresolved. Every derived
+// dependency is logged, logr having no warning level.
+func (rt *ResourceGraphDefinitionRuntime) withDerivedDependencies(
+	expression string,
+	kind variable.ResourceVariableKind,
+	dependencies []string,
+) []string {
+	if !kind.IsDynamic() {
+		return dependencies
+	}
+	inspection, err := rt.inspectReferences(expression)
+	if err != nil {
+		// Left to the evaluation to report.
+		return dependencies
+	}
+	merged := dependencies
+	for _, reference := range inspection.ResourceDependencies {
+		if _, ok := rt.resources[reference.ID]; !ok || slices.Contains(merged, reference.ID) {
+			continue
+		}
+		rt.log.Info("expression references an undeclared dependency, adding it",
+			"expression", expression, "dependency", reference.ID)
+		// Never modify the declared dependencies in place.
+		merged = append(slices.Clone(merged), reference.ID)
+	}
+	return merged
+}
+
+// specReferences returns the instance paths (without the "schema." prefix)
+// referenced by the given expression. A reference to the whole instance is
+// returned as an empty path. Expressions that fail inspection are considered
+// to have no references.
+func specReferences(inspector *ast.Inspector, expression string) []string {
+	inspection, err := inspector.Inspect(expression)
+	if err != nil {
+		return nil
+	}
+
+	var references []string
+	for _, dependency := range inspection.ResourceDependencies {
+		if dependency.ID != "schema" {
+			continue
+		}
+		references = append(references, strings.TrimPrefix(strings.TrimPrefix(dependency.Path, "schema"), "."))
+	}
+	return references
+}
+
+// pathsOverlap returns true if one of the paths is equal to, or is a parent
+// of, the other one. An empty path is the parent of every path.
+func pathsOverlap(a, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// ManagedFields returns the paths of the fields set by expressions in the
+// given resource ("instance" for the instance status), e.g
+// "spec.replicas" or "spec.containers[0].image". It's meant to scope the
+// server side apply field manager to the fields the runtime controls. The
+// returned list is sorted and doesn't contain duplicates, it's nil for
+// unknown resources.
+func (rt *ResourceGraphDefinitionRuntime) ManagedFields(name string) []string {
+	var resource Resource
+	if name == "instance" {
+		resource = rt.instance
+	} else if r, ok := rt.resources[name]; ok {
+		resource = r
+	}
+	if resource == nil {
+		return nil
+	}
+
+	var paths []string
+	for _, v := range resource.GetVariables() {
+		if !slices.Contains(paths, v.Path) {
+			paths = append(paths, v.Path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// RedundantExpressions returns the expressions used by more than one
+// resource ("instance" for the instance status), keyed by expression, with
+// the sorted ids of the resources using them. The runtime evaluates them only
+// once, but authors may want to factor them out, e.g into a shared resource
+// field. Expressions used several times by a single resource aren't reported.
+func (rt *ResourceGraphDefinitionRuntime) RedundantExpressions() map[string][]string {
+	redundant := make(map[string][]string)
+	for expression, consumers := range rt.expressionConsumers {
+		if len(consumers) > 1 {
+			names := slices.Clone(consumers)
+			slices.Sort(names)
+			redundant[expression] = names
+		}
+	}
+	return redundant
+}
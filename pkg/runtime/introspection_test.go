@@ -0,0 +1,332 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ExpressionsUsingSpecPath(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		resources: map[string]Resource{
+			"deployment": newTestResource(
+				withConditions([]string{"schema.spec.replicas > 0"}),
+			),
+			"service": newTestResource(),
+		},
+		expressionsCache: map[string]*expressionEvaluationState{
+			"schema.spec.replicas * 2": {
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			"schema.spec.name + '-svc'": {
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			"schema.spec": {
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			"schema.spec.replicas.max": {
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			"deployment.spec.replicas": {
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"deployment"},
+			},
+			"deployment.status.readyReplicas == schema.spec.replicas": {
+				Kind: variable.ResourceVariableKindReadyWhen,
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "direct references",
+			path: "spec.replicas",
+			want: []string{
+				"deployment.status.readyReplicas == schema.spec.replicas",
+				"schema.spec",
+				"schema.spec.replicas * 2",
+				"schema.spec.replicas > 0",
+				"schema.spec.replicas.max",
+			},
+		},
+		{
+			name: "schema prefixed path",
+			path: "schema.spec.name",
+			want: []string{
+				"schema.spec",
+				"schema.spec.name + '-svc'",
+			},
+		},
+		{
+			name: "no references",
+			path: "spec.image",
+			want: []string{
+				"schema.spec",
+			},
+		},
+		{
+			name: "path outside of the spec",
+			path: "metadata.name",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rt.ExpressionsUsingSpecPath(tt.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpressionsUsingSpecPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ManagedFields(t *testing.T) {
+	field := func(path string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{"schema.spec.value"},
+				StandaloneExpression: true,
+			},
+			Kind: variable.ResourceVariableKindStatic,
+		}
+	}
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: newTestResource(withVariables([]*variable.ResourceField{
+			field("status.endpoint"),
+		})),
+		resources: map[string]Resource{
+			"deployment": newTestResource(withVariables([]*variable.ResourceField{
+				field("spec.template.spec.containers[0].image"),
+				field("metadata.name"),
+				field("spec.replicas"),
+				field("metadata.name"),
+			})),
+			"configmap": newTestResource(),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		want     []string
+	}{
+		{
+			name:     "multi field resource",
+			resource: "deployment",
+			want: []string{
+				"metadata.name",
+				"spec.replicas",
+				"spec.template.spec.containers[0].image",
+			},
+		},
+		{
+			name:     "instance",
+			resource: "instance",
+			want:     []string{"status.endpoint"},
+		},
+		{
+			name:     "resource without expressions",
+			resource: "configmap",
+			want:     nil,
+		},
+		{
+			name:     "unknown resource",
+			resource: "unknown",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rt.ManagedFields(tt.resource)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ManagedFields(%q) = %v, want %v", tt.resource, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DerivedDependencies(t *testing.T) {
+	var logged []string
+	log := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	consumer := func(dependencies []string) Resource {
+		return newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${dep.spec.value + other.spec.value}"},
+			}),
+			withDependencies([]string{"dep", "other"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{"dep.spec.value + other.spec.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: dependencies,
+				},
+			}),
+		)
+	}
+	// The dependency on other is missing from the declaration.
+	declared := []string{"dep"}
+	resources := map[string]Resource{
+		"dep":      newTestResource(),
+		"other":    newTestResource(),
+		"consumer": consumer(declared),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "other", "consumer"}, WithLogger(log))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	if !reflect.DeepEqual(declared, []string{"dep"}) {
+		t.Errorf("declared dependencies modified: %v", declared)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], `"dependency"="other"`) {
+		t.Errorf("logged = %v, want a single derived dependency on other", logged)
+	}
+
+	// Not resolved until every referenced resource is observed.
+	rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": "hello"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	if _, state := rt.GetResource("consumer"); state != ResourceStateWaitingOnDependencies {
+		t.Errorf("consumer state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+	}
+
+	rt.SetResource("other", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": " world"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	obj, state := rt.GetResource("consumer")
+	if state != ResourceStateResolved {
+		t.Fatalf("consumer state = %v, want %v", state, ResourceStateResolved)
+	}
+	if got := obj.Object["data"].(map[string]interface{})["value"]; got != "hello world" {
+		t.Errorf("consumer data.value = %v, want %q", got, "hello world")
+	}
+}
+
+func Test_RedundantExpressions(t *testing.T) {
+	field := func(path string, expressions ...string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:        path,
+				Expressions: expressions,
+			},
+			Kind: variable.ResourceVariableKindStatic,
+		}
+	}
+	spec := withObject(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name":     "app",
+			"replicas": int64(1),
+			"image":    "nginx",
+			"tag":      "latest",
+			"port":     int64(80),
+		},
+	})
+	configmap := newTestResource(withObject(map[string]interface{}{
+		"data": map[string]interface{}{
+			"port":      "${schema.spec.port}",
+			"otherPort": "${schema.spec.port}",
+		},
+	}), withVariables([]*variable.ResourceField{
+		field("data.port", "schema.spec.port"),
+		field("data.otherPort", "schema.spec.port"),
+	}))
+	rt, err := NewResourceGraphDefinitionRuntime(
+		newTestResource(spec, withVariables([]*variable.ResourceField{
+			field("status.name", "schema.spec.name"),
+		})),
+		map[string]Resource{
+			"deployment": newTestResource(withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "${schema.spec.name}",
+					"labels": map[string]interface{}{"app": "${schema.spec.name}"},
+				},
+				"spec": map[string]interface{}{
+					"replicas": "${schema.spec.replicas}",
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"image": "${schema.spec.image}:${schema.spec.tag}"},
+							},
+						},
+					},
+				},
+			}), withVariables([]*variable.ResourceField{
+				field("metadata.name", "schema.spec.name"),
+				field("metadata.labels.app", "schema.spec.name"),
+				field("spec.replicas", "schema.spec.replicas"),
+				field("spec.template.spec.containers[0].image", "schema.spec.image", "schema.spec.tag"),
+			})),
+			"service": newTestResource(withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":        "${schema.spec.name}",
+					"annotations": map[string]interface{}{"tag": "${schema.spec.tag}"},
+				},
+			}), withVariables([]*variable.ResourceField{
+				field("metadata.name", "schema.spec.name"),
+				field("metadata.annotations.tag", "schema.spec.tag"),
+			})),
+			"configmap": configmap,
+		},
+		[]string{"deployment", "service", "configmap"},
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	want := map[string][]string{
+		"schema.spec.name": {"deployment", "instance", "service"},
+		"schema.spec.tag":  {"deployment", "service"},
+	}
+	if got := rt.RedundantExpressions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedundantExpressions() = %v, want %v", got, want)
+	}
+
+	// Without shared expressions, nothing is reported.
+	rt, err = NewResourceGraphDefinitionRuntime(
+		newTestResource(spec),
+		map[string]Resource{"configmap": configmap},
+		[]string{"configmap"},
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	if got := rt.RedundantExpressions(); len(got) != 0 {
+		t.Errorf("RedundantExpressions() = %v, want none", got)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// MemoCache caches the results of the dynamic expressions, keyed by the
+// expression and a hash of its inputs. A cache shared by multiple runtimes,
+// e.g the runtimes of all the instances reconciled by a controller, avoids
+// evaluating expensive expressions again for identical inputs.
+//
+// Implementations must be safe for concurrent use. The cached values must
+// not be modified, the runtime copies them.
+type MemoCache interface {
+	// Get returns the value cached for the key, if any.
+	Get(key string) (interface{}, bool)
+	// Set caches the value for the key.
+	Set(key string, value interface{})
+}
+
+// evaluateMemoized evaluates the expression, looking up its result in the
+// memoization cache first, if any. Only successful evaluations are cached.
+func (rt *ResourceGraphDefinitionRuntime) evaluateMemoized(
+	env *cel.Env,
+	evalContext map[string]interface{},
+	expression string,
+) (interface{}, error) {
+	// Injected errors must not be shadowed by memoized results.
+	if rt.memoCache == nil || rt.hasInjectedError(expression) {
+		return rt.evaluate(env, evalContext, expression)
+	}
+	key, ok := rt.memoKey(expression, evalContext)
+	if !ok {
+		return rt.evaluate(env, evalContext, expression)
+	}
+	if value, ok := rt.memoCache.Get(key); ok {
+		return deepCopyValue(value), nil
+	}
+	value, err := rt.evaluate(env, evalContext, expression)
+	if err != nil {
+		return nil, err
+	}
+	rt.memoCache.Set(key, deepCopyValue(value))
+	return value, nil
+}
+
+// memoKey returns the memoization key of the expression: the expression
+// followed by the sha256 of the context fields it references. Hashing only
+// the referenced fields, rather than the whole objects, keeps the key cheap
+// and lets runtimes of different instances share results. It returns false
+// if the inputs can't be determined.
+func (rt *ResourceGraphDefinitionRuntime) memoKey(expression string, evalContext map[string]interface{}) (string, bool) {
+	paths, ok := rt.memoInputs[expression]
+	if !ok {
+		if rt.memoInspector == nil {
+			inspector, err := rt.newInspector()
+			if err != nil {
+				return "", false
+			}
+			rt.memoInspector = inspector
+		}
+		inspection, err := rt.memoInspector.Inspect(expression)
+		if err != nil {
+			return "", false
+		}
+		for _, dependency := range inspection.ResourceDependencies {
+			paths = append(paths, dependency.Path)
+		}
+		// Variables unknown to the inspector, e.g kind aliases or the
+		// resources list.
+		for _, unknown := range inspection.UnknownResources {
+			paths = append(paths, unknown.Path)
+		}
+		paths = compactFieldPaths(paths)
+		if rt.memoInputs == nil {
+			rt.memoInputs = make(map[string][]string)
+		}
+		rt.memoInputs[expression] = paths
+	}
+
+	inputs := make(map[string]interface{}, len(paths))
+	for _, path := range paths {
+		// Keyed by the path found, a missing field and a field holding the
+		// value of its parent have distinct keys.
+		if found, value, ok := lookupFieldPath(evalContext, path); ok {
+			inputs[found] = value
+		}
+	}
+	// encoding/json sorts the map keys, which makes the encoding canonical.
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return expression + "\x00" + hex.EncodeToString(sum[:]), true
+}
+
+// compactFieldPaths sorts the paths and removes the duplicates and the paths
+// nested in another one, their values are already part of the parent value.
+func compactFieldPaths(paths []string) []string {
+	slices.Sort(paths)
+	compacted := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if n := len(compacted); n > 0 {
+			last := compacted[n-1]
+			if path == last || strings.HasPrefix(path, last+".") {
+				continue
+			}
+		}
+		compacted = append(compacted, path)
+	}
+	return compacted
+}
+
+// lookupFieldPath returns the value of the dotted path in the context, and
+// the path it was found at. The lookup stops at the deepest existing map,
+// e.g at a list being indexed or at a missing field, and returns that value:
+// it always holds the referenced field, or tells that it's missing. It
+// returns false if the context doesn't have the root variable.
+func lookupFieldPath(evalContext map[string]interface{}, path string) (string, interface{}, bool) {
+	fields := strings.Split(path, ".")
+	value, ok := evalContext[fields[0]]
+	if !ok {
+		return "", nil, false
+	}
+	depth := 1
+	for _, field := range fields[1:] {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		next, ok := m[field]
+		if !ok {
+			break
+		}
+		value = next
+		depth++
+	}
+	return strings.Join(fields[:depth], "."), value, true
+}
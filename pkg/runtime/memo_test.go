@@ -0,0 +1,156 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// countingMemoCache is a MemoCache counting its hits and misses.
+type countingMemoCache struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	hits   int
+	misses int
+}
+
+func (c *countingMemoCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return value, ok
+}
+
+func (c *countingMemoCache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+}
+
+func Test_MemoCache(t *testing.T) {
+	const expr = "dep.spec.items.map(x, x * 2)"
+	cache := &countingMemoCache{values: map[string]interface{}{}}
+
+	newRuntime := func(name string, items ...interface{}) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		rt := newConsumersRuntime(t, expr)
+		WithMemoCache(cache)(rt)
+		// The instances differ, but the expression doesn't reference them.
+		rt.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name},
+		}})
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"items": items},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+	assertResult := func(rt *ResourceGraphDefinitionRuntime, want []interface{}) {
+		t.Helper()
+		_, value, err := rt.ExpressionState(expr)
+		if err != nil {
+			t.Fatalf("ExpressionState() error = %v", err)
+		}
+		if !reflect.DeepEqual(value, want) {
+			t.Errorf("%s = %v, want %v", expr, value, want)
+		}
+	}
+
+	first := newRuntime("first", int64(1), int64(2))
+	assertResult(first, []interface{}{int64(2), int64(4)})
+	if cache.hits != 0 || cache.misses != 1 {
+		t.Errorf("first runtime: hits = %d, misses = %d, want 0 and 1", cache.hits, cache.misses)
+	}
+
+	// Identical inputs in another runtime hit the cache.
+	second := newRuntime("second", int64(1), int64(2))
+	assertResult(second, []interface{}{int64(2), int64(4)})
+	if cache.hits != 1 || cache.misses != 1 {
+		t.Errorf("second runtime: hits = %d, misses = %d, want 1 and 1", cache.hits, cache.misses)
+	}
+
+	// Different inputs have a distinct hash, and miss the cache.
+	third := newRuntime("third", int64(3))
+	assertResult(third, []interface{}{int64(6)})
+	if cache.hits != 1 || cache.misses != 2 {
+		t.Errorf("third runtime: hits = %d, misses = %d, want 1 and 2", cache.hits, cache.misses)
+	}
+	if len(cache.values) != 2 {
+		t.Errorf("cache has %d values, want 2", len(cache.values))
+	}
+}
+
+func Test_MemoCache_ReferencedFields(t *testing.T) {
+	const expr = "has(dep.spec.extra) ? dep.spec.value : 'none'"
+	cache := &countingMemoCache{values: map[string]interface{}{}}
+
+	synchronize := func(spec, status map[string]interface{}) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		rt := newConsumersRuntime(t, expr)
+		WithMemoCache(cache)(rt)
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   spec,
+			"status": status,
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+	assertResult := func(rt *ResourceGraphDefinitionRuntime, want string) {
+		t.Helper()
+		_, value, err := rt.ExpressionState(expr)
+		if err != nil {
+			t.Fatalf("ExpressionState() error = %v", err)
+		}
+		if value != want {
+			t.Errorf("%s = %v, want %v", expr, value, want)
+		}
+	}
+
+	assertResult(synchronize(
+		map[string]interface{}{"value": "a"},
+		map[string]interface{}{"generation": int64(1)},
+	), "none")
+
+	// Fields the expression doesn't reference are not part of the key.
+	assertResult(synchronize(
+		map[string]interface{}{"value": "a"},
+		map[string]interface{}{"generation": int64(2)},
+	), "none")
+	if cache.hits != 1 || cache.misses != 1 {
+		t.Errorf("hits = %d, misses = %d, want 1 and 1", cache.hits, cache.misses)
+	}
+
+	// A missing field and a field holding the value of its parent have
+	// distinct keys.
+	assertResult(synchronize(
+		map[string]interface{}{"value": "a", "extra": map[string]interface{}{"value": "a"}},
+		nil,
+	), "a")
+	if cache.hits != 1 || cache.misses != 2 {
+		t.Errorf("hits = %d, misses = %d, want 1 and 2", cache.hits, cache.misses)
+	}
+}
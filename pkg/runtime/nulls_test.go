@@ -0,0 +1,168 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime/resolver"
+)
+
+func Test_StrictNullHandling(t *testing.T) {
+	const expr = "dep.spec.value"
+	newRuntime := func(t *testing.T, required bool, opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"dep": newTestResource(),
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"data": map[string]interface{}{"value": "${" + expr + "}", "static": "kept"},
+				}),
+				withDependencies([]string{"dep"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "data.value",
+							Expressions:          []string{expr},
+							StandaloneExpression: true,
+							Required:             required,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"dep"},
+					},
+				}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": nil},
+		}})
+		return rt
+	}
+
+	t.Run("required field resolving to null", func(t *testing.T) {
+		rt := newRuntime(t, true, WithStrictNullHandling())
+		_, err := rt.Synchronize()
+		if err == nil {
+			t.Fatal("Synchronize() error = nil, want an error")
+		}
+		if !strings.Contains(err.Error(), resolver.ErrRequiredFieldNull.Error()+": data.value") {
+			t.Errorf("Synchronize() error = %v, want %v", err, resolver.ErrRequiredFieldNull)
+		}
+	})
+
+	t.Run("optional field resolving to null is omitted", func(t *testing.T) {
+		rt := newRuntime(t, false, WithStrictNullHandling())
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		got, state := rt.GetResource("consumer")
+		if state != ResourceStateResolved {
+			t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+		want := map[string]interface{}{"data": map[string]interface{}{"static": "kept"}}
+		if !reflect.DeepEqual(got.Object, want) {
+			t.Errorf("GetResource() = %v, want %v", got.Object, want)
+		}
+	})
+
+	t.Run("nulls are written by default", func(t *testing.T) {
+		rt := newRuntime(t, true)
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		got, _ := rt.GetResource("consumer")
+		value, found, _ := unstructured.NestedFieldNoCopy(got.Object, "data", "value")
+		if !found || value != nil {
+			t.Errorf("data.value = %v (found %v), want null", value, found)
+		}
+	})
+}
+
+func Test_ExplicitNull(t *testing.T) {
+	const (
+		explicitExpr = "dep.spec.selector == null ? explicitNull() : dep.spec.selector"
+		omittedExpr  = "dep.spec.selector"
+	)
+	field := func(path, expr string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"dep"},
+		}
+	}
+	newRuntime := func(t *testing.T, selector interface{}) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"dep": newTestResource(),
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{
+						"explicit": "${" + explicitExpr + "}",
+						"omitted":  "${" + omittedExpr + "}",
+					},
+				}),
+				withDependencies([]string{"dep"}),
+				withVariables([]*variable.ResourceField{
+					field("spec.explicit", explicitExpr),
+					field("spec.omitted", omittedExpr),
+				}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"}, WithStrictNullHandling())
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"selector": selector},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+
+	t.Run("explicit null and omitted fields", func(t *testing.T) {
+		rt := newRuntime(t, nil)
+		got, state := rt.GetResource("consumer")
+		if state != ResourceStateResolved {
+			t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+		want := map[string]interface{}{"spec": map[string]interface{}{"explicit": nil}}
+		if !reflect.DeepEqual(got.Object, want) {
+			t.Errorf("GetResource() = %v, want %v", got.Object, want)
+		}
+	})
+
+	t.Run("set fields", func(t *testing.T) {
+		rt := newRuntime(t, "app")
+		got, _ := rt.GetResource("consumer")
+		want := map[string]interface{}{"spec": map[string]interface{}{"explicit": "app", "omitted": "app"}}
+		if !reflect.DeepEqual(got.Object, want) {
+			t.Errorf("GetResource() = %v, want %v", got.Object, want)
+		}
+	})
+}
@@ -0,0 +1,346 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Option configures optional behaviors of a ResourceGraphDefinitionRuntime.
+type Option func(*ResourceGraphDefinitionRuntime)
+
+// WithOwnerReferences makes the runtime inject an ownerReference pointing to
+// the instance into every rendered resource, so that the resources are garbage
+// collected with the instance.
+//
+// Resources are only stamped once the instance has a UID, and cluster scoped
+// resources are never stamped with a namespaced instance owner reference,
+// since Kubernetes doesn't allow it.
+func WithOwnerReferences() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.injectOwnerReferences = true
+	}
+}
+
+// WithEvaluator registers an alternative expression evaluator, used for the
+// expressions prefixed with "<prefix>:". See Evaluator.
+func WithEvaluator(prefix string, evaluator Evaluator) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		if rt.evaluators == nil {
+			rt.evaluators = make(map[string]Evaluator)
+		}
+		rt.evaluators[prefix] = evaluator
+	}
+}
+
+// WithStaticEvaluationWorkers bounds the number of goroutines used to evaluate
+// the static expressions when the runtime is constructed. Values lower than 1
+// default to GOMAXPROCS, and 1 evaluates the expressions sequentially.
+func WithStaticEvaluationWorkers(workers int) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.staticEvaluationWorkers = workers
+	}
+}
+
+// WithKindAliases makes the resources referenceable by their kind, when no
+// other resource of the graph has the same kind. e.g a graph with a single
+// Deployment can use "${Deployment.status.readyReplicas}".
+//
+// Kinds shared by multiple resources are ambiguous, and referencing them is
+// an error. Kinds colliding with a resource id are never aliased.
+//
+// NOTE: The expression dependencies are expected to contain the alias, the
+// graph builder doesn't resolve kind aliases for now.
+func WithKindAliases() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.kindAliasesEnabled = true
+	}
+}
+
+// WithOnExpressionFailed registers a callback called when an expression
+// reaches a terminal error state, i.e a hard evaluation error, or incomplete
+// data after the attempts allowed by WithIncompleteDataThreshold. The
+// callback is called at most once per expression, which makes it suitable to
+// emit events or metrics for misconfigured ResourceGraphDefinitions.
+func WithOnExpressionFailed(fn func(expression string, err error)) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.onExpressionFailed = fn
+	}
+}
+
+// WithIncompleteDataThreshold makes expressions failing because of incomplete
+// data terminal after the given number of consecutive attempts. By default,
+// incomplete data is never terminal, and the evaluation is retried forever.
+func WithIncompleteDataThreshold(attempts int) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.incompleteDataThreshold = attempts
+	}
+}
+
+// WithResourceGroup exposes the identity of the resource graph definition
+// that produced the instance to the expressions, as the "resourceGroup"
+// variable. e.g "${resourceGroup.name + '-' + schema.spec.name}".
+func WithResourceGroup(name, version string) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.resourceGroupName = name
+		rt.resourceGroupVersion = version
+	}
+}
+
+// WithTypedResource registers a Go struct type for the given kind, e.g
+// WithTypedResource(appsv1.SchemeGroupVersion.WithKind("Deployment"),
+// &appsv1.Deployment{}). The observed resources of that kind are converted
+// once to the typed struct, and exposed as such to the expressions, which is
+// faster than accessing the fields of unstructured objects.
+//
+// Typed objects expose the zero values of unset fields, instead of failing
+// with "no such key" errors, and their embedded type metadata (apiVersion and
+// kind) isn't accessible.
+func WithTypedResource(gvk schema.GroupVersionKind, prototype interface{}) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		t := reflect.TypeOf(prototype)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if rt.typedKinds == nil {
+			rt.typedKinds = make(map[schema.GroupVersionKind]reflect.Type)
+		}
+		rt.typedKinds[gvk] = t
+	}
+}
+
+// WithFeatureFlags sets the enabled feature flags. Expressions opting in to a
+// feature flag (see variable.FieldDescriptor.RequiredFeatures) get access to
+// the CEL declarations gated behind it, and fail to compile if it's disabled.
+func WithFeatureFlags(flags map[string]bool) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.featureFlags = flags
+	}
+}
+
+// WithTracerProvider enables the OpenTelemetry tracing of the runtime: every
+// Synchronize call creates a span, with a child span per expression
+// evaluation. Tracing is disabled, and costs nothing, by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// WithPreviousState loads the resolved state of a previous reconcile, as
+// returned by ResolvedState, and exposes it to the expressions under the
+// "previous" variable, keyed by resource id. e.g
+// "${has(previous.config) && config.data.version != previous.config.data.version}"
+func WithPreviousState(state map[string]interface{}) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.previousState = state
+	}
+}
+
+// WithMemoCache makes the runtime look up the results of the dynamic
+// expressions in the given cache before evaluating them, and cache them. The
+// results are keyed by expression and by a hash of the values it references,
+// so a cache shared by the runtimes of similar instances avoids recomputing
+// identical results.
+func WithMemoCache(cache MemoCache) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.memoCache = cache
+	}
+}
+
+// WithStrictNullHandling prevents nulls from silently propagating into the
+// resources: a required field (see variable.FieldDescriptor.Required) whose
+// expression resolves to null fails the resolution of the resource, while an
+// optional field resolving to null is omitted instead of being set to null.
+// Only the standalone expressions are concerned, string templates are always
+// rendered.
+func WithStrictNullHandling() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.strictNulls = true
+	}
+}
+
+// WithReadinessProbe registers a readiness probe for the resource. The probe
+// is called by IsResourceReady, in addition to the readyWhen expressions of
+// the resource, once they are all true.
+func WithReadinessProbe(resourceID string, probe ReadinessProbe) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		if rt.readinessProbes == nil {
+			rt.readinessProbes = make(map[string]ReadinessProbe)
+		}
+		rt.readinessProbes[resourceID] = probe
+	}
+}
+
+// WithErrorHistorySize sets the number of recent evaluation errors kept per
+// expression, see ErrorHistory. Defaults to 5, and negative values disable
+// the history.
+func WithErrorHistorySize(size int) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.errorHistorySize = size
+	}
+}
+
+// WithCostAwareOrdering makes Synchronize evaluate the pending dynamic
+// expressions by increasing estimated cost, as computed by the CEL cost
+// estimator. An evaluation pass stops at the first expression hitting
+// incomplete data, evaluating the cheap expressions first resolves more
+// expressions per pass, and unblocks the resources depending on them sooner.
+func WithCostAwareOrdering() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.costAwareOrdering = true
+	}
+}
+
+// WithReconcileContext exposes contextual information of the controller, e.g
+// the reconcile id, the triggering event or the cluster name, to the
+// expressions under the "reconcileContext" variable. e.g
+// "${reconcileContext.clusterName + '-' + schema.spec.name}"
+func WithReconcileContext(reconcileContext map[string]interface{}) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.reconcileContext = reconcileContext
+	}
+}
+
+// WithFieldPruning makes GetResource and RenderResource return the given
+// resources without their null fields, e.g the fields whose expressions
+// resolved to null because they reference a resource ignored by conditions.
+// This produces minimal objects for the target CRDs rejecting unknown or null
+// fields. The maps left empty by the pruning are removed as well.
+func WithFieldPruning(resourceIDs ...string) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		if rt.prunedResources == nil {
+			rt.prunedResources = make(map[string]bool)
+		}
+		for _, id := range resourceIDs {
+			rt.prunedResources[id] = true
+		}
+	}
+}
+
+// WithAuditRedaction redacts the values of the fields for which redact
+// returns true from the audit trail (see AuditTrail), e.g the data of the
+// Secrets, or fields sourced from them. Their value is replaced by
+// RedactedValue.
+func WithAuditRedaction(redact func(field ResolvedField) bool) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.auditRedaction = redact
+	}
+}
+
+// WithLazyResolution makes GetResource resolve the expressions of the
+// requested resource, and of its dependencies, on demand, instead of waiting
+// for Synchronize to resolve the expressions of the whole graph. It suits
+// controllers only fetching a few resources of large graphs per reconcile.
+// Evaluation errors are not returned by GetResource, the resource is reported
+// as waiting on its dependencies, Synchronize returns them.
+func WithLazyResolution() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.lazyResolution = true
+	}
+}
+
+// WithReferenceValidation makes NewResourceGraphDefinitionRuntime fail if the
+// graph references resources that aren't part of it, see ValidateReferences,
+// instead of leaving the resources depending on them waiting forever.
+func WithReferenceValidation() Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.referenceValidation = true
+	}
+}
+
+// WithRequeueInterval declares the delay before reconciling the instance
+// again, see ComputeRequeueInterval. The interval can embed expressions
+// evaluated against the current state, e.g
+// `${schema.status.phase == "Provisioning" ? "30s" : ""}`.
+func WithRequeueInterval(interval string) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.requeueInterval = interval
+	}
+}
+
+// WithLogger sets the logger used to report the issues of the graph that don't
+// prevent its resolution, e.g dependencies derived from the expressions
+// because they weren't declared. Nothing is logged by default.
+func WithLogger(log logr.Logger) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.log = log
+	}
+}
+
+// WithProgressCallback calls fn with the resolution progress during
+// Synchronize, after each resolution milestone, e.g to report "resolving
+// 12/30 expressions" in the instance status during slow convergences. Reports
+// are rate-limited to one per interval (DefaultProgressInterval if interval
+// isn't positive), except for the completion report.
+func WithProgressCallback(interval time.Duration, fn func(Progress)) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		if interval <= 0 {
+			interval = DefaultProgressInterval
+		}
+		rt.progressCallback = fn
+		rt.progressInterval = interval
+	}
+}
+
+// WithDefaulter registers the defaulting function of the given kind, applied
+// to the observed resources of that kind before exposing them to the
+// expressions. Depending on when they are read, observed resources may or may
+// not carry the defaults set by the API server, e.g the defaults of their CRD
+// schema; defaulting them makes the expressions see consistent values.
+func WithDefaulter(gvk schema.GroupVersionKind, defaulter Defaulter) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		if rt.defaulters == nil {
+			rt.defaulters = make(map[schema.GroupVersionKind]Defaulter)
+		}
+		rt.defaulters[gvk] = defaulter
+	}
+}
+
+// WithValidationRules declares invariants of the instance, checked before
+// resolving anything by Synchronize, which fails with ErrValidationFailed and
+// the messages of the failing rules. See ValidateRules.
+func WithValidationRules(rules ...ValidationRule) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.validationRules = append(rt.validationRules, rules...)
+	}
+}
+
+// WithMaxSynchronizeIterations caps the number of Synchronize passes that
+// don't converge between two changes of the inputs of the runtime (the
+// instance, the observed and the ignored resources),
+// DefaultMaxSynchronizeIterations by default. Past the cap, Synchronize fails
+// with ErrMaxSynchronizeIterations, and a diagnostic of the unresolved
+// expressions.
+func WithMaxSynchronizeIterations(iterations int) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.maxSynchronizeIterations = iterations
+	}
+}
+
+// WithClusterInfo exposes the identity of the cluster the instance is
+// reconciled in, e.g its name, region or provider, to the expressions under
+// the "kroCluster" variable. This lets a resource graph definition deployed to a
+// fleet of clusters configure its resources per cluster. e.g
+// "${kroCluster.region == 'eu-west-1' ? 'eu' : 'us'}"
+func WithClusterInfo(clusterInfo map[string]interface{}) Option {
+	return func(rt *ResourceGraphDefinitionRuntime) {
+		rt.clusterInfo = clusterInfo
+	}
+}
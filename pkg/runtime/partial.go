@@ -0,0 +1,106 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/kro-run/kro/pkg/runtime/resolver"
+)
+
+// SynchronizeResource re-evaluates the dynamic expressions depending on the
+// given resource, e.g after its observed state was updated with SetResource,
+// and renders again the resources and the instance fields using them. Unlike
+// Synchronize, the rest of the graph isn't evaluated, which suits the common
+// case of a single resource changing between reconciles.
+//
+// It returns true if the value of a re-evaluated expression changed, a.k.a
+// the dependents of the resource need to be updated. It fails with
+// ErrUnknownResource if the resource isn't part of the graph.
+func (rt *ResourceGraphDefinitionRuntime) SynchronizeResource(id string) (bool, error) {
+	if _, ok := rt.resources[id]; !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnknownResource, id)
+	}
+
+	previous := make(map[string]interface{})
+	for _, state := range rt.expressionsCache {
+		if state.Resolved && dependsOn(state, id) {
+			previous[state.Expression] = state.ResolvedValue
+		}
+	}
+	rt.invalidateExpressionsDependingOn(id)
+	affected := slices.DeleteFunc(rt.dynamicEvaluationOrder(), func(state *expressionEvaluationState) bool {
+		return !dependsOn(state, id)
+	})
+	if err := rt.evaluateDynamicVariablesOf(affected); err != nil {
+		return false, fmt.Errorf("failed to evaluate dynamic variables: %w", err)
+	}
+
+	changed := false
+	consumers := make(map[string]bool)
+	for _, state := range affected {
+		value, wasResolved := previous[state.Expression]
+		if state.Resolved != wasResolved || !reflect.DeepEqual(state.ResolvedValue, value) {
+			changed = true
+		}
+		for _, consumer := range rt.expressionConsumers[state.Expression] {
+			consumers[consumer] = true
+		}
+	}
+
+	for _, consumer := range rt.topologicalOrder {
+		if !consumers[consumer] || !rt.canProcessResource(consumer) {
+			continue
+		}
+		// The rendered fields replaced their templates, which are needed to
+		// render the fields again.
+		if err := rt.restoreTemplateFields(consumer); err != nil {
+			return false, fmt.Errorf("failed to restore the templates of resource %s: %w", consumer, err)
+		}
+		if err := rt.evaluateResourceExpressions(consumer); err != nil {
+			return false, fmt.Errorf("failed to evaluate resource variables for %s: %w", consumer, err)
+		}
+	}
+	if consumers["instance"] {
+		if err := rt.evaluateInstanceStatuses(); err != nil {
+			return false, fmt.Errorf("failed to evaluate instance statuses: %w", err)
+		}
+	}
+	return changed, nil
+}
+
+// dependsOn returns true if the expression is a dynamic expression depending
+// on the given resource.
+func dependsOn(state *expressionEvaluationState, id string) bool {
+	return state.Kind.IsDynamic() && slices.Contains(state.Dependencies, id)
+}
+
+// restoreTemplateFields sets the fields of the resource holding expressions
+// back to their template values.
+func (rt *ResourceGraphDefinitionRuntime) restoreTemplateFields(id string) error {
+	template := resolver.NewResolver(rt.templates[id], nil)
+	rs := resolver.NewResolver(rt.resources[id].Unstructured().Object, nil)
+	for _, variable := range rt.resources[id].GetVariables() {
+		value, err := template.ValueAtPath(variable.Path)
+		if err != nil {
+			return err
+		}
+		if err := rs.UpsertValueAtPath(variable.Path, deepCopyValue(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_SynchronizeResource(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	dynamicField := func(path, expression, dependency string, standalone bool) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expression},
+				StandaloneExpression: standalone,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{dependency},
+		}
+	}
+	resources := map[string]Resource{
+		"database": newTestResource(),
+		"cache":    newTestResource(),
+		"application": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"url": "postgres://${database.spec.host}:5432"},
+			}),
+			withDependencies([]string{"database"}),
+			withVariables([]*variable.ResourceField{
+				dynamicField("data.url", "database.spec.host", "database", false),
+			}),
+		),
+		"worker": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"cache": "${cache.spec.host}"},
+			}),
+			withDependencies([]string{"cache"}),
+			withVariables([]*variable.ResourceField{
+				dynamicField("data.cache", "cache.spec.host", "cache", true),
+			}),
+		),
+	}
+	instance := newTestResource(withVariables([]*variable.ResourceField{
+		dynamicField("status.database", "database.spec.host", "database", true),
+	}))
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources,
+		[]string{"database", "cache", "application", "worker"}, WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	observe := func(id, host string) {
+		rt.SetResource(id, &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"host": host},
+		}})
+	}
+	observe("database", "db-1")
+	observe("cache", "cache-1")
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	exporter.Reset()
+
+	observe("database", "db-2")
+	changed, err := rt.SynchronizeResource("database")
+	if err != nil {
+		t.Fatalf("SynchronizeResource() error = %v", err)
+	}
+	if !changed {
+		t.Errorf("SynchronizeResource() = false, want true")
+	}
+
+	// Only the expression depending on the database is evaluated again.
+	var evaluated []string
+	for _, span := range exporter.GetSpans() {
+		for _, kv := range span.Attributes {
+			if kv.Key == attributeExpression {
+				evaluated = append(evaluated, kv.Value.AsString())
+			}
+		}
+	}
+	if len(evaluated) != 1 || evaluated[0] != "database.spec.host" {
+		t.Errorf("evaluated expressions = %v, want [database.spec.host]", evaluated)
+	}
+
+	// The direct dependents are rendered again.
+	application, _ := rt.GetResource("application")
+	if got, _, _ := unstructured.NestedString(application.Object, "data", "url"); got != "postgres://db-2:5432" {
+		t.Errorf("application data.url = %q, want %q", got, "postgres://db-2:5432")
+	}
+	if got, _, _ := unstructured.NestedString(rt.GetInstance().Object, "status", "database"); got != "db-2" {
+		t.Errorf("instance status.database = %q, want %q", got, "db-2")
+	}
+	worker, _ := rt.GetResource("worker")
+	if got, _, _ := unstructured.NestedString(worker.Object, "data", "cache"); got != "cache-1" {
+		t.Errorf("worker data.cache = %q, want %q", got, "cache-1")
+	}
+
+	// Nothing changed since.
+	if changed, err := rt.SynchronizeResource("database"); err != nil || changed {
+		t.Errorf("SynchronizeResource() = %v, %v, want false, nil", changed, err)
+	}
+
+	if _, err := rt.SynchronizeResource("unknown"); !errors.Is(err, ErrUnknownResource) {
+		t.Errorf("SynchronizeResource() error = %v, want %v", err, ErrUnknownResource)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResolutionPlan is a snapshot of what the runtime is going to do given the
+// data it currently has: in which order the resources are created, which ones
+// are conditionally included, what they resolve to and what they're still
+// waiting on.
+type ResolutionPlan struct {
+	// Order is the order in which the resources are going to be created.
+	Order []string
+	// Resources holds the plan of every resource, in creation order.
+	Resources []ResourcePlan
+	// PendingInstanceExpressions are the instance status expressions that
+	// are not resolved yet.
+	PendingInstanceExpressions []string
+}
+
+// ResourcePlan describes the plan of a single resource.
+type ResourcePlan struct {
+	// ID is the id of the resource.
+	ID string
+	// State is the runtime state of the resource.
+	State ResourceState
+	// Conditional is true if the resource has includeWhen expressions.
+	Conditional bool
+	// Included is false if the resource is ignored, either because one of its
+	// includeWhen expressions evaluated to false or because one of its
+	// dependencies is ignored.
+	Included bool
+	// SkippedByCondition is the includeWhen expression that evaluated to
+	// false, if any.
+	SkippedByCondition string
+	// Object is the rendered object of the resource. It is only set when all
+	// the resource variables are resolved.
+	Object *unstructured.Unstructured
+	// PendingDependencies are the dependencies that are neither resolved nor
+	// ignored yet.
+	PendingDependencies []string
+	// PendingExpressions are the resource expressions that are not resolved
+	// yet.
+	PendingExpressions []string
+}
+
+// Plan returns the resolution plan of the runtime. It doesn't modify the
+// runtime state, e.g resources whose conditions evaluate to false are reported
+// as not included, but are not ignored by the runtime.
+func (rt *ResourceGraphDefinitionRuntime) Plan() (*ResolutionPlan, error) {
+	plan := &ResolutionPlan{
+		Order:                      slices.Clone(rt.topologicalOrder),
+		PendingInstanceExpressions: pendingExpressions(rt.runtimeVariables["instance"]),
+	}
+
+	// excluded tracks the resources that are ignored or planned to be
+	// skipped, so that their dependents are skipped as well.
+	excluded := make(map[string]bool)
+	for _, id := range rt.topologicalOrder {
+		resource := rt.resources[id]
+		resourcePlan := ResourcePlan{
+			ID:                 id,
+			Conditional:        len(resource.GetIncludeWhenExpressions()) > 0,
+			Included:           true,
+			PendingExpressions: pendingExpressions(rt.runtimeVariables[id]),
+		}
+
+		for _, dep := range resource.GetDependencies() {
+			if excluded[dep] && !rt.handlesIgnoredDependency(id, dep) {
+				resourcePlan.Included = false
+			}
+			if _, ok := rt.resolvedResources[dep]; !ok && !excluded[dep] {
+				resourcePlan.PendingDependencies = append(resourcePlan.PendingDependencies, dep)
+			}
+		}
+
+		if resourcePlan.Included && !rt.ignoredByConditionsResources[id] {
+			included, condition, err := rt.evaluateIncludeWhen(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed evaluating conditions of resource %s: %w", id, err)
+			}
+			resourcePlan.Included = included
+			resourcePlan.SkippedByCondition = condition
+		} else {
+			resourcePlan.Included = false
+		}
+
+		if !resourcePlan.Included {
+			excluded[id] = true
+			resourcePlan.State = ResourceStateIgnoredByConditions
+			plan.Resources = append(plan.Resources, resourcePlan)
+			continue
+		}
+
+		obj, state := rt.GetResource(id)
+		resourcePlan.State = state
+		if obj != nil {
+			resourcePlan.Object = obj.DeepCopy()
+		}
+		plan.Resources = append(plan.Resources, resourcePlan)
+	}
+	return plan, nil
+}
+
+// pendingExpressions returns the unresolved expressions of the given
+// variables.
+func pendingExpressions(variables []*expressionEvaluationState) []string {
+	var pending []string
+	for _, variable := range variables {
+		if !variable.Resolved && !slices.Contains(pending, variable.Expression) {
+			pending = append(pending, variable.Expression)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_Plan(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name":         "myapp",
+				"cacheEnabled": false,
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.replicas",
+					Expressions:          []string{"deployment.status.replicas"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"deployment"},
+			},
+		}),
+	)
+	configmap := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "${schema.spec.name}"},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.name",
+					Expressions:          []string{"schema.spec.name"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+		}),
+	)
+	deployment := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+			"spec":     map[string]interface{}{"config": "${configmap.metadata.name}"},
+		}),
+		withDependencies([]string{"configmap"}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "spec.config",
+					Expressions:          []string{"configmap.metadata.name"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"configmap"},
+			},
+		}),
+	)
+	cache := newTestResource(
+		withConditions([]string{"schema.spec.cacheEnabled"}),
+	)
+	cacheConfig := newTestResource(
+		withDependencies([]string{"cache"}),
+	)
+
+	order := []string{"cache", "configmap", "cacheConfig", "deployment"}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{
+		"configmap":   configmap,
+		"deployment":  deployment,
+		"cache":       cache,
+		"cacheConfig": cacheConfig,
+	}, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	plan, err := rt.Plan()
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := &ResolutionPlan{
+		Order: order,
+		Resources: []ResourcePlan{
+			{
+				ID:                 "cache",
+				State:              ResourceStateIgnoredByConditions,
+				Conditional:        true,
+				SkippedByCondition: "schema.spec.cacheEnabled",
+			},
+			{
+				ID:       "configmap",
+				State:    ResourceStateResolved,
+				Included: true,
+				Object: &unstructured.Unstructured{Object: map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "myapp"},
+				}},
+			},
+			{
+				ID:    "cacheConfig",
+				State: ResourceStateIgnoredByConditions,
+			},
+			{
+				ID:                  "deployment",
+				State:               ResourceStateWaitingOnDependencies,
+				Included:            true,
+				PendingDependencies: []string{"configmap"},
+				PendingExpressions:  []string{"configmap.metadata.name"},
+			},
+		},
+		PendingInstanceExpressions: []string{"deployment.status.replicas"},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("Plan() = %+v, want %+v", plan, want)
+	}
+	if _, ignored := rt.ignoredByConditionsResources["cache"]; ignored {
+		t.Error("Plan() must not ignore resources")
+	}
+
+	// Once the configmap is created, the deployment can be rendered.
+	rt.SetResource("configmap", &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "myapp"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	plan, err = rt.Plan()
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	got := plan.Resources[3]
+	if got.State != ResourceStateResolved || len(got.PendingDependencies) != 0 || len(got.PendingExpressions) != 0 {
+		t.Errorf("deployment plan = %+v, want resolved without pending dependencies", got)
+	}
+	wantSpec := map[string]interface{}{"config": "myapp"}
+	if got.Object == nil || !reflect.DeepEqual(got.Object.Object["spec"], wantSpec) {
+		t.Errorf("deployment object = %v, want spec %v", got.Object, wantSpec)
+	}
+}
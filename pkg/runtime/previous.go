@@ -0,0 +1,113 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// previousVariableName is the name of the variable exposing the resolved
+// state of the previous reconcile to the expressions, see WithPreviousState.
+const previousVariableName = "previous"
+
+// ResolvedState returns a snapshot of the resolved resources, keyed by
+// resource id. The snapshot can be persisted, and loaded in the runtime of
+// the next reconcile with WithPreviousState, for the expressions to detect
+// changes.
+func (rt *ResourceGraphDefinitionRuntime) ResolvedState() map[string]interface{} {
+	state := make(map[string]interface{}, len(rt.resolvedResources))
+	for id, resource := range rt.resolvedResources {
+		state[id] = runtime.DeepCopyJSON(resource.Object)
+	}
+	return state
+}
+
+// ReferencedState returns the part of the resolved state (see ResolvedState)
+// read by the expressions through the "previous" variable, to be loaded in
+// the next reconcile with WithPreviousState. Keeping only the referenced
+// fields bounds the memory held between reconciles. It returns nil if no
+// expression references the previous state.
+func (rt *ResourceGraphDefinitionRuntime) ReferencedState() map[string]interface{} {
+	paths, whole := rt.previousStatePaths()
+	if whole {
+		return rt.ResolvedState()
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	resolved := make(map[string]interface{}, len(rt.resolvedResources))
+	for id, resource := range rt.resolvedResources {
+		resolved[id] = resource.Object
+	}
+	state := make(map[string]interface{})
+	for _, path := range compactFieldPaths(paths) {
+		// Like the memoization keys, the deepest existing field holding the
+		// referenced one is kept, which tells if the field is missing.
+		found, value, ok := lookupFieldPath(resolved, path)
+		if !ok {
+			continue
+		}
+		_ = unstructured.SetNestedField(state, runtime.DeepCopyJSONValue(value), strings.Split(found, ".")...)
+	}
+	return state
+}
+
+// previousStatePaths returns the paths of the previous state read by the
+// expressions, without the "previous" prefix. It returns true if an
+// expression reads the previous state as a whole, or can't be inspected.
+func (rt *ResourceGraphDefinitionRuntime) previousStatePaths() ([]string, bool) {
+	expressions := make([]string, 0, len(rt.expressionsCache))
+	for expression := range rt.expressionsCache {
+		expressions = append(expressions, expression)
+	}
+	for _, resource := range rt.resources {
+		expressions = append(expressions, resource.GetIncludeWhenExpressions()...)
+	}
+
+	var paths []string
+	for _, expression := range expressions {
+		if !strings.Contains(expression, previousVariableName) {
+			continue
+		}
+		inspection, err := rt.inspectReferences(expression)
+		if err != nil {
+			return nil, true
+		}
+		for _, dependency := range inspection.ResourceDependencies {
+			if dependency.ID != previousVariableName {
+				continue
+			}
+			path, ok := strings.CutPrefix(dependency.Path, previousVariableName+".")
+			if !ok {
+				return nil, true
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, false
+}
+
+// previousStateOrEmpty returns the loaded previous state, or an empty state
+// on the first reconcile, so that expressions can check for the previous
+// values with has().
+func (rt *ResourceGraphDefinitionRuntime) previousStateOrEmpty() map[string]interface{} {
+	if rt.previousState == nil {
+		return map[string]interface{}{}
+	}
+	return rt.previousState
+}
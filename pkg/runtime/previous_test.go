@@ -0,0 +1,181 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_PreviousState(t *testing.T) {
+	const (
+		changedExpr  = "has(previous.config) && previous.config.data.version != config.data.version"
+		previousExpr = "has(previous.config) ? previous.config.data.version : 'none'"
+	)
+	reconcile := func(version string, opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		field := func(path, expr string) *variable.ResourceField {
+			return &variable.ResourceField{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 path,
+					Expressions:          []string{expr},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"config"},
+			}
+		}
+		resources := map[string]Resource{
+			"config": newTestResource(),
+			"deployment": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{
+						"restart":  "${" + changedExpr + "}",
+						"previous": "${" + previousExpr + "}",
+					},
+				}),
+				withDependencies([]string{"config"}),
+				withVariables([]*variable.ResourceField{
+					field("spec.restart", changedExpr),
+					field("spec.previous", previousExpr),
+				}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"config", "deployment"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("config", &unstructured.Unstructured{Object: map[string]interface{}{
+			"data": map[string]interface{}{"version": version},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+	assertValue := func(rt *ResourceGraphDefinitionRuntime, expr string, want interface{}) {
+		t.Helper()
+		resolved, value, err := rt.ExpressionState(expr)
+		if err != nil || !resolved {
+			t.Fatalf("ExpressionState(%q) = %v, %v, want resolved", expr, resolved, err)
+		}
+		if value != want {
+			t.Errorf("%s = %v, want %v", expr, value, want)
+		}
+	}
+
+	// First reconcile, without previous state.
+	first := reconcile("1")
+	assertValue(first, changedExpr, false)
+	assertValue(first, previousExpr, "none")
+	snapshot := first.ResolvedState()
+
+	// The snapshot is a copy, unaffected by the later changes.
+	first.SetResource("config", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"version": "ignored"},
+	}})
+
+	// The value changed since the previous reconcile.
+	second := reconcile("2", WithPreviousState(snapshot))
+	assertValue(second, changedExpr, true)
+	assertValue(second, previousExpr, "1")
+
+	// The value is unchanged since the previous reconcile.
+	third := reconcile("2", WithPreviousState(second.ResolvedState()))
+	assertValue(third, changedExpr, false)
+	assertValue(third, previousExpr, "2")
+}
+
+func Test_ReferencedState(t *testing.T) {
+	newRuntime := func(expr string) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"config": newTestResource(),
+			"deployment": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{"value": "${" + expr + "}"},
+				}),
+				withDependencies([]string{"config"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.value",
+							Expressions:          []string{expr},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"config"},
+					},
+				}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"config", "deployment"})
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("config", &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "config"},
+			"data": map[string]interface{}{
+				"version": "1",
+				"payload": "large",
+			},
+		}})
+		rt.SetResource("deployment", &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "deployment"},
+		}})
+		return rt
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want map[string]interface{}
+	}{
+		{
+			name: "referenced fields only",
+			expr: "has(previous.config.data.version) && previous.config.data.version != config.data.version",
+			want: map[string]interface{}{
+				"config": map[string]interface{}{
+					"data": map[string]interface{}{"version": "1"},
+				},
+			},
+		},
+		{
+			name: "missing fields keep their parent",
+			expr: "has(previous.config.data.missing) && config.data.version == ''",
+			want: map[string]interface{}{
+				"config": map[string]interface{}{
+					"data": map[string]interface{}{"version": "1", "payload": "large"},
+				},
+			},
+		},
+		{
+			name: "no reference to the previous state",
+			expr: "config.data.version",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newRuntime(tt.expr).ReferencedState()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReferencedState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
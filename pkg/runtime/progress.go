@@ -0,0 +1,82 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// DefaultProgressInterval is the default minimum delay between two progress
+// reports, see WithProgressCallback.
+const DefaultProgressInterval = 5 * time.Second
+
+// Progress is the resolution progress of the runtime, reported during
+// Synchronize, see WithProgressCallback.
+type Progress struct {
+	// ResolvedExpressions is the number of resolved expressions.
+	ResolvedExpressions int
+	// TotalExpressions is the number of expressions to resolve, readyWhen
+	// expressions excluded.
+	TotalExpressions int
+}
+
+// String returns the progress in a human readable form, e.g
+// "resolving 12/30 expressions".
+func (p Progress) String() string {
+	return fmt.Sprintf("resolving %d/%d expressions", p.ResolvedExpressions, p.TotalExpressions)
+}
+
+// Complete returns true if every expression is resolved.
+func (p Progress) Complete() bool {
+	return p.ResolvedExpressions == p.TotalExpressions
+}
+
+// currentProgress returns the resolution progress of the runtime.
+func (rt *ResourceGraphDefinitionRuntime) currentProgress() Progress {
+	var progress Progress
+	for _, state := range rt.expressionsCache {
+		if state.Kind == variable.ResourceVariableKindReadyWhen {
+			continue
+		}
+		progress.TotalExpressions++
+		if state.Resolved {
+			progress.ResolvedExpressions++
+		}
+	}
+	return progress
+}
+
+// reportProgress calls the progress callback, if any, after a resolution
+// milestone of Synchronize. Reports are skipped when nothing changed since
+// the last one, and rate-limited to one per progress interval to avoid
+// excessive status writes, except for the completion report.
+func (rt *ResourceGraphDefinitionRuntime) reportProgress() {
+	if rt.progressCallback == nil {
+		return
+	}
+	progress := rt.currentProgress()
+	if rt.progressReported && progress == rt.lastProgress {
+		return
+	}
+	now := rt.currentTime()
+	if rt.progressReported && !progress.Complete() && now.Sub(rt.lastProgressReport) < rt.progressInterval {
+		return
+	}
+	rt.progressReported = true
+	rt.lastProgress, rt.lastProgressReport = progress, now
+	rt.progressCallback(progress)
+}
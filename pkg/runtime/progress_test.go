@@ -0,0 +1,110 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ProgressCallback(t *testing.T) {
+	consumer := func(dependency string) Resource {
+		expression := dependency + ".spec.value"
+		return newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${" + expression + "}"},
+			}),
+			withDependencies([]string{dependency}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{expression},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{dependency},
+				},
+			}),
+		)
+	}
+	resources := map[string]Resource{
+		"database":    newTestResource(),
+		"cache":       newTestResource(),
+		"application": consumer("database"),
+		"worker":      consumer("cache"),
+	}
+
+	var reports []Progress
+	rt, err := NewResourceGraphDefinitionRuntime(
+		newTestResource(),
+		resources,
+		[]string{"database", "cache", "application", "worker"},
+		WithProgressCallback(time.Minute, func(progress Progress) {
+			reports = append(reports, progress)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	clock := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rt.now = func() time.Time { return clock }
+
+	observe := func(id string) {
+		rt.SetResource(id, &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": id},
+		}})
+	}
+	synchronize := func(wantReports int) {
+		t.Helper()
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if len(reports) != wantReports {
+			t.Fatalf("got %d reports, want %d: %v", len(reports), wantReports, reports)
+		}
+	}
+
+	// The first report is never rate-limited, the following milestones of
+	// the pass didn't change anything.
+	synchronize(1)
+	// Rate-limited.
+	observe("database")
+	synchronize(1)
+	// Reported once the interval elapsed.
+	clock = clock.Add(time.Minute)
+	synchronize(2)
+	// The completion is always reported.
+	observe("cache")
+	synchronize(3)
+	// Nothing left to resolve.
+	synchronize(3)
+
+	want := []Progress{
+		{ResolvedExpressions: 0, TotalExpressions: 2},
+		{ResolvedExpressions: 1, TotalExpressions: 2},
+		{ResolvedExpressions: 2, TotalExpressions: 2},
+	}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("reports = %v, want %v", reports, want)
+	}
+	if got, want := reports[1].String(), "resolving 1/2 expressions"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/cel/ast"
+)
+
+// ReadinessProbe checks the readiness of a resource that can't be expressed
+// with readyWhen expressions against the object, e.g an HTTP health check.
+// The runtime only declares the hook, the controller implements the probe.
+type ReadinessProbe interface {
+	// Probe returns whether the resource is ready, given its observed
+	// object, and the reason it isn't.
+	Probe(resourceID string, observed *unstructured.Unstructured) (ready bool, reason string, err error)
+}
+
+// probeReadiness runs the readiness probe of the resource, if any. Resources
+// without probe are ready.
+func (rt *ResourceGraphDefinitionRuntime) probeReadiness(resourceID string, observed *unstructured.Unstructured) (bool, string, error) {
+	probe, ok := rt.readinessProbes[resourceID]
+	if !ok {
+		return true, "", nil
+	}
+	ready, reason, err := probe.Probe(resourceID, observed)
+	if err != nil {
+		return false, "", fmt.Errorf("failed probing readiness of resource %s: %w", resourceID, err)
+	}
+	if !ready && reason == "" {
+		reason = fmt.Sprintf("readiness probe of resource %s failed", resourceID)
+	}
+	return ready, reason, nil
+}
+
+// readinessAttempts tracks the consecutive failed readiness checks of a
+// resource, for the observed generation of the resource.
+type readinessAttempts struct {
+	failures   int
+	generation int64
+}
+
+// ReadinessAttempts returns the number of consecutive IsResourceReady calls
+// that reported the resource as not ready. The count is reset when the
+// resource becomes ready, or when its observed generation changes.
+func (rt *ResourceGraphDefinitionRuntime) ReadinessAttempts(resourceID string) int {
+	attempts, ok := rt.readinessAttempts[resourceID]
+	if !ok {
+		return 0
+	}
+	return attempts.failures
+}
+
+// recordReadinessAttempt records the result of a readiness check. Checks of
+// resources that aren't observed yet are not recorded.
+func (rt *ResourceGraphDefinitionRuntime) recordReadinessAttempt(resourceID string, ready bool) {
+	observed, ok := rt.resolvedResources[resourceID]
+	if !ok || observed == nil {
+		return
+	}
+	if rt.readinessAttempts == nil {
+		rt.readinessAttempts = make(map[string]*readinessAttempts)
+	}
+
+	attempts, ok := rt.readinessAttempts[resourceID]
+	if !ok || attempts.generation != observed.GetGeneration() {
+		attempts = &readinessAttempts{generation: observed.GetGeneration()}
+		rt.readinessAttempts[resourceID] = attempts
+	}
+	if ready {
+		attempts.failures = 0
+		return
+	}
+	attempts.failures++
+}
+
+// referencesReadiness returns true if the expression references the
+// readiness of its dependencies, see readyVariableName. Expressions that fail
+// inspection fail evaluation as well, they are assumed not to reference it.
+func (rt *ResourceGraphDefinitionRuntime) referencesReadiness(expression string) bool {
+	if references, ok := rt.readinessReferences[expression]; ok {
+		return references
+	}
+	references := false
+	if inspection, err := rt.inspectReferences(expression); err == nil {
+		references = slices.ContainsFunc(inspection.UnknownResources, func(unknown ast.UnknownResource) bool {
+			return unknown.ID == readyVariableName
+		})
+	}
+	if rt.readinessReferences == nil {
+		rt.readinessReferences = make(map[string]bool)
+	}
+	rt.readinessReferences[expression] = references
+	return references
+}
+
+// dependenciesReadiness returns the readiness of the given dependencies, keyed
+// by resource id. The readiness of each resource is computed once and stored
+// in the given cache.
+func (rt *ResourceGraphDefinitionRuntime) dependenciesReadiness(dependencies []string, cache map[string]bool) (map[string]interface{}, error) {
+	readiness := make(map[string]interface{}, len(dependencies))
+	for _, dep := range dependencies {
+		ready, ok := cache[dep]
+		if !ok {
+			var err error
+			// Unresolved (i.e ignored) resources are reported not ready.
+			ready, _, err = rt.isResourceReady(dep)
+			if err != nil {
+				return nil, err
+			}
+			cache[dep] = ready
+		}
+		readiness[dep] = ready
+	}
+	return readiness, nil
+}
+
+// ReadinessResult is the readiness of a resource, as returned by
+// IsResourceReady.
+type ReadinessResult struct {
+	Ready  bool
+	Reason string
+}
+
+// EvaluateAllReadiness returns the readiness of every resolved resource, keyed
+// by resource id, like calling IsResourceReady on each of them. The readyWhen
+// expressions of all the resources are evaluated with a single environment,
+// instead of one per resource, which is much cheaper on large graphs.
+func (rt *ResourceGraphDefinitionRuntime) EvaluateAllReadiness() (map[string]ReadinessResult, error) {
+	ids := maps.Keys(rt.resolvedResources)
+	slices.Sort(ids)
+
+	var env *cel.Env
+	results := make(map[string]ReadinessResult, len(ids))
+	for _, id := range ids {
+		if _, ok := rt.resources[id]; !ok {
+			continue
+		}
+		if env == nil && len(rt.resources[id].GetReadyWhenExpressions()) > 0 {
+			var err error
+			env, err = rt.readinessEnvironment(ids)
+			if err != nil {
+				return nil, fmt.Errorf("failed creating new Environment: %w", err)
+			}
+		}
+		ready, reason, err := rt.evaluateReadiness(id, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed evaluating readiness of resource %s: %w", id, err)
+		}
+		rt.recordReadinessAttempt(id, ready)
+		results[id] = ReadinessResult{Ready: ready, Reason: reason}
+	}
+	return results, nil
+}
+
+// readinessEnvironment returns the environment of the readyWhen expressions
+// of the given resources, cached by set of resources.
+func (rt *ResourceGraphDefinitionRuntime) readinessEnvironment(resourceIDs []string) (*cel.Env, error) {
+	sorted := slices.Clone(resourceIDs)
+	slices.Sort(sorted)
+	return rt.cachedEnvironment(environmentKey{name: "readiness:" + strings.Join(sorted, ",")}, func() (*cel.Env, error) {
+		return krocel.DefaultEnvironment(
+			krocel.WithResourceIDs(sorted),
+			krocel.WithNativeTypes(rt.nativeTypes()...),
+		)
+	})
+}
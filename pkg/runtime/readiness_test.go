@@ -0,0 +1,372 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ReadinessAttempts(t *testing.T) {
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), map[string]Resource{
+		"deployment": newTestResource(
+			withReadyExpressions([]string{"deployment.status.ready"}),
+		),
+	}, []string{"deployment"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	observe := func(generation int64, ready bool) {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": ready},
+		}}
+		obj.SetGeneration(generation)
+		rt.SetResource("deployment", obj)
+	}
+	check := func(wantReady bool, wantAttempts int) {
+		t.Helper()
+		ready, _, err := rt.IsResourceReady("deployment")
+		if err != nil {
+			t.Fatalf("IsResourceReady() error = %v", err)
+		}
+		if ready != wantReady {
+			t.Fatalf("IsResourceReady() = %v, want %v", ready, wantReady)
+		}
+		if got := rt.ReadinessAttempts("deployment"); got != wantAttempts {
+			t.Fatalf("ReadinessAttempts() = %d, want %d", got, wantAttempts)
+		}
+	}
+
+	// Not observed yet: nothing is recorded.
+	if _, _, err := rt.IsResourceReady("deployment"); err != nil {
+		t.Fatalf("IsResourceReady() error = %v", err)
+	}
+	if got := rt.ReadinessAttempts("deployment"); got != 0 {
+		t.Fatalf("ReadinessAttempts() = %d, want 0", got)
+	}
+
+	observe(1, false)
+	check(false, 1)
+	check(false, 2)
+	check(false, 3)
+
+	// A new generation resets the count.
+	observe(2, false)
+	check(false, 1)
+	check(false, 2)
+
+	// Becoming ready resets the count.
+	observe(2, true)
+	check(true, 0)
+	observe(2, false)
+	check(false, 1)
+}
+
+// stubReadinessProbe is a ReadinessProbe returning a fixed result, and
+// recording the resources it probed.
+type stubReadinessProbe struct {
+	ready  bool
+	reason string
+	err    error
+	probed []string
+}
+
+func (p *stubReadinessProbe) Probe(resourceID string, _ *unstructured.Unstructured) (bool, string, error) {
+	p.probed = append(p.probed, resourceID)
+	return p.ready, p.reason, p.err
+}
+
+func Test_ReadinessProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		readyWhen   []string
+		statusReady bool
+		probe       *stubReadinessProbe
+		wantReady   bool
+		wantReason  string
+		wantErr     bool
+		wantProbed  bool
+	}{
+		{
+			name:       "probe ready without expressions",
+			probe:      &stubReadinessProbe{ready: true},
+			wantReady:  true,
+			wantProbed: true,
+		},
+		{
+			name:       "probe not ready without expressions",
+			probe:      &stubReadinessProbe{reason: "health check returned 503"},
+			wantReason: "health check returned 503",
+			wantProbed: true,
+		},
+		{
+			name:       "probe not ready without reason",
+			probe:      &stubReadinessProbe{},
+			wantReason: "readiness probe of resource database failed",
+			wantProbed: true,
+		},
+		{
+			name:        "probe not ready in addition to ready expressions",
+			readyWhen:   []string{"database.status.ready"},
+			statusReady: true,
+			probe:       &stubReadinessProbe{reason: "not accepting connections"},
+			wantReason:  "not accepting connections",
+			wantProbed:  true,
+		},
+		{
+			name:        "expressions not ready skip the probe",
+			readyWhen:   []string{"database.status.ready"},
+			statusReady: false,
+			probe:       &stubReadinessProbe{ready: true},
+			wantReason:  "expression database.status.ready evaluated to false",
+		},
+		{
+			name:       "probe error",
+			probe:      &stubReadinessProbe{err: errors.New("connection refused")},
+			wantErr:    true,
+			wantProbed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), map[string]Resource{
+				"database": newTestResource(withReadyExpressions(tt.readyWhen)),
+			}, []string{"database"}, WithReadinessProbe("database", tt.probe))
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+			rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"ready": tt.statusReady},
+			}})
+
+			ready, reason, err := rt.IsResourceReady("database")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsResourceReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ready != tt.wantReady || reason != tt.wantReason {
+				t.Errorf("IsResourceReady() = %v, %q, want %v, %q", ready, reason, tt.wantReady, tt.wantReason)
+			}
+			if probed := len(tt.probe.probed) > 0; probed != tt.wantProbed {
+				t.Errorf("probed = %v, want %v", probed, tt.wantProbed)
+			}
+		})
+	}
+}
+
+func Test_ReadinessDependencies(t *testing.T) {
+	resources := map[string]Resource{
+		"database": newTestResource(withReadyExpressions([]string{"database.status.ready"})),
+		"app": newTestResource(
+			withDependencies([]string{"database"}),
+			withReadinessDependencies([]string{"database"}),
+		),
+		"cache": newTestResource(withDependencies([]string{"database"})),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "app", "cache"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	setDatabase := func(ready bool) {
+		rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": ready},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+	}
+
+	// Not observed yet.
+	if _, state := rt.GetResource("app"); state != ResourceStateWaitingOnDependencies {
+		t.Errorf("app state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+	}
+
+	// Created but not ready: the app waits, while the resources only
+	// depending on the resolution of the database don't.
+	setDatabase(false)
+	if _, state := rt.GetResource("app"); state != ResourceStateWaitingOnDependencies {
+		t.Errorf("app state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+	}
+	if _, state := rt.GetResource("cache"); state != ResourceStateResolved {
+		t.Errorf("cache state = %v, want %v", state, ResourceStateResolved)
+	}
+
+	setDatabase(true)
+	if _, state := rt.GetResource("app"); state != ResourceStateResolved {
+		t.Errorf("app state = %v, want %v", state, ResourceStateResolved)
+	}
+
+	// Checking the readiness of the dependencies doesn't count as readiness
+	// attempts.
+	if attempts := rt.ReadinessAttempts("database"); attempts != 0 {
+		t.Errorf("ReadinessAttempts(database) = %d, want 0", attempts)
+	}
+}
+
+func Test_DependencyReadinessInContext(t *testing.T) {
+	// The readiness of the dependencies is read when the expressions are
+	// evaluated, i.e once per reconcile, like their fields.
+	for _, ready := range []bool{false, true} {
+		replicas := &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "spec.replicas",
+				Expressions:          []string{"ready.database && ready.cache ? 3 : 1"},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"database", "cache"},
+		}
+		resources := map[string]Resource{
+			"database": newTestResource(withReadyExpressions([]string{"database.status.ready"})),
+			"cache":    newTestResource(),
+			"app": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{"replicas": "${" + replicas.Expressions[0] + "}"},
+				}),
+				withDependencies([]string{"database", "cache"}),
+				withVariables([]*variable.ResourceField{replicas}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "cache", "app"})
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("cache", &unstructured.Unstructured{Object: map[string]interface{}{}})
+		rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": ready},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+
+		obj, state := rt.GetResource("app")
+		if state != ResourceStateResolved {
+			t.Fatalf("app state = %v, want %v", state, ResourceStateResolved)
+		}
+		want := int64(1)
+		if ready {
+			want = 3
+		}
+		if got := obj.Object["spec"].(map[string]interface{})["replicas"]; got != want {
+			t.Errorf("replicas with a ready database = %t: %v, want %d", ready, got, want)
+		}
+	}
+}
+
+func Test_EvaluateAllReadiness(t *testing.T) {
+	resources := map[string]Resource{
+		"database": newTestResource(
+			withReadyExpressions([]string{"database.status.ready"}),
+			withReadyMessages(map[string]string{"database.status.ready": "${database.status.phase}"}),
+		),
+		"cache":   newTestResource(withReadyExpressions([]string{"cache.status.ready"})),
+		"config":  newTestResource(),
+		"pending": newTestResource(withReadyExpressions([]string{"pending.status.ready"})),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "cache", "config", "pending"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("database", &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"ready": false, "phase": "Initializing"},
+	}})
+	rt.SetResource("cache", &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"ready": true},
+	}})
+	rt.SetResource("config", &unstructured.Unstructured{Object: map[string]interface{}{}})
+
+	got, err := rt.EvaluateAllReadiness()
+	if err != nil {
+		t.Fatalf("EvaluateAllReadiness() error = %v", err)
+	}
+	// Resources that aren't observed yet are left out.
+	want := map[string]ReadinessResult{
+		"database": {Ready: false, Reason: "Initializing"},
+		"cache":    {Ready: true},
+		"config":   {Ready: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvaluateAllReadiness() = %v, want %v", got, want)
+	}
+	for id, result := range got {
+		ready, reason, err := rt.IsResourceReady(id)
+		if err != nil || ready != result.Ready || reason != result.Reason {
+			t.Errorf("IsResourceReady(%s) = %v, %q, %v, want %v", id, ready, reason, err, result)
+		}
+	}
+	// Like IsResourceReady, failed checks count as readiness attempts.
+	if attempts := rt.ReadinessAttempts("database"); attempts != 2 {
+		t.Errorf("ReadinessAttempts(database) = %d, want 2", attempts)
+	}
+}
+
+func Test_NonBooleanReadiness(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		resources: map[string]Resource{
+			"deployment": newTestResource(withReadyExpressions([]string{"deployment.status.ready"})),
+		},
+		resolvedResources: map[string]*unstructured.Unstructured{
+			"deployment": {Object: map[string]interface{}{
+				"status": map[string]interface{}{"ready": "yes"},
+			}},
+		},
+	}
+	ready, _, err := rt.IsResourceReady("deployment")
+	if err == nil {
+		t.Fatalf("IsResourceReady() = %v, want an error", ready)
+	}
+	if !strings.Contains(err.Error(), "expected bool") {
+		t.Errorf("IsResourceReady() error = %v, want a type error", err)
+	}
+}
+
+func Test_SkipReadiness(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		resources: map[string]Resource{
+			"deployment": newTestResource(
+				withReadyExpressions([]string{"deployment.status.ready"}),
+				withSkipReadiness(),
+			),
+			"pending": newTestResource(withSkipReadiness()),
+		},
+		resolvedResources: map[string]*unstructured.Unstructured{
+			"deployment": {Object: map[string]interface{}{
+				"status": map[string]interface{}{"ready": false},
+			}},
+		},
+	}
+
+	ready, reason, err := rt.IsResourceReady("deployment")
+	if err != nil {
+		t.Fatalf("IsResourceReady() error = %v", err)
+	}
+	if !ready || reason != "" {
+		t.Errorf("IsResourceReady() = %v, %q, want true with no reason", ready, reason)
+	}
+
+	// Skipping the readiness check doesn't make unresolved resources ready.
+	ready, _, err = rt.IsResourceReady("pending")
+	if err != nil {
+		t.Fatalf("IsResourceReady() error = %v", err)
+	}
+	if ready {
+		t.Error("IsResourceReady() = true for an unresolved resource, want false")
+	}
+}
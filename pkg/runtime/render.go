@@ -0,0 +1,166 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrResourceNotRendered is returned by RenderAllStrict when a resource can't
+// be rendered yet, e.g because its dependencies aren't resolved.
+var ErrResourceNotRendered = errors.New("resource can't be rendered yet")
+
+// RenderResource returns the desired state of the resource, computed with a
+// three-way merge of the resource template, the values resolved by kro and
+// the observed object. The fields set in the rendered template win, and the
+// fields only present in the observed object (server defaulted fields,
+// fields added by other controllers...) are preserved.
+//
+// Maps are merged recursively, while lists and scalar values of the template
+// replace the observed ones: without the schema, there is no way to know how
+// list items should be merged.
+func (rt *ResourceGraphDefinitionRuntime) RenderResource(id string) (*unstructured.Unstructured, ResourceState) {
+	if !rt.canProcessResource(id) {
+		return nil, ResourceStateWaitingOnDependencies
+	}
+
+	desired := rt.resources[id].Unstructured()
+	if rt.injectOwnerReferences {
+		rt.injectOwnerReference(id, desired)
+	}
+	if rt.prunedResources[id] {
+		desired = prunedCopy(desired)
+	}
+
+	rendered := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if observed, ok := rt.resolvedResources[id]; ok && observed != nil {
+		rendered.Object = deepCopyValue(observed.Object).(map[string]interface{})
+	}
+	mergeRenderedFields(rendered.Object, desired.Object)
+	return rendered, ResourceStateResolved
+}
+
+// RenderAll renders all the resources, see RenderResource, as a multi-document
+// YAML stream in topological order, e.g to output manifests for review or
+// GitOps. The resources that can't be rendered yet, and the resources
+// ignored by conditions, are skipped.
+func (rt *ResourceGraphDefinitionRuntime) RenderAll() ([]byte, error) {
+	return rt.renderAll(false)
+}
+
+// RenderAllStrict is like RenderAll, but fails with ErrResourceNotRendered if
+// one of the resources not ignored by conditions can't be rendered yet.
+func (rt *ResourceGraphDefinitionRuntime) RenderAllStrict() ([]byte, error) {
+	return rt.renderAll(true)
+}
+
+func (rt *ResourceGraphDefinitionRuntime) renderAll(strict bool) ([]byte, error) {
+	var out bytes.Buffer
+	for _, id := range rt.topologicalOrder {
+		if rt.ignoredByConditionsResources[id] {
+			continue
+		}
+		rendered, state := rt.RenderResource(id)
+		if state != ResourceStateResolved {
+			if strict {
+				return nil, fmt.Errorf("%w: %s", ErrResourceNotRendered, id)
+			}
+			continue
+		}
+		document, err := yaml.Marshal(rendered.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource %s: %w", id, err)
+		}
+		if out.Len() > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(document)
+	}
+	return out.Bytes(), nil
+}
+
+// mergeRenderedFields sets the fields of src in dst, merging the nested maps
+// present in both.
+func mergeRenderedFields(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeRenderedFields(dstMap, srcMap)
+			continue
+		}
+		dst[key] = deepCopyValue(value)
+	}
+}
+
+// deepCopyValue copies the maps and lists of the value. Unlike
+// runtime.DeepCopyJSONValue, it doesn't panic on non JSON scalar types
+// (e.g uint64 values produced by CEL expressions).
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyValue(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// prunedCopy returns a copy of the rendered object without its null fields,
+// see WithFieldPruning.
+func prunedCopy(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	pruned := deepCopyValue(obj.Object).(map[string]interface{})
+	pruneNullFields(pruned)
+	return &unstructured.Unstructured{Object: pruned}
+}
+
+// pruneNullFields removes the null fields of the map, recursively, and the
+// maps left empty once pruned. List items are never removed, to preserve
+// their positions, but the maps they hold are pruned.
+func pruneNullFields(obj map[string]interface{}) {
+	for key, value := range obj {
+		switch v := value.(type) {
+		case nil:
+			delete(obj, key)
+		case map[string]interface{}:
+			if len(v) == 0 {
+				continue
+			}
+			pruneNullFields(v)
+			if len(v) == 0 {
+				delete(obj, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					pruneNullFields(itemMap)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_RenderResource(t *testing.T) {
+	newRuntime := func(t *testing.T) *ResourceGraphDefinitionRuntime {
+		resources := map[string]Resource{
+			"deployment": newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": "app",
+					},
+					"spec": map[string]interface{}{
+						"replicas": "${schema.spec.replicas}",
+						"template": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "app", "image": "nginx"},
+							},
+						},
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.replicas",
+							Expressions:          []string{"schema.spec.replicas"},
+							StandaloneExpression: true,
+						},
+						Kind: variable.ResourceVariableKindStatic,
+					},
+				}),
+			),
+		}
+		instance := newTestResource(withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(3)},
+		}))
+		rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment"})
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		return rt
+	}
+
+	t.Run("without observed object", func(t *testing.T) {
+		rt := newRuntime(t)
+		got, state := rt.RenderResource("deployment")
+		if state != ResourceStateResolved {
+			t.Fatalf("RenderResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+		want := map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "app"},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "nginx"},
+					},
+				},
+			},
+		}
+		if !reflect.DeepEqual(got.Object, want) {
+			t.Errorf("RenderResource() = %v, want %v", got.Object, want)
+		}
+	})
+
+	t.Run("observed unmanaged fields are preserved", func(t *testing.T) {
+		rt := newRuntime(t)
+		rt.SetResource("deployment", &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":            "app",
+				"resourceVersion": "42",
+				"annotations": map[string]interface{}{
+					"other-controller/revision": "7",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas":             int64(5),
+				"progressDeadlineSecs": int64(600),
+				"template": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "nginx", "imagePullPolicy": "Always"},
+						map[string]interface{}{"name": "sidecar", "image": "injected"},
+					},
+				},
+			},
+			"status": map[string]interface{}{"readyReplicas": int64(5)},
+		}})
+
+		got, state := rt.RenderResource("deployment")
+		if state != ResourceStateResolved {
+			t.Fatalf("RenderResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+		want := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":            "app",
+				"resourceVersion": "42",
+				"annotations": map[string]interface{}{
+					"other-controller/revision": "7",
+				},
+			},
+			"spec": map[string]interface{}{
+				// managed by kro
+				"replicas": int64(3),
+				// server defaulted
+				"progressDeadlineSecs": int64(600),
+				"template": map[string]interface{}{
+					// lists are owned by the template
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "nginx"},
+					},
+				},
+			},
+			"status": map[string]interface{}{"readyReplicas": int64(5)},
+		}
+		if !reflect.DeepEqual(got.Object, want) {
+			t.Errorf("RenderResource() = %v, want %v", got.Object, want)
+		}
+
+		// The observed object is left untouched.
+		observed := rt.resolvedResources["deployment"].Object
+		if replicas := observed["spec"].(map[string]interface{})["replicas"]; replicas != int64(5) {
+			t.Errorf("observed spec.replicas = %v, want 5", replicas)
+		}
+	})
+}
+
+func Test_RenderAll(t *testing.T) {
+	dynamicField := func(path, expr, dependency string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{dependency},
+		}
+	}
+	// zone <- network <- app, ids deliberately not sorted alphabetically.
+	resources := map[string]Resource{
+		"zone": newTestResource(withObject(map[string]interface{}{
+			"kind":     "Zone",
+			"metadata": map[string]interface{}{"name": "zone"},
+		})),
+		"network": newTestResource(
+			withObject(map[string]interface{}{
+				"kind":     "Network",
+				"metadata": map[string]interface{}{"name": "network"},
+				"spec":     map[string]interface{}{"zone": "${zone.status.id}"},
+			}),
+			withDependencies([]string{"zone"}),
+			withVariables([]*variable.ResourceField{dynamicField("spec.zone", "zone.status.id", "zone")}),
+		),
+		"app": newTestResource(
+			withObject(map[string]interface{}{
+				"kind":     "App",
+				"metadata": map[string]interface{}{"name": "app"},
+				"spec":     map[string]interface{}{"network": "${network.status.id}"},
+			}),
+			withDependencies([]string{"network"}),
+			withVariables([]*variable.ResourceField{dynamicField("spec.network", "network.status.id", "network")}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"zone", "network", "app"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("zone", &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"id": "z-1"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	decodeKinds := func(t *testing.T, stream []byte) []string {
+		t.Helper()
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(stream), 4096)
+		var kinds []string
+		for {
+			obj := map[string]interface{}{}
+			if err := decoder.Decode(&obj); err != nil {
+				if errors.Is(err, io.EOF) {
+					return kinds
+				}
+				t.Fatalf("invalid YAML stream: %v\n%s", err, stream)
+			}
+			kinds = append(kinds, obj["kind"].(string))
+		}
+	}
+
+	// The app can't be rendered until the network is resolved.
+	stream, err := rt.RenderAll()
+	if err != nil {
+		t.Fatalf("RenderAll() error = %v", err)
+	}
+	if got, want := decodeKinds(t, stream), []string{"Zone", "Network"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderAll() kinds = %v, want %v", got, want)
+	}
+	if _, err := rt.RenderAllStrict(); !errors.Is(err, ErrResourceNotRendered) {
+		t.Errorf("RenderAllStrict() error = %v, want %v", err, ErrResourceNotRendered)
+	}
+
+	rt.SetResource("network", &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"id": "n-1"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	stream, err = rt.RenderAllStrict()
+	if err != nil {
+		t.Fatalf("RenderAllStrict() error = %v", err)
+	}
+	if got, want := decodeKinds(t, stream), []string{"Zone", "Network", "App"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderAllStrict() kinds = %v, want %v", got, want)
+	}
+	if !strings.Contains(string(stream), "network: n-1") {
+		t.Errorf("RenderAllStrict() = %s, want the resolved network id", stream)
+	}
+}
+
+func Test_FieldPruning(t *testing.T) {
+	const nullExpr = "has(schema.spec.tier) ? schema.spec.tier : null"
+	newRuntime := func(opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"app": newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "app"},
+					"spec": map[string]interface{}{
+						"replicas": "${schema.spec.replicas}",
+						"tier":     "${" + nullExpr + "}",
+						"selector": map[string]interface{}{
+							"tier": "${" + nullExpr + "}",
+						},
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app", "tier": "${" + nullExpr + "}"},
+						},
+						"extra": map[string]interface{}{},
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					staticField("spec.replicas", "schema.spec.replicas"),
+					staticField("spec.tier", nullExpr),
+					staticField("spec.selector.tier", nullExpr),
+					staticField("spec.containers[0].tier", nullExpr),
+				}),
+			),
+		}
+		instance := newTestResource(withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": int64(2)},
+		}))
+		rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"app"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		return rt
+	}
+
+	unpruned, _ := newRuntime().GetResource("app")
+	wantUnpruned := map[string]interface{}{
+		"replicas": int64(2),
+		"tier":     nil,
+		"selector": map[string]interface{}{"tier": nil},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "tier": nil},
+		},
+		"extra": map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(unpruned.Object["spec"], wantUnpruned) {
+		t.Errorf("unpruned spec = %v, want %v", unpruned.Object["spec"], wantUnpruned)
+	}
+
+	rt := newRuntime(WithFieldPruning("app"))
+	wantPruned := map[string]interface{}{
+		"replicas": int64(2),
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app"},
+		},
+		"extra": map[string]interface{}{},
+	}
+	pruned, _ := rt.GetResource("app")
+	if !reflect.DeepEqual(pruned.Object["spec"], wantPruned) {
+		t.Errorf("pruned spec = %v, want %v", pruned.Object["spec"], wantPruned)
+	}
+	rendered, _ := rt.RenderResource("app")
+	if !reflect.DeepEqual(rendered.Object["spec"], wantPruned) {
+		t.Errorf("rendered spec = %v, want %v", rendered.Object["spec"], wantPruned)
+	}
+	// The pruning doesn't alter the template of the resource.
+	if _, ok := rt.resources["app"].Unstructured().Object["spec"].(map[string]interface{})["tier"]; !ok {
+		t.Errorf("pruning removed the field from the resource template")
+	}
+}
+
+func staticField(path, expr string) *variable.ResourceField {
+	return &variable.ResourceField{
+		FieldDescriptor: variable.FieldDescriptor{
+			Path:                 path,
+			Expressions:          []string{expr},
+			StandaloneExpression: true,
+		},
+		Kind: variable.ResourceVariableKindStatic,
+	}
+}
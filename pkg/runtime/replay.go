@@ -0,0 +1,90 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResolutionInputs are the inputs of a resolution, see RecordInputs. They can
+// be serialized, e.g attached to a bug report, and replayed with
+// ReplayResolution to reproduce the resolution. They must be deserialized
+// preserving the integers, e.g with k8s.io/apimachinery/pkg/util/json, for
+// the replay to be faithful.
+type ResolutionInputs struct {
+	// Instance is the instance object, as it was before any evaluation.
+	Instance map[string]interface{} `json:"instance"`
+	// Observed are the observed resources, keyed by resource id.
+	Observed map[string]map[string]interface{} `json:"observed,omitempty"`
+	// Ignored are the ids of the resources ignored by conditions.
+	Ignored []string `json:"ignored,omitempty"`
+	// Time is the time of the recording, used as the current time of the
+	// replay, e.g for the transition times of the conditions.
+	Time time.Time `json:"time"`
+}
+
+// RecordInputs captures the inputs of the resolution of the runtime: the
+// instance, the observed resources and the resources ignored by conditions.
+// The graph itself and the options of the runtime aren't part of the inputs,
+// the replaying runtime is expected to be built from the same graph and
+// options.
+func (rt *ResourceGraphDefinitionRuntime) RecordInputs() *ResolutionInputs {
+	inputs := &ResolutionInputs{
+		Instance: deepCopyValue(rt.instanceTemplate).(map[string]interface{}),
+		Observed: make(map[string]map[string]interface{}, len(rt.resolvedResources)),
+		Time:     rt.currentTime(),
+	}
+	for id, observed := range rt.resolvedResources {
+		inputs.Observed[id] = deepCopyValue(observed.Object).(map[string]interface{})
+	}
+	for _, id := range rt.topologicalOrder {
+		if rt.ignoredByConditionsResources[id] {
+			inputs.Ignored = append(inputs.Ignored, id)
+		}
+	}
+	return inputs
+}
+
+// ReplayResolution returns a new runtime built from the graph and options of
+// the runtime, fed with the recorded inputs (see RecordInputs), and
+// synchronized until no more expressions can be resolved. The replayed
+// runtime reproduces the resolution of the recorded one.
+func (rt *ResourceGraphDefinitionRuntime) ReplayResolution(inputs *ResolutionInputs) (*ResourceGraphDefinitionRuntime, error) {
+	replay, err := rt.newWithInstance(inputs.Instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the replay runtime: %w", err)
+	}
+	if !inputs.Time.IsZero() {
+		replay.now = func() time.Time { return inputs.Time }
+	}
+	for id, observed := range inputs.Observed {
+		if _, ok := replay.resources[id]; !ok {
+			return nil, fmt.Errorf("observed resource: %w: %s", ErrUnknownResource, id)
+		}
+		replay.SetResource(id, &unstructured.Unstructured{Object: deepCopyValue(observed).(map[string]interface{})})
+	}
+	for _, id := range inputs.Ignored {
+		if _, ok := replay.resources[id]; !ok {
+			return nil, fmt.Errorf("ignored resource: %w: %s", ErrUnknownResource, id)
+		}
+		replay.IgnoreResource(id)
+	}
+	if err := replay.synchronizeUntilStable(); err != nil {
+		return nil, fmt.Errorf("failed to replay the resolution: %w", err)
+	}
+	return replay, nil
+}
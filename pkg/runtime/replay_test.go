@@ -0,0 +1,143 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ReplayResolution(t *testing.T) {
+	newRuntime := func(replicas int64) *ResourceGraphDefinitionRuntime {
+		resources := map[string]Resource{
+			"deployment": newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "app"},
+					"spec":     map[string]interface{}{"replicas": "${schema.spec.replicas}"},
+				}),
+				withVariables([]*variable.ResourceField{
+					staticField("spec.replicas", "schema.spec.replicas"),
+				}),
+			),
+			"service": newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{"selector": "${deployment.metadata.uid}"},
+				}),
+				withDependencies([]string{"deployment"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.selector",
+							Expressions:          []string{"deployment.metadata.uid"},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"deployment"},
+					},
+				}),
+			),
+		}
+		instance := newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": replicas},
+			}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "status.selector",
+						Expressions:          []string{"service.spec.selector"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"service"},
+				},
+			}),
+		)
+		rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment", "service"})
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		return rt
+	}
+
+	// The recorded resolution, e.g in production.
+	recorded := newRuntime(3)
+	recorded.SetResource("deployment", &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "app", "uid": "1234"},
+	}})
+	if err := recorded.synchronizeUntilStable(); err != nil {
+		t.Fatalf("synchronizeUntilStable() error = %v", err)
+	}
+	service, _ := recorded.GetResource("service")
+	recorded.SetResource("service", service.DeepCopy())
+	if err := recorded.synchronizeUntilStable(); err != nil {
+		t.Fatalf("synchronizeUntilStable() error = %v", err)
+	}
+
+	// The inputs survive a serialization round trip.
+	data, err := json.Marshal(recorded.RecordInputs())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	inputs := &ResolutionInputs{}
+	if err := utiljson.Unmarshal(data, inputs); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	// Replayed locally, from the same graph but another instance.
+	replayed, err := newRuntime(1).ReplayResolution(inputs)
+	if err != nil {
+		t.Fatalf("ReplayResolution() error = %v", err)
+	}
+	for _, id := range []string{"deployment", "service"} {
+		want, wantState := recorded.RenderResource(id)
+		got, gotState := replayed.RenderResource(id)
+		if gotState != wantState || !reflect.DeepEqual(got, want) {
+			t.Errorf("replayed %s = %v (%v), want %v (%v)", id, got, gotState, want, wantState)
+		}
+	}
+	if got, want := replayed.GetInstance().Object, recorded.GetInstance().Object; !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed instance = %v, want %v", got, want)
+	}
+	if got, _, _ := unstructured.NestedString(replayed.GetInstance().Object, "status", "selector"); got != "1234" {
+		t.Errorf("replayed status.selector = %q, want %q", got, "1234")
+	}
+
+	// The instance set after the runtime was created is recorded.
+	updated := newRuntime(1)
+	updated.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(5)},
+	}})
+	replayed, err = newRuntime(1).ReplayResolution(updated.RecordInputs())
+	if err != nil {
+		t.Fatalf("ReplayResolution() error = %v", err)
+	}
+	deployment, _ := replayed.GetResource("deployment")
+	if got, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas"); got != 5 {
+		t.Errorf("replayed replicas = %d, want 5", got)
+	}
+
+	// Inputs of another graph.
+	inputs.Observed["unknown"] = map[string]interface{}{}
+	if _, err := newRuntime(1).ReplayResolution(inputs); !errors.Is(err, ErrUnknownResource) {
+		t.Errorf("ReplayResolution() error = %v, want %v", err, ErrUnknownResource)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kro-run/kro/pkg/graph/parser"
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+const (
+	// RequeueHintProgressing is the requeue hint when the last Synchronize
+	// call made progress, more expressions are likely to resolve soon.
+	RequeueHintProgressing = 2 * time.Second
+	// RequeueHintWaitingOnDependencies is the requeue hint when resources
+	// are waiting on their dependencies to be created.
+	RequeueHintWaitingOnDependencies = 5 * time.Second
+	// RequeueHintWaitingOnReadiness is the requeue hint when resources are
+	// waiting on external readiness, e.g a database being provisioned.
+	RequeueHintWaitingOnReadiness = 15 * time.Second
+)
+
+// RequeueHint returns the suggested delay before reconciling the instance
+// again, derived from the resolution state of the runtime:
+//
+//   - zero when the instance is fully reconciled, a.k.a every expression is
+//     resolved and every resource is resolved (or ignored) and ready.
+//   - RequeueHintProgressing when the last Synchronize call resolved new
+//     expressions.
+//   - RequeueHintWaitingOnReadiness when resources failed their last
+//     readiness checks.
+//   - RequeueHintWaitingOnDependencies otherwise.
+func (rt *ResourceGraphDefinitionRuntime) RequeueHint() time.Duration {
+	waitingOnReadiness := rt.isWaitingOnReadiness()
+	switch {
+	case rt.allValuesAreResolved() && rt.allResourcesResolvedOrIgnored() && !waitingOnReadiness:
+		return 0
+	case rt.lastSynchronizeProgressed:
+		return RequeueHintProgressing
+	case waitingOnReadiness:
+		return RequeueHintWaitingOnReadiness
+	default:
+		return RequeueHintWaitingOnDependencies
+	}
+}
+
+// ComputeRequeueInterval returns the delay before reconciling the instance
+// again. If the instance declares a requeue interval (see
+// WithRequeueInterval) it's evaluated against the current state, including
+// the status of the instance, and parsed as a duration, e.g "30s". RequeueHint
+// is returned when no interval is declared or it evaluates to an empty string.
+func (rt *ResourceGraphDefinitionRuntime) ComputeRequeueInterval() (time.Duration, error) {
+	interval := rt.requeueInterval
+	expressions, err := parser.ExtractExpressions(interval)
+	if err != nil {
+		return 0, fmt.Errorf("failed parsing requeue interval: %w", err)
+	}
+	if len(expressions) > 0 {
+		interval, err = rt.renderWithCurrentState(interval)
+		if err != nil {
+			return 0, fmt.Errorf("failed evaluating requeue interval: %w", err)
+		}
+	}
+
+	interval = strings.TrimSpace(interval)
+	if interval == "" {
+		return rt.RequeueHint(), nil
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid requeue interval %q: %w", interval, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid requeue interval %q: must not be negative", interval)
+	}
+	return duration, nil
+}
+
+// allValuesAreResolved checks if every expression, except the readyWhen
+// expressions, has been successfully evaluated. readyWhen expressions are
+// evaluated on demand by IsResourceReady and never stored as resolved, the
+// readiness is covered by isWaitingOnReadiness instead.
+func (rt *ResourceGraphDefinitionRuntime) allValuesAreResolved() bool {
+	for _, state := range rt.expressionsCache {
+		if state.Kind == variable.ResourceVariableKindReadyWhen {
+			continue
+		}
+		if !state.Resolved && rt.isExpressionNeeded(state.Expression) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWaitingOnReadiness returns true if a resource failed its last readiness
+// check.
+func (rt *ResourceGraphDefinitionRuntime) isWaitingOnReadiness() bool {
+	for id := range rt.readinessAttempts {
+		if rt.ReadinessAttempts(id) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvedExpressionsCount returns the number of resolved expressions.
+func (rt *ResourceGraphDefinitionRuntime) resolvedExpressionsCount() int {
+	count := 0
+	for _, state := range rt.expressionsCache {
+		if state.Resolved {
+			count++
+		}
+	}
+	return count
+}
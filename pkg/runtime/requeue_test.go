@@ -0,0 +1,128 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_RequeueHint(t *testing.T) {
+	resources := map[string]Resource{
+		"dep": newTestResource(
+			withReadyExpressions([]string{"dep.status.ready"}),
+		),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${dep.spec.value}"},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{"dep.spec.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	observeDep := func(ready bool) {
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"value": "hello"},
+			"status": map[string]interface{}{"ready": ready},
+		}})
+		if _, _, err := rt.IsResourceReady("dep"); err != nil {
+			t.Fatalf("IsResourceReady() error = %v", err)
+		}
+	}
+	synchronize := func(want time.Duration) {
+		t.Helper()
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if got := rt.RequeueHint(); got != want {
+			t.Errorf("RequeueHint() = %v, want %v", got, want)
+		}
+	}
+
+	// Nothing is observed, nothing can be resolved.
+	synchronize(RequeueHintWaitingOnDependencies)
+
+	// Observing the dependency resolves the consumer expression.
+	observeDep(false)
+	synchronize(RequeueHintProgressing)
+
+	// No more progress, the dependency isn't ready yet.
+	synchronize(RequeueHintWaitingOnReadiness)
+
+	// Everything is observed and ready.
+	observeDep(true)
+	rt.SetResource("consumer", &unstructured.Unstructured{Object: map[string]interface{}{}})
+	synchronize(0)
+}
+
+func Test_ComputeRequeueInterval(t *testing.T) {
+	const interval = `${schema.status.phase == "Provisioning" ? "30s" : schema.status.phase == "Failed" ? "5m" : ""}`
+
+	tests := []struct {
+		name     string
+		interval string
+		phase    string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "provisioning", interval: interval, phase: "Provisioning", want: 30 * time.Second},
+		{name: "failed", interval: interval, phase: "Failed", want: 5 * time.Minute},
+		{name: "empty interval falls back to the hint", interval: interval, phase: "Ready", want: 0},
+		{name: "no interval falls back to the hint", phase: "Provisioning", want: 0},
+		{name: "static interval", interval: "1m", phase: "Ready", want: time.Minute},
+		{name: "invalid interval", interval: "${schema.status.phase}", phase: "Ready", wantErr: true},
+		{name: "negative interval", interval: "-1s", phase: "Ready", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := newTestResource(withObject(map[string]interface{}{
+				"status": map[string]interface{}{"phase": tt.phase},
+			}))
+			rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{}, []string{}, WithRequeueInterval(tt.interval))
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+			if _, err := rt.Synchronize(); err != nil {
+				t.Fatalf("Synchronize() error = %v", err)
+			}
+
+			got, err := rt.ComputeRequeueInterval()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ComputeRequeueInterval() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ComputeRequeueInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
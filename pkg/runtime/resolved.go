@@ -0,0 +1,73 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// ErrUnknownResource is returned when looking up a resource id that isn't
+	// part of the graph.
+	ErrUnknownResource = errors.New("unknown resource")
+	// ErrResourceNotResolved is returned when looking up a resource of the
+	// graph that wasn't resolved (set with SetResource) yet.
+	ErrResourceNotResolved = errors.New("resource not resolved yet")
+)
+
+// MustGetResolved returns the resolved (observed) object of the resource. It
+// is meant for callers expecting the resolution to have happened, and fails
+// with ErrUnknownResource if the id isn't part of the graph, or with
+// ErrResourceNotResolved if the resource wasn't resolved yet.
+func (rt *ResourceGraphDefinitionRuntime) MustGetResolved(resourceID string) (*unstructured.Unstructured, error) {
+	if _, ok := rt.resources[resourceID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, resourceID)
+	}
+	obj, ok := rt.resolvedResources[resourceID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotResolved, resourceID)
+	}
+	return obj, nil
+}
+
+// resolveLazily evaluates the unresolved dynamic expressions of the resource
+// and of its dependencies, which need to be resolved for the resource to be
+// processed, and renders the resource. See WithLazyResolution.
+func (rt *ResourceGraphDefinitionRuntime) resolveLazily(id string) {
+	resource, ok := rt.resources[id]
+	if !ok {
+		return
+	}
+	subgraph := make(map[*expressionEvaluationState]bool)
+	for _, rid := range append([]string{id}, resource.GetDependencies()...) {
+		for _, variable := range rt.runtimeVariables[rid] {
+			subgraph[variable] = true
+		}
+	}
+	variables := slices.DeleteFunc(rt.dynamicEvaluationOrder(), func(variable *expressionEvaluationState) bool {
+		return !subgraph[variable]
+	})
+	if len(variables) > 0 {
+		// Errors, e.g incomplete data, leave the expressions unresolved, they
+		// are returned by Synchronize.
+		_ = rt.evaluateDynamicVariablesOf(variables)
+	}
+	if rt.canProcessResource(id) {
+		_ = rt.evaluateResourceExpressions(id)
+	}
+}
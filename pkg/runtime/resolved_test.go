@@ -0,0 +1,119 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_MustGetResolved(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "deployment"},
+	}}
+	rt := &ResourceGraphDefinitionRuntime{
+		resources: map[string]Resource{
+			"deployment": newTestResource(),
+			"service":    newTestResource(),
+		},
+		resolvedResources: map[string]*unstructured.Unstructured{
+			"deployment": deployment,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		resourceID string
+		want       *unstructured.Unstructured
+		wantErr    error
+	}{
+		{
+			name:       "resolved resource",
+			resourceID: "deployment",
+			want:       deployment,
+		},
+		{
+			name:       "known but not yet resolved",
+			resourceID: "service",
+			wantErr:    ErrResourceNotResolved,
+		},
+		{
+			name:       "unknown resource",
+			resourceID: "configmap",
+			wantErr:    ErrUnknownResource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rt.MustGetResolved(tt.resourceID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MustGetResolved(%q) error = %v, want %v", tt.resourceID, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MustGetResolved(%q) = %v, want %v", tt.resourceID, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LazyResolution(t *testing.T) {
+	const (
+		exprA = `dep.spec.value + "-a"`
+		exprB = `dep.spec.value + "-b"`
+	)
+
+	t.Run("eager", func(t *testing.T) {
+		rt := newConsumersRuntime(t, exprA, exprB)
+		if _, state := rt.GetResource("consumerA"); state != ResourceStateWaitingOnDependencies {
+			t.Errorf("GetResource() state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		rt := newConsumersRuntime(t, exprA, exprB)
+		WithLazyResolution()(rt)
+
+		obj, state := rt.GetResource("consumerA")
+		if state != ResourceStateResolved {
+			t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+		}
+		if got := obj.Object["data"].(map[string]interface{})["value"]; got != "hello-a" {
+			t.Errorf("GetResource() data.value = %v, want hello-a", got)
+		}
+		// Only the subgraph of the requested resource is evaluated.
+		if resolved, _, _ := rt.ExpressionState(exprB); resolved {
+			t.Errorf("ExpressionState(%q) resolved, want unresolved", exprB)
+		}
+
+		// Synchronize resolves the rest of the graph.
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		if resolved, value, _ := rt.ExpressionState(exprB); !resolved || value != "hello-b" {
+			t.Errorf("ExpressionState(%q) = (%v, %v), want (true, hello-b)", exprB, resolved, value)
+		}
+	})
+
+	t.Run("unobserved dependency", func(t *testing.T) {
+		rt := newConsumersRuntime(t, exprA)
+		WithLazyResolution()(rt)
+		rt.SetResource("dep", nil)
+		if _, state := rt.GetResource("consumerA"); state != ResourceStateWaitingOnDependencies {
+			t.Errorf("GetResource() state = %v, want %v", state, ResourceStateWaitingOnDependencies)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/types"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// resolvePatchField applies the patch produced by the field expression to the
+// observed value of the field, and sets the patched value in the resource.
+// This allows surgical updates of objects whose content is managed elsewhere.
+func (r *Resolver) resolvePatchField(field variable.FieldDescriptor, result ResolutionResult) ResolutionResult {
+	if !field.StandaloneExpression {
+		result.Error = fmt.Errorf("patch field %s must be a standalone expression", field.Path)
+		return result
+	}
+	patch, ok := r.data[strings.Trim(field.Expressions[0], "${}")]
+	if !ok {
+		result.Error = fmt.Errorf("no data provided for expression: %s", field.Expressions[0])
+		return result
+	}
+
+	var base interface{} = map[string]interface{}{}
+	if r.observed != nil {
+		if observed, err := valueAtPath(r.observed, field.Path); err == nil {
+			base = observed
+		}
+	}
+
+	patched, err := applyPatch(field.PatchType, base, explicitNullsToNil(patch))
+	if err != nil {
+		result.Error = fmt.Errorf("error applying patch at path %s: %v", field.Path, err)
+		return result
+	}
+	if err := r.setValueAtPath(field.Path, patched); err != nil {
+		result.Error = fmt.Errorf("error setting value: %v", err)
+		return result
+	}
+	result.Resolved = true
+	result.Replaced = patched
+	return result
+}
+
+// applyPatch applies the patch to a copy of the base value, and returns the
+// patched value.
+func applyPatch(patchType types.PatchType, base, patch interface{}) (interface{}, error) {
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal base value: %w", err)
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	var patchedJSON []byte
+	switch patchType {
+	case types.JSONPatchType:
+		operations, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return nil, fmt.Errorf("invalid json patch: %w", err)
+		}
+		patchedJSON, err = operations.Apply(baseJSON)
+		if err != nil {
+			return nil, err
+		}
+	case types.MergePatchType:
+		patchedJSON, err = jsonpatch.MergePatch(baseJSON, patchJSON)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type: %s", patchType)
+	}
+
+	// Unlike encoding/json, utiljson decodes the integers as int64, like
+	// the rest of the unstructured objects.
+	var patched interface{}
+	if err := utiljson.Unmarshal(patchedJSON, &patched); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal patched value: %w", err)
+	}
+	return patched, nil
+}
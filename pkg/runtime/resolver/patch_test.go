@@ -0,0 +1,120 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func TestResolvePatchField(t *testing.T) {
+	observed := map[string]interface{}{
+		"data": map[string]interface{}{
+			"managed":   "elsewhere",
+			"replicas":  int64(1),
+			"untouched": true,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		observed  map[string]interface{}
+		patchType types.PatchType
+		patch     interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "json patch",
+			observed:  observed,
+			patchType: types.JSONPatchType,
+			patch: []interface{}{
+				map[string]interface{}{"op": "replace", "path": "/replicas", "value": int64(3)},
+				map[string]interface{}{"op": "remove", "path": "/untouched"},
+			},
+			want: map[string]interface{}{
+				"managed":  "elsewhere",
+				"replicas": int64(3),
+			},
+		},
+		{
+			name:      "merge patch",
+			observed:  observed,
+			patchType: types.MergePatchType,
+			patch: map[string]interface{}{
+				"replicas":  int64(3),
+				"untouched": nil,
+				"added":     "by kro",
+			},
+			want: map[string]interface{}{
+				"managed":  "elsewhere",
+				"replicas": int64(3),
+				"added":    "by kro",
+			},
+		},
+		{
+			name:      "merge patch without observed state",
+			patchType: types.MergePatchType,
+			patch:     map[string]interface{}{"replicas": int64(3)},
+			want:      map[string]interface{}{"replicas": int64(3)},
+		},
+		{
+			name:      "invalid json patch",
+			observed:  observed,
+			patchType: types.JSONPatchType,
+			patch: []interface{}{
+				map[string]interface{}{"op": "remove", "path": "/missing"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "unsupported patch type",
+			observed:  observed,
+			patchType: types.StrategicMergePatchType,
+			patch:     map[string]interface{}{},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := map[string]interface{}{
+				"data": "${patch}",
+			}
+			r := NewResolver(resource, map[string]interface{}{"patch": tt.patch}).WithObserved(tt.observed)
+
+			summary := r.Resolve([]variable.FieldDescriptor{
+				{
+					Path:                 "data",
+					Expressions:          []string{"patch"},
+					StandaloneExpression: true,
+					PatchType:            tt.patchType,
+				},
+			})
+			if tt.wantErr {
+				assert.NotEmpty(t, summary.Errors)
+				return
+			}
+			assert.Empty(t, summary.Errors)
+			assert.Equal(t, tt.want, resource["data"])
+			// the observed state is never modified.
+			assert.Equal(t, "elsewhere", observed["data"].(map[string]interface{})["managed"])
+			assert.Equal(t, true, observed["data"].(map[string]interface{})["untouched"])
+		})
+	}
+}
@@ -14,13 +14,19 @@
 package resolver
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	krocel "github.com/kro-run/kro/pkg/cel"
 	"github.com/kro-run/kro/pkg/graph/fieldpath"
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
 
+// ErrRequiredFieldNull is returned, in strict null handling mode, when the
+// expression of a required field resolves to null.
+var ErrRequiredFieldNull = errors.New("required field resolved to null")
+
 // ResolutionResult represents the result of resolving a single expression.
 type ResolutionResult struct {
 	Path     string
@@ -47,6 +53,11 @@ type Resolver struct {
 	// responsible for providing this only with available data aka CEL Expressions
 	// we've been able to resolve.
 	data map[string]interface{}
+	// The observed state of the resource, if any. Patch fields are applied
+	// to their observed value.
+	observed map[string]interface{}
+	// strictNulls enables the strict null handling, see WithStrictNulls.
+	strictNulls bool
 }
 
 // NewResolver creates a new Resolver instance.
@@ -57,6 +68,25 @@ func NewResolver(resource map[string]interface{}, data map[string]interface{}) *
 	}
 }
 
+// WithObserved sets the observed state of the resource, the base on which
+// the patch fields are applied. Without it, patches are applied to an empty
+// object.
+func (r *Resolver) WithObserved(observed map[string]interface{}) *Resolver {
+	r.observed = observed
+	return r
+}
+
+// WithStrictNulls enables the strict null handling of the standalone
+// expressions: a required field (see variable.FieldDescriptor.Required)
+// resolving to null is an error, and an optional field resolving to null is
+// omitted from the resource instead of being set to null. Omitted fields are
+// set again once their expression resolves to a value. Expressions resolving
+// to krocel.ExplicitNull still set their field to null.
+func (r *Resolver) WithStrictNulls() *Resolver {
+	r.strictNulls = true
+	return r
+}
+
 // Resolve processes all the given ExpressionFields and resolves their CEL expressions.
 // It returns a ResolutionSummary containing information about the resolution process.
 func (r *Resolver) Resolve(expressions []variable.FieldDescriptor) ResolutionSummary {
@@ -81,7 +111,18 @@ func (r *Resolver) Resolve(expressions []variable.FieldDescriptor) ResolutionSum
 
 // UpsertValueAtPath sets a value in the resource using the fieldpath parser.
 func (r *Resolver) UpsertValueAtPath(path string, value interface{}) error {
-	return r.setValueAtPath(path, value)
+	return r.setValueAtPath(path, explicitNullsToNil(value))
+}
+
+// RemoveValueAtPath removes the field at the given path from the resource,
+// list items are set to null instead. Missing fields are ignored.
+func (r *Resolver) RemoveValueAtPath(path string) error {
+	return r.removeValueAtPath(path)
+}
+
+// ValueAtPath returns the value of the resource at the given path.
+func (r *Resolver) ValueAtPath(path string) (interface{}, error) {
+	return r.getValueFromPath(path)
 }
 
 // resolveField handles the resolution of a single ExpressionField (one field) in
@@ -94,19 +135,30 @@ func (r *Resolver) resolveField(field variable.FieldDescriptor) ResolutionResult
 	}
 
 	value, err := r.getValueFromPath(field.Path)
-	if err != nil {
+	// In strict null handling mode, the field may have been omitted by a
+	// previous resolution.
+	omittable := r.strictNulls && field.StandaloneExpression && field.PatchType == ""
+	if err != nil && !omittable {
 		// Not sure if these kind of errors should be fatal, these paths are produced
 		// by the parser, so they should be valid. Maybe we should log them instead....
 		result.Error = fmt.Errorf("error getting value: %v", err)
 		return result
 	}
 
+	if field.PatchType != "" {
+		return r.resolvePatchField(field, result)
+	}
+
 	if field.StandaloneExpression {
 		resolvedValue, ok := r.data[strings.Trim(field.Expressions[0], "${}")]
 		if !ok {
 			result.Error = fmt.Errorf("no data provided for expression: %s", field.Expressions[0])
 			return result
 		}
+		if resolvedValue == nil && r.strictNulls {
+			return r.resolveNullField(field, result)
+		}
+		resolvedValue = explicitNullsToNil(resolvedValue)
 		err = r.setValueAtPath(field.Path, resolvedValue)
 		if err != nil {
 			result.Error = fmt.Errorf("error setting value: %v", err)
@@ -144,18 +196,101 @@ func (r *Resolver) resolveField(field variable.FieldDescriptor) ResolutionResult
 	return result
 }
 
+// resolveNullField handles a standalone field resolving to null in strict null
+// handling mode: required fields are an error, optional fields are omitted.
+func (r *Resolver) resolveNullField(field variable.FieldDescriptor, result ResolutionResult) ResolutionResult {
+	if field.Required {
+		result.Error = fmt.Errorf("%w: %s", ErrRequiredFieldNull, field.Path)
+		return result
+	}
+	if err := r.removeValueAtPath(field.Path); err != nil {
+		result.Error = fmt.Errorf("error omitting value: %v", err)
+		return result
+	}
+	result.Resolved = true
+	return result
+}
+
+// explicitNullsToNil replaces the explicit nulls (see krocel.ExplicitNull) of
+// the value, recursively, with nil: they're written as JSON nulls. Maps and
+// lists are copied, the value may be shared with other resources.
+func explicitNullsToNil(value interface{}) interface{} {
+	switch v := value.(type) {
+	case krocel.ExplicitNullValue:
+		return nil
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = explicitNullsToNil(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = explicitNullsToNil(item)
+		}
+		return copied
+	}
+	return value
+}
+
+// removeValueAtPath removes the field at the given path from the resource.
+// List items can't be removed without shifting the other items, they are set
+// to null instead. Missing fields are ignored.
+func (r *Resolver) removeValueAtPath(path string) error {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid path '%s': %v", path, err)
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	current := interface{}(r.resource)
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		if segment.Index >= 0 {
+			array, ok := current.([]interface{})
+			if !ok || segment.Index >= len(array) {
+				return nil
+			}
+			if last {
+				array[segment.Index] = nil
+				return nil
+			}
+			current = array[segment.Index]
+			continue
+		}
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if last {
+			delete(currentMap, segment.Name)
+			return nil
+		}
+		current = currentMap[segment.Name]
+	}
+	return nil
+}
+
 // getValueFromPath retrieves a value from the resource using a dot separated path.
 // NOTE(a-hilaly): this is very similar to the `setValueAtPath` function maybe
 // we can refactor something here.
 // getValueFromPath retrieves a value from the resource using a dot-separated path.
 func (r *Resolver) getValueFromPath(path string) (interface{}, error) {
+	return valueAtPath(r.resource, path)
+}
+
+// valueAtPath retrieves a value from the given object using a dot-separated path.
+func valueAtPath(obj map[string]interface{}, path string) (interface{}, error) {
 	path = strings.TrimPrefix(path, ".") // Remove leading dot if present
 	segments, err := fieldpath.Parse(path)
 	if err != nil {
 		return nil, fmt.Errorf("invalid path '%s': %v", path, err)
 	}
 
-	current := interface{}(r.resource)
+	current := interface{}(obj)
 
 	for _, segment := range segments {
 		if segment.Index >= 0 {
@@ -215,15 +350,18 @@ func (r *Resolver) setValueAtPath(path string, value interface{}) error {
 			}
 			current = newCurrent
 
+			array, ok := current.([]interface{})
+			if !ok {
+				return fmt.Errorf("expected array at path segment: %v", segment)
+			}
 			if i == len(segments)-1 {
-				array := current.([]interface{})
 				array[segment.Index] = value
 				return nil
 			}
 			parent = current
 			parentIndex = segment.Index
 
-			current = getOrCreateNext(current.([]interface{}), segment.Index, segments[i+1].Index >= 0)
+			current = getOrCreateNext(array, segment.Index, segments[i+1].Index >= 0)
 		} else {
 			currentMap, ok := current.(map[string]interface{})
 			if !ok {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	krocel "github.com/kro-run/kro/pkg/cel"
 	"github.com/kro-run/kro/pkg/graph/variable"
 )
 
@@ -604,6 +605,99 @@ func TestResolveField(t *testing.T) {
 	}
 }
 
+func TestResolveStrictNulls(t *testing.T) {
+	newResource := func() map[string]interface{} {
+		return map[string]interface{}{
+			"spec": map[string]interface{}{
+				"name":  "${name}",
+				"ports": []interface{}{"${port}", int64(443)},
+			},
+		}
+	}
+	standalone := func(path, expr string, required bool) variable.FieldDescriptor {
+		return variable.FieldDescriptor{
+			Path:                 path,
+			Expressions:          []string{expr},
+			StandaloneExpression: true,
+			Required:             required,
+		}
+	}
+
+	t.Run("required field resolving to null", func(t *testing.T) {
+		r := NewResolver(newResource(), map[string]interface{}{"name": nil}).WithStrictNulls()
+		got := r.resolveField(standalone("spec.name", "name", true))
+		assert.ErrorIs(t, got.Error, ErrRequiredFieldNull)
+		assert.False(t, got.Resolved)
+	})
+
+	t.Run("optional field resolving to null is omitted", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"name": nil, "port": nil}).WithStrictNulls()
+		summary := r.Resolve([]variable.FieldDescriptor{
+			standalone("spec.name", "name", false),
+			standalone("spec.ports[0]", "port", false),
+		})
+		assert.Empty(t, summary.Errors)
+		assert.Equal(t, 2, summary.ResolvedExpressions)
+		assert.Equal(t, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"ports": []interface{}{nil, int64(443)},
+			},
+		}, resource)
+
+		// The omitted field is set again once it resolves to a value.
+		r = NewResolver(resource, map[string]interface{}{"name": "app"}).WithStrictNulls()
+		got := r.resolveField(standalone("spec.name", "name", false))
+		assert.NoError(t, got.Error)
+		assert.Equal(t, "app", resource["spec"].(map[string]interface{})["name"])
+	})
+
+	t.Run("explicit nulls are written", func(t *testing.T) {
+		resource := newResource()
+		resource["spec"].(map[string]interface{})["selector"] = "${selector}"
+		r := NewResolver(resource, map[string]interface{}{
+			"name":     nil,
+			"port":     int64(80),
+			"selector": krocel.ExplicitNull,
+		}).WithStrictNulls()
+		summary := r.Resolve([]variable.FieldDescriptor{
+			standalone("spec.name", "name", false),
+			standalone("spec.ports[0]", "port", false),
+			standalone("spec.selector", "selector", true),
+		})
+		assert.Empty(t, summary.Errors)
+		assert.Equal(t, 3, summary.ResolvedExpressions)
+		// The null field is omitted, the explicit null one is set to null.
+		assert.Equal(t, map[string]interface{}{
+			"spec": map[string]interface{}{
+				"ports":    []interface{}{int64(80), int64(443)},
+				"selector": nil,
+			},
+		}, resource)
+	})
+
+	t.Run("nested explicit nulls are written", func(t *testing.T) {
+		resource := newResource()
+		value := map[string]interface{}{"matchLabels": krocel.ExplicitNull}
+		r := NewResolver(resource, map[string]interface{}{"name": value})
+		got := r.resolveField(standalone("spec.name", "name", true))
+		assert.NoError(t, got.Error)
+		assert.Equal(t, map[string]interface{}{"matchLabels": nil}, resource["spec"].(map[string]interface{})["name"])
+		// The resolved value itself is left untouched.
+		assert.Equal(t, krocel.ExplicitNull, value["matchLabels"])
+	})
+
+	t.Run("nulls are written without strict handling", func(t *testing.T) {
+		resource := newResource()
+		r := NewResolver(resource, map[string]interface{}{"name": nil})
+		got := r.resolveField(standalone("spec.name", "name", true))
+		assert.NoError(t, got.Error)
+		value, found := resource["spec"].(map[string]interface{})["name"]
+		assert.True(t, found)
+		assert.Nil(t, value)
+	})
+}
+
 func TestResolveDynamicArrayIndexes(t *testing.T) {
 	resource := map[string]interface{}{
 		"spec": map[string]interface{}{
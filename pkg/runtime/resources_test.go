@@ -0,0 +1,128 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_CountWhereResources(t *testing.T) {
+	const expr = "countWhere(resources, r, r.kind == 'Pod' && r.status.phase == 'Running')"
+	instance := newTestResource(
+		withObject(map[string]interface{}{}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.runningPods",
+					Expressions:          []string{expr},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"podA", "podB", "service"},
+			},
+		}),
+	)
+	resources := map[string]Resource{
+		"podA":    newTestResource(),
+		"podB":    newTestResource(),
+		"service": newTestResource(),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"podA", "podB", "service"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	newObject := func(kind, phase string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":   kind,
+			"status": map[string]interface{}{"phase": phase},
+		}}
+	}
+	rt.SetResource("podA", newObject("Pod", "Running"))
+	rt.SetResource("podB", newObject("Pod", "Pending"))
+	rt.SetResource("service", newObject("Service", "Running"))
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	if got := rt.expressionsCache[expr].ResolvedValue; got != int64(1) {
+		t.Errorf("expression %q = %v, want 1", expr, got)
+	}
+
+	rt.SetResource("podB", newObject("Pod", "Running"))
+	rt.expressionsCache[expr].Resolved = false
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	status := rt.GetInstance().Object["status"].(map[string]interface{})
+	if got := status["runningPods"]; got != int64(2) {
+		t.Errorf("status.runningPods = %v, want 2", got)
+	}
+}
+
+func Test_AggregateResourcesField(t *testing.T) {
+	const expr = `resources.filter(r, r.kind == "Pod").map(r, r.status.podIP)`
+	instance := newTestResource(
+		withObject(map[string]interface{}{}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.podIPs",
+					Expressions:          []string{expr},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"podA", "podB", "podC", "service"},
+			},
+		}),
+	)
+	resources := map[string]Resource{
+		"podA":    newTestResource(),
+		"podB":    newTestResource(),
+		"podC":    newTestResource(),
+		"service": newTestResource(),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"podA", "podB", "podC", "service"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	newPod := func(ip string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":   "Pod",
+			"status": map[string]interface{}{"podIP": ip},
+		}}
+	}
+	rt.SetResource("podC", newPod("10.0.0.3"))
+	rt.SetResource("podA", newPod("10.0.0.1"))
+	rt.SetResource("podB", newPod("10.0.0.2"))
+	rt.SetResource("service", &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":   "Service",
+		"status": map[string]interface{}{},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	// The resources are listed by resource id.
+	got, _, _ := unstructured.NestedStringSlice(rt.GetInstance().Object, "status", "podIPs")
+	want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("status.podIPs = %v, want %v", got, want)
+	}
+}
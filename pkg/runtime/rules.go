@@ -0,0 +1,90 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrValidationFailed is returned by Synchronize when validation rules of the
+// instance fail, see WithValidationRules.
+var ErrValidationFailed = errors.New("validation failed")
+
+// ValidationRule is a semantic invariant of the instance, e.g its minimum
+// being lower than its maximum.
+type ValidationRule struct {
+	// Expression is the expression that must evaluate to true, e.g
+	// "schema.spec.min <= schema.spec.max". Only the instance and the context
+	// variables can be referenced.
+	Expression string
+	// Message is reported when the expression evaluates to false. It can
+	// embed expressions, e.g "min (${schema.spec.min}) must be <= max". The
+	// expression is reported if the message is empty.
+	Message string
+}
+
+// ValidateRules evaluates the validation rules of the instance (see
+// WithValidationRules), and returns the messages of the failing ones, in
+// order. It fails if a rule can't be evaluated, or doesn't evaluate to a
+// boolean.
+func (rt *ResourceGraphDefinitionRuntime) ValidateRules() ([]string, error) {
+	if len(rt.validationRules) == 0 {
+		return nil, nil
+	}
+	env, err := rt.staticEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	context := rt.newEvalContext()
+
+	var messages []string
+	for _, rule := range rt.validationRules {
+		value, err := rt.evaluate(env, context, rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("failed evaluating validation rule %s: %w", rule.Expression, err)
+		}
+		valid, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("validation rule %s returned %T, expected bool", rule.Expression, value)
+		}
+		if valid {
+			continue
+		}
+		if rule.Message == "" {
+			messages = append(messages, fmt.Sprintf("validation rule %s failed", rule.Expression))
+			continue
+		}
+		message, err := rt.renderMessage(env, context, rule.Message)
+		if err != nil {
+			return nil, fmt.Errorf("failed rendering message of validation rule %s: %w", rule.Expression, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// checkValidationRules returns an error wrapping ErrValidationFailed if
+// validation rules of the instance fail.
+func (rt *ResourceGraphDefinitionRuntime) checkValidationRules() error {
+	messages, err := rt.ValidateRules()
+	if err != nil {
+		return err
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%w: %s", ErrValidationFailed, strings.Join(messages, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ValidationRules(t *testing.T) {
+	rules := []ValidationRule{
+		{
+			Expression: "schema.spec.min <= schema.spec.max",
+			Message:    "min (${schema.spec.min}) must be lower than max (${schema.spec.max})",
+		},
+		{Expression: "schema.spec.name.size() <= 8"},
+	}
+
+	tests := []struct {
+		name         string
+		spec         map[string]interface{}
+		wantMessages []string
+		wantErr      bool
+	}{
+		{
+			name: "passing rules",
+			spec: map[string]interface{}{"min": int64(1), "max": int64(3), "name": "web"},
+		},
+		{
+			name:         "failing rule with a message",
+			spec:         map[string]interface{}{"min": int64(5), "max": int64(3), "name": "web"},
+			wantMessages: []string{"min (5) must be lower than max (3)"},
+		},
+		{
+			name: "failing rules",
+			spec: map[string]interface{}{"min": int64(5), "max": int64(3), "name": "frontend-web"},
+			wantMessages: []string{
+				"min (5) must be lower than max (3)",
+				"validation rule schema.spec.name.size() <= 8 failed",
+			},
+		},
+		{
+			name:    "rule that can't be evaluated",
+			spec:    map[string]interface{}{"name": "web"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := newTestResource(withObject(map[string]interface{}{"spec": tt.spec}))
+			rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{}, []string{},
+				WithValidationRules(rules...))
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+
+			messages, err := rt.ValidateRules()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(messages, tt.wantMessages) {
+				t.Errorf("ValidateRules() = %q, want %q", messages, tt.wantMessages)
+			}
+
+			_, err = rt.Synchronize()
+			switch {
+			case tt.wantErr:
+				if err == nil {
+					t.Errorf("Synchronize() error = nil, want an error")
+				}
+			case len(tt.wantMessages) > 0:
+				if !errors.Is(err, ErrValidationFailed) || !strings.Contains(err.Error(), tt.wantMessages[0]) {
+					t.Errorf("Synchronize() error = %v, want %v with %q", err, ErrValidationFailed, tt.wantMessages[0])
+				}
+			default:
+				if err != nil {
+					t.Errorf("Synchronize() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -14,16 +14,29 @@
 package runtime
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	goruntime "runtime"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/google/cel-go/cel"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	krocel "github.com/kro-run/kro/pkg/cel"
+	"github.com/kro-run/kro/pkg/cel/ast"
+	"github.com/kro-run/kro/pkg/graph/parser"
 	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/metadata"
 	"github.com/kro-run/kro/pkg/runtime/resolver"
 )
 
@@ -45,15 +58,38 @@ func NewResourceGraphDefinitionRuntime(
 	instance Resource,
 	resources map[string]Resource,
 	topologicalOrder []string,
+	opts ...Option,
 ) (*ResourceGraphDefinitionRuntime, error) {
 	r := &ResourceGraphDefinitionRuntime{
 		instance:                     instance,
 		resources:                    resources,
-		topologicalOrder:             topologicalOrder,
+		topologicalOrder:             sortTopologicalOrder(topologicalOrder, resources),
 		resolvedResources:            make(map[string]*unstructured.Unstructured),
 		runtimeVariables:             make(map[string][]*expressionEvaluationState),
 		expressionsCache:             make(map[string]*expressionEvaluationState),
+		expressionConsumers:          make(map[string][]string),
 		ignoredByConditionsResources: make(map[string]bool),
+		compilationErrors:            make(map[compilationErrorKey]error),
+		log:                          logr.Discard(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	// Keep the templates and the options around to clone the runtime, the
+	// templates are modified in place by the evaluations.
+	r.options = opts
+	r.instanceTemplate = deepCopyValue(instance.Unstructured().Object).(map[string]interface{})
+	r.templates = make(map[string]map[string]interface{}, len(resources))
+	for id, resource := range resources {
+		r.templates[id] = deepCopyValue(resource.Unstructured().Object).(map[string]interface{})
+	}
+	if r.kindAliasesEnabled {
+		r.buildKindAliases()
+	}
+	if r.referenceValidation {
+		if errs := unknownReferences(instance, resources, r.kindAliases); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid references: %w", errors.Join(errs...))
+		}
 	}
 	// make sure to copy the variables and the dependencies, to avoid
 	// modifying the original resource.
@@ -61,6 +97,7 @@ func NewResourceGraphDefinitionRuntime(
 		// Process the resource variables.
 		for _, variable := range resource.GetVariables() {
 			for _, expr := range variable.Expressions {
+				r.addExpressionConsumer(expr, id)
 				// If cached use the same pointer.
 				if ec, seen := r.expressionsCache[expr]; seen {
 					// NOTE(a-hilaly): This strikes me as an early optimization, but
@@ -69,10 +106,16 @@ func NewResourceGraphDefinitionRuntime(
 					r.runtimeVariables[id] = append(r.runtimeVariables[id], ec)
 					continue
 				}
+				dependencies, err := r.resolveDependencyAliases(variable.Dependencies)
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve dependencies of expression %s: %w", expr, err)
+				}
+				dependencies = r.withDerivedDependencies(expr, variable.Kind, dependencies)
 				ees := &expressionEvaluationState{
-					Expression:   expr,
-					Dependencies: variable.Dependencies,
-					Kind:         variable.Kind,
+					Expression:       expr,
+					Dependencies:     dependencies,
+					Kind:             variable.Kind,
+					RequiredFeatures: variable.RequiredFeatures,
 				}
 				r.runtimeVariables[id] = append(r.runtimeVariables[id], ees)
 				r.expressionsCache[expr] = ees
@@ -91,22 +134,31 @@ func NewResourceGraphDefinitionRuntime(
 	// Now we need to collect the instance variables.
 	for _, variable := range instance.GetVariables() {
 		for _, expr := range variable.Expressions {
+			r.addExpressionConsumer(expr, "instance")
 			if ec, seen := r.expressionsCache[expr]; seen {
 				// It is validated at the Graph level that the resource ids
 				// can't be `instance`. This is why.
 				r.runtimeVariables["instance"] = append(r.runtimeVariables["instance"], ec)
 				continue
 			}
+			dependencies, err := r.resolveDependencyAliases(variable.Dependencies)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve dependencies of expression %s: %w", expr, err)
+			}
+			dependencies = r.withDerivedDependencies(expr, variable.Kind, dependencies)
 			ees := &expressionEvaluationState{
-				Expression:   expr,
-				Dependencies: variable.Dependencies,
-				Kind:         variable.Kind,
+				Expression:       expr,
+				Dependencies:     dependencies,
+				Kind:             variable.Kind,
+				RequiredFeatures: variable.RequiredFeatures,
 			}
 			r.runtimeVariables["instance"] = append(r.runtimeVariables["instance"], ees)
 			r.expressionsCache[expr] = ees
 		}
 	}
 
+	r.reclassifyStaticExpressions()
+
 	// Evaluate the static variables, so that the caller only needs to call Synchronize
 	// whenever a new resource is added or a variable is updated.
 	err := r.evaluateStaticVariables()
@@ -166,6 +218,211 @@ type ResourceGraphDefinitionRuntime struct {
 	// ignoredByConditionsResources holds the resources whos defined conditions returned false
 	// or who's dependencies are ignored
 	ignoredByConditionsResources map[string]bool
+
+	// compilationErrors caches the errors of expressions that failed to
+	// compile, keyed by environment and expression.
+	compilationErrors map[compilationErrorKey]error
+
+	// environments caches the CEL environments built on demand, see
+	// cachedEnvironment. They are guarded by environmentsLock.
+	environments     map[environmentKey]*cel.Env
+	environmentsLock sync.Mutex
+
+	// expressionConsumers maps every resource and instance variables
+	// expression to the ids of the resources using it ("instance" for the
+	// instance). It's used to lazily skip the expressions only used by
+	// ignored resources.
+	expressionConsumers map[string][]string
+
+	// evaluators are the alternative expression evaluators, keyed by the
+	// prefix of the expressions they evaluate. evaluatorsLock serializes
+	// their calls, the static expressions being evaluated concurrently.
+	evaluators     map[string]Evaluator
+	evaluatorsLock sync.Mutex
+
+	// injectOwnerReferences indicates whether the rendered resources should
+	// be stamped with an ownerReference pointing to the instance.
+	injectOwnerReferences bool
+
+	// staticEvaluationWorkers bounds the number of goroutines evaluating
+	// the static expressions at construction. Defaults to GOMAXPROCS.
+	staticEvaluationWorkers int
+
+	// kindAliasesEnabled indicates whether the resources can be referenced
+	// by their kind, when it's unique in the graph.
+	kindAliasesEnabled bool
+
+	// kindAliases maps the unique kinds of the graph to the id of the
+	// resource having them, and ambiguousKinds maps the kinds shared by
+	// multiple resources to their ids.
+	kindAliases    map[string]string
+	ambiguousKinds map[string][]string
+
+	// onExpressionFailed is called once for every expression reaching a
+	// terminal error state.
+	onExpressionFailed func(expression string, err error)
+
+	// incompleteDataThreshold is the number of consecutive incomplete data
+	// evaluations after which an expression is considered failed. Zero means
+	// that incomplete data is never terminal.
+	incompleteDataThreshold int
+
+	// resourceGroupName and resourceGroupVersion identify the resource graph
+	// definition that produced the instance.
+	resourceGroupName    string
+	resourceGroupVersion string
+
+	// readinessAttempts tracks the failed readiness checks of the resources.
+	readinessAttempts map[string]*readinessAttempts
+
+	// typedKinds maps the kinds registered with WithTypedResource to their
+	// Go struct type, and typedObjects caches the typed conversions of the
+	// observed resources, keyed by resource id.
+	typedKinds   map[schema.GroupVersionKind]reflect.Type
+	typedObjects map[string]interface{}
+
+	// defaulters maps the kinds registered with WithDefaulter to their
+	// defaulting function, and defaultedObjects caches the defaulted copies
+	// of the observed resources, keyed by resource id.
+	defaulters       map[schema.GroupVersionKind]Defaulter
+	defaultedObjects map[string]map[string]interface{}
+
+	// featureFlags are the enabled feature flags, keyed by flag name.
+	featureFlags map[string]bool
+
+	// tracer creates the spans of the Synchronize calls and expression
+	// evaluations, nil when tracing is disabled. traceContext holds the
+	// span of the ongoing Synchronize call.
+	tracer       trace.Tracer
+	traceContext context.Context
+
+	// lastSynchronizeProgressed is true if the last Synchronize call
+	// resolved new expressions.
+	lastSynchronizeProgressed bool
+
+	// resolvedEventHook is called with the resolution events of the ongoing
+	// SynchronizeStream call, if any.
+	resolvedEventHook func(ResolvedEvent) error
+
+	// now returns the current time, used to stamp the condition
+	// transitions. Defaults to time.Now.
+	now func() time.Time
+
+	// previousState is the resolved state of the previous reconcile, keyed
+	// by resource id, see WithPreviousState.
+	previousState map[string]interface{}
+
+	// dynamicBaseEnvironment declares the context variables of the dynamic
+	// expressions, and dynamicEnvironments caches the environments extending
+	// it with the dependencies of the expressions, keyed by their sorted
+	// dependencies joined by commas.
+	dynamicBaseEnvironment *cel.Env
+	dynamicEnvironments    map[string]*cel.Env
+
+	// memoCache caches the results of the dynamic expressions, possibly
+	// across runtimes, see WithMemoCache. memoInputs caches the context
+	// field paths referenced by the expressions, found by memoInspector.
+	memoCache     MemoCache
+	memoInputs    map[string][]string
+	memoInspector *ast.Inspector
+
+	// strictNulls enables the strict null handling of the resource fields,
+	// see WithStrictNullHandling.
+	strictNulls bool
+
+	// readinessProbes are the readiness probes of the resources, keyed by
+	// resource id.
+	readinessProbes map[string]ReadinessProbe
+
+	// errorHistories holds the recent evaluation errors of the expressions,
+	// keyed by expression, bounded to errorHistorySize errors each.
+	errorHistories   map[string]*errorHistory
+	errorHistorySize int
+
+	// costAwareOrdering evaluates the cheapest dynamic expressions first, see
+	// WithCostAwareOrdering. expressionCosts caches the estimated costs of
+	// the expressions.
+	costAwareOrdering bool
+	expressionCosts   map[string]uint64
+
+	// reconcileContext is the contextual information provided by the
+	// controller, see WithReconcileContext.
+	reconcileContext map[string]interface{}
+
+	// reclassifiedExpressions are the dynamic expressions reclassified as
+	// static, because they don't reference any resource.
+	reclassifiedExpressions []string
+
+	// prunedResources are the ids of the resources rendered without their
+	// null fields, see WithFieldPruning.
+	prunedResources map[string]bool
+
+	// options are the options the runtime was created with, and templates
+	// and instanceTemplate are copies of the objects of the resources and of
+	// the last instance set (see SetInstance) before any evaluation, used to
+	// clone the runtime.
+	options          []Option
+	templates        map[string]map[string]interface{}
+	instanceTemplate map[string]interface{}
+
+	// auditRedaction returns true for the fields whose values are redacted
+	// from the audit trail, see WithAuditRedaction.
+	auditRedaction func(field ResolvedField) bool
+
+	// readinessReferences caches whether the dynamic expressions reference
+	// the readiness of their dependencies, and identityReferences the
+	// identity fields of the dependencies they only reference by identity,
+	// both found by referencesInspector.
+	readinessReferences map[string]bool
+	identityReferences  map[string]map[string][]string
+	referencesInspector *ast.Inspector
+
+	// lazyResolution makes GetResource resolve the requested resource on
+	// demand, see WithLazyResolution.
+	lazyResolution bool
+
+	// referenceValidation makes the constructor validate the references of
+	// the graph, see WithReferenceValidation.
+	referenceValidation bool
+
+	// requeueInterval is the requeue interval declared by the instance, see
+	// ComputeRequeueInterval.
+	requeueInterval string
+
+	// log is the logger used to report the issues of the graph that don't
+	// prevent its resolution, see WithLogger.
+	log logr.Logger
+
+	// progressCallback is called with the resolution progress during
+	// Synchronize, at most once per progressInterval, see
+	// WithProgressCallback.
+	progressCallback func(Progress)
+	progressInterval time.Duration
+	// progressReported, lastProgress and lastProgressReport track the last
+	// progress report, to skip the redundant ones.
+	progressReported   bool
+	lastProgress       Progress
+	lastProgressReport time.Time
+
+	// validationRules are the invariants of the instance, checked by
+	// Synchronize, see WithValidationRules.
+	validationRules []ValidationRule
+
+	// maxSynchronizeIterations caps the Synchronize passes that don't
+	// converge, see WithMaxSynchronizeIterations. synchronizeIterations
+	// counts them since the last change of the inputs of the runtime.
+	maxSynchronizeIterations int
+	synchronizeIterations    int
+
+	// injectedErrors are the errors returned by the next evaluation of the
+	// expressions, see InjectEvaluationError. They are guarded by
+	// injectedErrorsLock, static expressions are evaluated concurrently.
+	injectedErrors     map[string]error
+	injectedErrorsLock sync.Mutex
+
+	// clusterInfo is the identity of the cluster the instance is reconciled
+	// in, see WithClusterInfo.
+	clusterInfo map[string]interface{}
 }
 
 // TopologicalOrder returns the topological order of resources.
@@ -193,21 +450,85 @@ func (rt *ResourceGraphDefinitionRuntime) GetResource(id string) (*unstructured.
 		return r, ResourceStateResolved
 	}
 
+	if rt.lazyResolution {
+		rt.resolveLazily(id)
+	}
+
 	// If not, can we process the resource?
 	resolved := rt.canProcessResource(id)
 	if resolved {
-		return rt.resources[id].Unstructured(), ResourceStateResolved
+		obj := rt.resources[id].Unstructured()
+		if rt.injectOwnerReferences {
+			rt.injectOwnerReference(id, obj)
+		}
+		if rt.prunedResources[id] {
+			obj = prunedCopy(obj)
+		}
+		return obj, ResourceStateResolved
 	}
 
 	return nil, ResourceStateWaitingOnDependencies
 }
 
+// injectOwnerReference adds an ownerReference pointing to the instance to the
+// given rendered resource, unless it already has one.
+func (rt *ResourceGraphDefinitionRuntime) injectOwnerReference(id string, obj *unstructured.Unstructured) {
+	instance := rt.instance.Unstructured()
+	if instance.GetUID() == "" {
+		return
+	}
+	// cluster scoped resources can't be owned by namespaced resources.
+	if !rt.resources[id].IsNamespaced() && instance.GetNamespace() != "" {
+		return
+	}
+
+	ownerReferences := obj.GetOwnerReferences()
+	for _, ref := range ownerReferences {
+		if ref.UID == instance.GetUID() {
+			return
+		}
+	}
+	ownerReferences = append(ownerReferences, metadata.NewInstanceOwnerReference(
+		instance.GroupVersionKind(),
+		instance.GetName(),
+		instance.GetUID(),
+	))
+	obj.SetOwnerReferences(ownerReferences)
+}
+
 // SetResource updates or sets a resource in the runtime. This is typically
 // called after a resource has been created or updated in the cluster.
+//
+// Setting a nil resource means that the resource is gone (e.g it was deleted
+// out from under us): the resource is forgotten, and the dynamic expressions
+// depending on it are invalidated, so that its direct dependents go back to
+// ResourceStateWaitingOnDependencies until the resource is set again.
 func (rt *ResourceGraphDefinitionRuntime) SetResource(id string, resource *unstructured.Unstructured) {
+	rt.synchronizeIterations = 0
+	delete(rt.typedObjects, id)
+	delete(rt.defaultedObjects, id)
+	if resource == nil {
+		delete(rt.resolvedResources, id)
+		rt.invalidateExpressionsDependingOn(id)
+		return
+	}
 	rt.resolvedResources[id] = resource
 }
 
+// invalidateExpressionsDependingOn marks the dynamic expressions depending on
+// the given resource as unresolved.
+func (rt *ResourceGraphDefinitionRuntime) invalidateExpressionsDependingOn(id string) {
+	for _, state := range rt.expressionsCache {
+		if !dependsOn(state, id) {
+			continue
+		}
+		state.Resolved = false
+		state.ResolvedValue = nil
+		state.ResolvedToNull = false
+		state.IncompleteDataAttempts = 0
+	}
+}
+
 // GetInstance returns the main instance object managed by this runtime.
 func (rt *ResourceGraphDefinitionRuntime) GetInstance() *unstructured.Unstructured {
 	return rt.instance.Unstructured()
@@ -215,9 +536,12 @@ func (rt *ResourceGraphDefinitionRuntime) GetInstance() *unstructured.Unstructur
 
 // SetInstance updates the main instance object.
 // This is typically called after the instance has been updated in the cluster.
+// The instance template, used by the clones of the runtime, is updated too.
 func (rt *ResourceGraphDefinitionRuntime) SetInstance(obj *unstructured.Unstructured) {
 	ptr := rt.instance.Unstructured()
 	ptr.Object = obj.Object
+	rt.instanceTemplate = deepCopyValue(obj.Object).(map[string]interface{})
+	rt.synchronizeIterations = 0
 }
 
 // Synchronize tries to resolve as many resources as possible. It returns true
@@ -228,9 +552,31 @@ func (rt *ResourceGraphDefinitionRuntime) SetInstance(obj *unstructured.Unstruct
 // to resolve as many as possible. If a resource is resolved, it's added to the
 // resolved resources map.
 func (rt *ResourceGraphDefinitionRuntime) Synchronize() (bool, error) {
+	resolved := rt.resolvedExpressionsCount()
+	defer func() {
+		rt.lastSynchronizeProgressed = rt.resolvedExpressionsCount() > resolved
+	}()
+
+	var more bool
+	var err error
+	if rt.tracer != nil {
+		more, err = rt.tracedSynchronize()
+	} else {
+		more, err = rt.synchronize()
+	}
+	return rt.checkConvergence(more, err)
+}
+
+// synchronize implements Synchronize.
+func (rt *ResourceGraphDefinitionRuntime) synchronize() (bool, error) {
+	// nothing is resolved for instances breaking their invariants.
+	if err := rt.checkValidationRules(); err != nil {
+		return true, err
+	}
+
 	// if everything is resolved, we're done.
 	// TODO(a-hilaly): Add readiness check here.
-	if rt.allExpressionsAreResolved() && len(rt.resolvedResources) == len(rt.resources) {
+	if rt.allExpressionsAreResolved() && rt.allResourcesResolvedOrIgnored() {
 		return false, nil
 	}
 
@@ -239,18 +585,21 @@ func (rt *ResourceGraphDefinitionRuntime) Synchronize() (bool, error) {
 	if err != nil {
 		return true, fmt.Errorf("failed to evaluate dynamic variables: %w", err)
 	}
+	rt.reportProgress()
 
 	// Now propagate the resource variables.
 	err = rt.propagateResourceVariables()
 	if err != nil {
 		return true, fmt.Errorf("failed to propagate resource variables: %w", err)
 	}
+	rt.reportProgress()
 
 	// then synchronize the instance
 	err = rt.evaluateInstanceStatuses()
 	if err != nil {
 		return true, fmt.Errorf("failed to evaluate instance statuses: %w", err)
 	}
+	rt.reportProgress()
 
 	return true, nil
 }
@@ -265,25 +614,47 @@ func (rt *ResourceGraphDefinitionRuntime) propagateResourceVariables() error {
 			if err != nil {
 				return fmt.Errorf("failed to evaluate resource variables for %s: %w", id, err)
 			}
+			if err := rt.emitResolvedEvent(ResolvedEvent{Type: ResolvedEventResource, ResourceID: id}); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 // canProcessResource checks if a resource can be resolved by examining
-// if all its dependencies are resolved AND if all its variables are resolved.
+// if all its dependencies are resolved AND if all its variables are resolved
+// AND if all its readiness dependencies are ready.
 func (rt *ResourceGraphDefinitionRuntime) canProcessResource(resource string) bool {
 	// Check if all dependencies are resolved. a.k.a all variables have been
 	// evaluated.
 	for _, dep := range rt.resources[resource].GetDependencies() {
+		// Ignored dependencies are never going to be resolved, the
+		// expressions referencing them are resolved to null instead.
+		if rt.ignoredByConditionsResources[dep] {
+			continue
+		}
 		if !rt.resourceVariablesResolved(dep) {
 			return false
 		}
 	}
 
 	// Check if the resource variables are resolved.
-	kk := rt.resourceVariablesResolved(resource)
-	return kk
+	if !rt.resourceVariablesResolved(resource) {
+		return false
+	}
+
+	// Some dependencies need to be ready, not only resolved, e.g a database
+	// accepting connections.
+	for _, dep := range rt.resources[resource].GetReadinessDependencies() {
+		if rt.ignoredByConditionsResources[dep] {
+			continue
+		}
+		if ready, _, err := rt.isResourceReady(dep); err != nil || !ready {
+			return false
+		}
+	}
+	return true
 }
 
 // resourceVariablesResolved determines if all variables for a given resource
@@ -302,28 +673,181 @@ func (rt *ResourceGraphDefinitionRuntime) resourceVariablesResolved(resource str
 // depending only on the initial configuration. This function is usually
 // called once during runtime initialization to set up the baseline state
 func (rt *ResourceGraphDefinitionRuntime) evaluateStaticVariables() error {
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	env, err := rt.staticEnvironment()
 	if err != nil {
 		return err
 	}
 
-	evalContext := map[string]interface{}{
-		"schema": rt.instance.Unstructured().Object,
-	}
+	// Static expressions only depend on the instance spec, so they can be
+	// evaluated independently. Sorting them makes the reported error
+	// deterministic, no matter how the evaluations are scheduled.
+	var statics []*expressionEvaluationState
 	for _, variable := range rt.expressionsCache {
 		if variable.Kind.IsStatic() {
-			value, err := evaluateExpression(env, evalContext, variable.Expression)
+			statics = append(statics, variable)
+		}
+	}
+	slices.SortFunc(statics, func(a, b *expressionEvaluationState) int {
+		return strings.Compare(a.Expression, b.Expression)
+	})
+
+	evalContext := rt.newEvalContext()
+	values := make([]interface{}, len(statics))
+	errs := make([]error, len(statics))
+
+	workers := rt.staticEvaluationWorkers
+	if workers <= 0 {
+		workers = goruntime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(statics))
+	if workers <= 1 {
+		// Not worth spinning goroutines for a single expression.
+		for i, variable := range statics {
+			values[i], errs[i] = rt.evaluate(env, evalContext, variable.Expression)
+		}
+	} else {
+		// The workers don't read the runtime trace context, it's only
+		// owned by the goroutine calling Synchronize.
+		traceContext := rt.traceContext
+		indexes := make(chan int)
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Every worker gets its own compilation errors cache, to
+				// avoid sharing the runtime one between goroutines.
+				evaluator := rt.newCELEvaluator(env, make(map[compilationErrorKey]error))
+				for i := range indexes {
+					values[i], errs[i] = rt.evaluateInTrace(traceContext, evaluator, evalContext, statics[i].Expression)
+				}
+			}()
+		}
+		for i := range statics {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		for i, err := range errs {
 			if err != nil {
-				return err
+				rt.recordEvaluationError(statics[i].Expression, err)
 			}
+		}
+	}
 
-			variable.Resolved = true
-			variable.ResolvedValue = value
+	for i, variable := range statics {
+		if errs[i] != nil {
+			rt.markExpressionFailed(variable, errs[i])
+			return errs[i]
 		}
+		variable.Resolved = true
+		variable.ResolvedValue = values[i]
 	}
 	return nil
 }
 
+// contextVariableNames are the names of the variables that are always part
+// of the evaluation context, regardless of which resources are resolved.
+//
+//   - schema: the instance object, mainly used to access the instance spec.
+//   - instance: a restricted view of the instance, exposing its identity
+//     (apiVersion, kind, name, namespace and uid), labels and annotations,
+//     e.g to propagate them to the sub resources or build ownerReferences.
+//   - resourceGroup: the identity (name and version) of the resource graph
+//     definition that produced the instance, see WithResourceGroup.
+//   - previous: the resolved resources of the previous reconcile, keyed by
+//     resource id, see WithPreviousState.
+//   - reconcileContext: the contextual information provided by the
+//     controller, e.g the reconcile id or the cluster name, see
+//     WithReconcileContext.
+//   - kroCluster: the identity of the cluster, e.g its name, region or
+//     provider, see WithClusterInfo.
+var contextVariableNames = []string{"schema", "instance", "resourceGroup", previousVariableName, reconcileContextVariableName, clusterVariableName}
+
+// reconcileContextVariableName is the name of the variable exposing the
+// reconcile context to the expressions, see WithReconcileContext.
+const reconcileContextVariableName = "reconcileContext"
+
+// clusterVariableName is the name of the variable exposing the cluster
+// information to the expressions, see WithClusterInfo.
+const clusterVariableName = "kroCluster"
+
+// resourcesVariableName is the name of the variable exposing the resolved
+// resources, as a list sorted by resource id, to the dynamic expressions.
+// e.g "${countWhere(resources, r, r.kind == 'Pod' && r.status.phase == 'Running')}"
+const resourcesVariableName = "resources"
+
+// readyVariableName is the name of the variable exposing the readiness of the
+// dependencies of the dynamic expressions (see IsResourceReady), keyed by
+// resource id. Ignored dependencies are never ready. e.g
+// "${ready.database ? schema.spec.replicas : 1}". The graph builder makes the
+// resources whose readiness is read dependencies of the expression.
+const readyVariableName = "ready"
+
+// resolvedResourcesList returns the observed values of the resolved
+// resources, sorted by resource id.
+func (rt *ResourceGraphDefinitionRuntime) resolvedResourcesList() ([]interface{}, error) {
+	ids := maps.Keys(rt.resolvedResources)
+	slices.Sort(ids)
+	list := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		value, err := rt.observedValue(id, rt.resolvedResources[id])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+// reconcileContextOrEmpty returns the reconcile context, or an empty map if
+// the controller didn't provide any, so that expressions can check for its
+// keys with has().
+func (rt *ResourceGraphDefinitionRuntime) reconcileContextOrEmpty() map[string]interface{} {
+	if rt.reconcileContext == nil {
+		return map[string]interface{}{}
+	}
+	return rt.reconcileContext
+}
+
+// clusterInfoOrEmpty returns the cluster information, or an empty map if the
+// controller didn't provide any, so that expressions can check for its keys
+// with has().
+func (rt *ResourceGraphDefinitionRuntime) clusterInfoOrEmpty() map[string]interface{} {
+	if rt.clusterInfo == nil {
+		return map[string]interface{}{}
+	}
+	return rt.clusterInfo
+}
+
+// newEvalContext returns a new evaluation context populated with the
+// variables listed in contextVariableNames.
+func (rt *ResourceGraphDefinitionRuntime) newEvalContext() map[string]interface{} {
+	instance := rt.instance.Unstructured()
+	obj := instance.Object
+	return map[string]interface{}{
+		"schema": obj,
+		"instance": map[string]interface{}{
+			"apiVersion": instance.GetAPIVersion(),
+			"kind":       instance.GetKind(),
+			"metadata": map[string]interface{}{
+				"name":        instance.GetName(),
+				"namespace":   instance.GetNamespace(),
+				"uid":         string(instance.GetUID()),
+				"labels":      nestedMapOrEmpty(obj, "metadata", "labels"),
+				"annotations": nestedMapOrEmpty(obj, "metadata", "annotations"),
+			},
+		},
+		"resourceGroup": map[string]interface{}{
+			"name":    rt.resourceGroupName,
+			"version": rt.resourceGroupVersion,
+		},
+		previousVariableName:         rt.previousStateOrEmpty(),
+		reconcileContextVariableName: rt.reconcileContextOrEmpty(),
+		clusterVariableName:          rt.clusterInfoOrEmpty(),
+	}
+}
+
 type EvalError struct {
 	IsIncompleteData bool
 	Err              error
@@ -336,70 +860,191 @@ func (e *EvalError) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the underlying evaluation error, e.g to match it against
+// ErrArithmeticOverflow or ErrDivisionByZero.
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
 // evaluateDynamicVariables processes all dynamic variables in the runtime.
 // Dynamic variables depend on the state of other resources and are evaluated
 // iteratively as resources are resolved. This function is called during each
 // synchronization cycle to update the runtime state based on newly resolved
 // resources.
 func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariables() error {
+	return rt.evaluateDynamicVariablesOf(rt.dynamicEvaluationOrder())
+}
+
+// evaluateDynamicVariablesOf implements evaluateDynamicVariables for the given
+// variables, evaluated in order.
+func (rt *ResourceGraphDefinitionRuntime) evaluateDynamicVariablesOf(variables []*expressionEvaluationState) error {
 	// Dynamic variables are those that depend on other resources
 	// and are resolved after all the dependencies are resolved.
 
 	resolvedResources := maps.Keys(rt.resolvedResources)
-	resolvedResources = append(resolvedResources, "schema")
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(resolvedResources))
+	resolvedResources = append(resolvedResources, contextVariableNames...)
+	// Resources ignored by conditions are part of the environment as well,
+	// they are exposed as null values to the expressions referencing them.
+	for id := range rt.ignoredByConditionsResources {
+		if _, ok := rt.resolvedResources[id]; !ok {
+			resolvedResources = append(resolvedResources, id)
+		}
+	}
+	resourcesList, err := rt.resolvedResourcesList()
 	if err != nil {
-		return err
+		return &EvalError{Err: err}
 	}
+	// The readiness of the dependencies is only computed for the expressions
+	// referencing it, at most once per resource.
+	readiness := make(map[string]bool)
 
 	// let's iterate over any resolved resource and try to resolve
 	// the dynamic variables that depend on it.
 	// Since we have already cached the expressions, we don't need to
 	// loop over all the resources.
-	for _, variable := range rt.expressionsCache {
-		if variable.Kind.IsDynamic() {
-			// Skip the variable if it's already resolved
-			if variable.Resolved {
+	for _, variable := range variables {
+		// Lazily skip the expressions that only feed the fields of
+		// ignored resources, their values are never going to be used.
+		if !rt.isExpressionNeeded(variable.Expression) {
+			continue
+		}
+
+		// we need to make sure that the dependencies are
+		// part of the resolved resources, or that their identity is
+		// known before their creation.
+		var identities map[string]interface{}
+		if len(variable.Dependencies) > 0 &&
+			!containsAllElements(resolvedResources, variable.Dependencies) {
+			var ok bool
+			if identities, ok = rt.desiredIdentities(variable, resolvedResources); !ok {
 				continue
 			}
+		}
 
-			// we need to make sure that the dependencies are
-			// part of the resolved resources.
-			if len(variable.Dependencies) > 0 &&
-				!containsAllElements(resolvedResources, variable.Dependencies) {
+		env, err := rt.dynamicEnvironment(variable.Dependencies)
+		if err != nil {
+			return err
+		}
+		aliases := rt.kindAliasesOf(variable.Dependencies)
+
+		evalContext := rt.newEvalContext()
+		evalContext[resourcesVariableName] = resourcesList
+		dependsOnIgnored := false
+		for _, dep := range variable.Dependencies {
+			resource, ok := rt.resolvedResources[dep]
+			if identity, isDesired := identities[dep]; !ok && isDesired {
+				evalContext[dep] = identity
 				continue
 			}
-
-			evalContext := make(map[string]interface{})
-			for _, dep := range variable.Dependencies {
-				evalContext[dep] = rt.resolvedResources[dep].Object
+			if !ok {
+				// only ignored resources can be missing at this point.
+				evalContext[dep] = nil
+				dependsOnIgnored = true
+				continue
 			}
-
-			evalContext["schema"] = rt.instance.Unstructured().Object
-
-			value, err := evaluateExpression(env, evalContext, variable.Expression)
+			value, err := rt.observedValue(dep, resource)
+			if err != nil {
+				return &EvalError{Err: err}
+			}
+			evalContext[dep] = value
+		}
+		for alias, id := range aliases {
+			if value, ok := evalContext[id]; ok {
+				evalContext[alias] = value
+			}
+		}
+		if rt.referencesReadiness(variable.Expression) {
+			ready, err := rt.dependenciesReadiness(variable.Dependencies, readiness)
 			if err != nil {
-				if strings.Contains(err.Error(), "no such key") {
-					// TODO(a-hilaly): I'm not sure if this is the best way to handle
-					// these. Probably need to reiterate here.
+				return &EvalError{Err: err}
+			}
+			evalContext[readyVariableName] = ready
+		}
+
+		value, err := rt.evaluateMemoized(env, evalContext, variable.Expression)
+		if err != nil {
+			if dependsOnIgnored {
+				// The expression doesn't handle the absence of the ignored
+				// resource (e.g it reads one of its fields), treat it as null
+				// instead of waiting for a resource that will never exist.
+				variable.Resolved = true
+				variable.ResolvedValue = nil
+				variable.ResolvedToNull = true
+				if err := rt.emitExpressionResolved(variable); err != nil {
+					return err
+				}
+				continue
+			}
+			if strings.Contains(err.Error(), "no such key") {
+				variable.IncompleteDataAttempts++
+				// TODO(a-hilaly): I'm not sure if this is the best way to handle
+				// these. Probably need to reiterate here.
+				if rt.incompleteDataThreshold <= 0 ||
+					variable.IncompleteDataAttempts < rt.incompleteDataThreshold {
 					return &EvalError{
 						IsIncompleteData: true,
 						Err:              err,
 					}
 				}
-				return &EvalError{
-					Err: err,
-				}
+				err = fmt.Errorf("data still incomplete after %d attempts: %w", variable.IncompleteDataAttempts, err)
+			}
+			rt.markExpressionFailed(variable, err)
+			return &EvalError{
+				Err: err,
 			}
+		}
 
-			variable.Resolved = true
-			variable.ResolvedValue = value
+		variable.Resolved = true
+		variable.ResolvedValue = value
+		if err := rt.emitExpressionResolved(variable); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// dynamicEnvironment returns the CEL environment of the dynamic expressions
+// depending on the given resources. Only the dependencies and their kind
+// aliases are declared on top of the context variables, instead of every
+// resolved resource, which keeps the environments small on large graphs. The
+// environments are cached by set of dependencies, most expressions of a
+// resource share them.
+func (rt *ResourceGraphDefinitionRuntime) dynamicEnvironment(dependencies []string) (*cel.Env, error) {
+	sorted := slices.Clone(dependencies)
+	slices.Sort(sorted)
+	key := strings.Join(sorted, ",")
+	if env, ok := rt.dynamicEnvironments[key]; ok {
+		return env, nil
+	}
+
+	if rt.dynamicBaseEnvironment == nil {
+		base, err := krocel.DefaultEnvironment(
+			krocel.WithResourceIDs(append(slices.Clone(contextVariableNames), readyVariableName)),
+			krocel.WithResourceLists([]string{resourcesVariableName}),
+			krocel.WithNativeTypes(rt.nativeTypes()...),
+		)
+		if err != nil {
+			return nil, err
+		}
+		rt.dynamicBaseEnvironment = base
+	}
+	names := append(sorted, maps.Keys(rt.kindAliasesOf(sorted))...)
+	declarations := make([]cel.EnvOption, 0, len(names))
+	for _, name := range names {
+		declarations = append(declarations, cel.Variable(name, cel.AnyType))
+	}
+	env, err := rt.dynamicBaseEnvironment.Extend(declarations...)
+	if err != nil {
+		return nil, err
+	}
+	if rt.dynamicEnvironments == nil {
+		rt.dynamicEnvironments = make(map[string]*cel.Env)
+	}
+	rt.dynamicEnvironments[key] = env
+	return env, nil
+}
+
 // evaluateInstanceStatuses updates the status of the main instance based on
 // the current state of all resources. This function aggregates information
 // from all managed resources to provide an overall status of the runtime,
@@ -412,9 +1057,25 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateInstanceStatuses() error {
 	//  2. Not all instance variables are guaranteed to be resolved. This is
 	//     more like a "best effort" to resolve as many as possible.
 	for _, variable := range rt.instance.GetVariables() {
+		// Only ever write the status fields owned by the runtime, the rest
+		// of the status may be written by other controllers.
+		if !isStatusPath(variable.Path) {
+			continue
+		}
 		cached, ok := rt.expressionsCache[variable.Expressions[0]]
 		if ok && cached.Resolved {
-			err := rs.UpsertValueAtPath(variable.Path, rt.expressionsCache[variable.Expressions[0]].ResolvedValue)
+			value := cached.ResolvedValue
+			if isConditionsPath(variable.Path) {
+				// The current value is the old status, as observed or
+				// written by the previous evaluation.
+				oldValue, _ := rs.ValueAtPath(variable.Path)
+				stamped, err := setTransitionTimes(oldValue, value, rt.currentTime())
+				if err != nil {
+					return fmt.Errorf("invalid conditions at path %s: %w", variable.Path, err)
+				}
+				value = stamped
+			}
+			err := rs.UpsertValueAtPath(variable.Path, value)
 			if err != nil {
 				return fmt.Errorf("failed to set value at path %s: %w", variable.Path, err)
 			}
@@ -426,20 +1087,29 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateInstanceStatuses() error {
 // evaluateResourceExpressions processes all expressions associated with a
 // specific resource.
 func (rt *ResourceGraphDefinitionRuntime) evaluateResourceExpressions(resource string) error {
-	exprValues := make(map[string]interface{})
-	for _, v := range rt.expressionsCache {
-		if v.Resolved {
-			exprValues[v.Expression] = v.ResolvedValue
-		}
-	}
-
+	// Only collect the values of the expressions used by the resource. They
+	// are looked up by their cache key, walking the resource variables in
+	// order, so that the collected values don't depend on the map iteration
+	// order and unresolved expressions never shadow resolved ones.
 	variables := rt.resources[resource].GetVariables()
+	exprValues := make(map[string]interface{})
 	exprFields := make([]variable.FieldDescriptor, len(variables))
 	for i, v := range variables {
 		exprFields[i] = v.FieldDescriptor
+		for _, expr := range v.Expressions {
+			if cached, ok := rt.expressionsCache[expr]; ok && cached.Resolved {
+				exprValues[expr] = cached.ResolvedValue
+			}
+		}
 	}
 
 	rs := resolver.NewResolver(rt.resources[resource].Unstructured().Object, exprValues)
+	if observed, ok := rt.resolvedResources[resource]; ok && observed != nil {
+		rs.WithObserved(observed.Object)
+	}
+	if rt.strictNulls {
+		rs.WithStrictNulls()
+	}
 
 	summary := rs.Resolve(exprFields)
 	if summary.Errors != nil {
@@ -449,10 +1119,11 @@ func (rt *ResourceGraphDefinitionRuntime) evaluateResourceExpressions(resource s
 }
 
 // allExpressionsAreResolved checks if every expression in the runtimes cache
-// has been successfully evaluated
+// has been successfully evaluated. Expressions only used by ignored resources
+// are skipped.
 func (rt *ResourceGraphDefinitionRuntime) allExpressionsAreResolved() bool {
 	for _, v := range rt.expressionsCache {
-		if !v.Resolved {
+		if !v.Resolved && rt.isExpressionNeeded(v.Expression) {
 			return false
 		}
 	}
@@ -460,9 +1131,23 @@ func (rt *ResourceGraphDefinitionRuntime) allExpressionsAreResolved() bool {
 }
 
 // IsResourceReady checks if a resource is ready based on the readyWhenExpressions
-// defined in the resource. If no readyWhenExpressions are defined, the resource
-// is considered ready.
+// defined in the resource, and its readiness probe, if any (see
+// WithReadinessProbe). If no readyWhenExpressions nor probe are defined, the
+// resource is considered ready.
 func (rt *ResourceGraphDefinitionRuntime) IsResourceReady(resourceID string) (bool, string, error) {
+	ready, reason, err := rt.isResourceReady(resourceID)
+	rt.recordReadinessAttempt(resourceID, ready)
+	return ready, reason, err
+}
+
+func (rt *ResourceGraphDefinitionRuntime) isResourceReady(resourceID string) (bool, string, error) {
+	return rt.evaluateReadiness(resourceID, nil)
+}
+
+// evaluateReadiness implements isResourceReady, evaluating the readyWhen
+// expressions with the given environment. A new environment declaring the
+// resource is created if it's nil.
+func (rt *ResourceGraphDefinitionRuntime) evaluateReadiness(resourceID string, env *cel.Env) (bool, string, error) {
 	observed, ok := rt.resolvedResources[resourceID]
 	if !ok {
 		// Users need to make sure that the resource is resolved a.k.a (SetResource)
@@ -470,38 +1155,119 @@ func (rt *ResourceGraphDefinitionRuntime) IsResourceReady(resourceID string) (bo
 		return false, fmt.Sprintf("resource %s is not resolved", resourceID), nil
 	}
 
+	if rt.resources[resourceID].GetSkipReadiness() {
+		return true, "", nil
+	}
+
 	expressions := rt.resources[resourceID].GetReadyWhenExpressions()
 	if len(expressions) == 0 {
-		return true, "", nil
+		return rt.probeReadiness(resourceID, observed)
 	}
 
-	// we should not expect errors here since we already compiled it
-	// in the dryRun
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{resourceID}))
+	if env == nil {
+		// we should not expect errors here since we already compiled it
+		// in the dryRun
+		var err error
+		env, err = rt.readinessEnvironment([]string{resourceID})
+		if err != nil {
+			return false, "", fmt.Errorf("failed creating new Environment: %w", err)
+		}
+	}
+	value, err := rt.observedValue(resourceID, observed)
 	if err != nil {
-		return false, "", fmt.Errorf("failed creating new Environment: %w", err)
+		return false, "", err
 	}
 	context := map[string]interface{}{
-		resourceID: observed.Object,
+		resourceID: value,
 	}
 
 	for _, expression := range expressions {
-		out, err := evaluateExpression(env, context, expression)
+		out, err := rt.evaluate(env, context, expression)
 		if err != nil {
 			return false, "", fmt.Errorf("failed evaluating expressison %s: %w", expression, err)
 		}
+		ready, ok := out.(bool)
+		if !ok {
+			return false, "", fmt.Errorf("readyWhen expression %s returned %T, expected bool", expression, out)
+		}
 		// returning a reason here to point out which expression is not ready yet
-		if !out.(bool) {
-			return false, fmt.Sprintf("expression %s evaluated to false", expression), nil
+		if !ready {
+			return false, rt.readyWhenFailureReason(resourceID, expression, env, context), nil
 		}
 	}
-	return true, "", nil
+	return rt.probeReadiness(resourceID, observed)
+}
+
+// IsResourceFullyRendered returns true if every expression used by the fields
+// of the resource resolved to a non null value. A resource can be resolvable
+// (GetResource returns ResourceStateResolved) while some of its fields are
+// null, e.g when they reference a resource ignored by conditions. Callers can
+// use this to avoid applying partial specs.
+func (rt *ResourceGraphDefinitionRuntime) IsResourceFullyRendered(resourceID string) bool {
+	resource, ok := rt.resources[resourceID]
+	if !ok {
+		return false
+	}
+	for _, v := range resource.GetVariables() {
+		for _, expr := range v.Expressions {
+			cached, ok := rt.expressionsCache[expr]
+			if !ok || !cached.Resolved || cached.ResolvedValue == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readyWhenFailureReason returns the reason to report when the given readyWhen
+// expression evaluated to false. If the resource defines a message for the
+// expression, the expressions embedded in the message are evaluated against
+// the readiness context. Otherwise (or if the message can't be rendered), a
+// generic reason pointing out the expression is returned.
+func (rt *ResourceGraphDefinitionRuntime) readyWhenFailureReason(
+	resourceID, expression string,
+	env *cel.Env,
+	context map[string]interface{},
+) string {
+	reason := fmt.Sprintf("expression %s evaluated to false", expression)
+
+	message, ok := rt.resources[resourceID].GetReadyWhenMessages()[expression]
+	if !ok {
+		return reason
+	}
+	rendered, err := rt.renderMessage(env, context, message)
+	if err != nil {
+		return fmt.Sprintf("%s (failed rendering message: %v)", reason, err)
+	}
+	return rendered
+}
+
+// renderMessage replaces the expressions embedded in the given message with
+// their evaluated values.
+func (rt *ResourceGraphDefinitionRuntime) renderMessage(
+	env *cel.Env,
+	context map[string]interface{},
+	message string,
+) (string, error) {
+	expressions, err := parser.ExtractExpressions(message)
+	if err != nil {
+		return "", err
+	}
+	for _, expression := range expressions {
+		out, err := rt.evaluate(env, context, expression)
+		if err != nil {
+			return "", err
+		}
+		message = strings.ReplaceAll(message, "${"+expression+"}", fmt.Sprintf("%v", out))
+	}
+	return message, nil
 }
 
 // IgnoreResource ignores resource that has a conditions expressison that evaluated
 // to false or whose dependencies are ignored
 func (rt *ResourceGraphDefinitionRuntime) IgnoreResource(resourceID string) {
 	rt.ignoredByConditionsResources[resourceID] = true
+	rt.synchronizeIterations = 0
 }
 
 // areDependenciesIgnored will returns true if the dependencies of the resource
@@ -509,16 +1275,73 @@ func (rt *ResourceGraphDefinitionRuntime) IgnoreResource(resourceID string) {
 //
 // Naturally, if a resource is judged to be ignored, it will be marked as ignored
 // and all its dependencies will be ignored as well. Causing a chain reaction
-// of ignored resources.
+// of ignored resources. The only exception is a resource that handles the
+// absence of its ignored dependencies, a.k.a all the expressions referencing
+// them were evaluated without reading fields of the null resource. e.g
+// `${cache == null ? "none" : cache.metadata.name}`.
 func (rt *ResourceGraphDefinitionRuntime) areDependenciesIgnored(resourceID string) bool {
 	for _, p := range rt.resources[resourceID].GetDependencies() {
 		if _, isIgnored := rt.ignoredByConditionsResources[p]; isIgnored {
+			if !rt.handlesIgnoredDependency(resourceID, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handlesIgnoredDependency returns true if the resource references the given
+// ignored dependency, and all the expressions doing so were resolved without
+// being defaulted to null.
+func (rt *ResourceGraphDefinitionRuntime) handlesIgnoredDependency(resourceID, dependency string) bool {
+	referenced := false
+	for _, variable := range rt.runtimeVariables[resourceID] {
+		if !slices.Contains(variable.Dependencies, dependency) {
+			continue
+		}
+		if !variable.Resolved || variable.ResolvedToNull {
+			return false
+		}
+		referenced = true
+	}
+	return referenced
+}
+
+// addExpressionConsumer records that the given expression is used by the
+// fields of the given resource.
+func (rt *ResourceGraphDefinitionRuntime) addExpressionConsumer(expression, resourceID string) {
+	if !slices.Contains(rt.expressionConsumers[expression], resourceID) {
+		rt.expressionConsumers[expression] = append(rt.expressionConsumers[expression], resourceID)
+	}
+}
+
+// isExpressionNeeded returns false if the expression is only used by
+// resources ignored by conditions. Expressions with unknown consumers are
+// always needed.
+func (rt *ResourceGraphDefinitionRuntime) isExpressionNeeded(expression string) bool {
+	consumers, ok := rt.expressionConsumers[expression]
+	if !ok {
+		return true
+	}
+	for _, id := range consumers {
+		if !rt.ignoredByConditionsResources[id] {
 			return true
 		}
 	}
 	return false
 }
 
+// allResourcesResolvedOrIgnored returns true if every resource was either set
+// by the caller (SetResource) or ignored by conditions.
+func (rt *ResourceGraphDefinitionRuntime) allResourcesResolvedOrIgnored() bool {
+	for id := range rt.resources {
+		if _, ok := rt.resolvedResources[id]; !ok && !rt.ignoredByConditionsResources[id] {
+			return false
+		}
+	}
+	return true
+}
+
 // WantToCreateResource returns true if all the condition expressions return true
 // if not it will add itself to the ignored resources
 func (rt *ResourceGraphDefinitionRuntime) WantToCreateResource(resourceID string) (bool, error) {
@@ -526,40 +1349,235 @@ func (rt *ResourceGraphDefinitionRuntime) WantToCreateResource(resourceID string
 		return false, nil
 	}
 
+	included, condition, err := rt.evaluateIncludeWhen(resourceID)
+	if err != nil {
+		return false, err
+	}
+	// returning a reason here to point out which expression is not ready yet
+	if !included {
+		return false, fmt.Errorf("Skipping resource creation due to condition %s", condition)
+	}
+	return true, nil
+}
+
+// evaluateIncludeWhen evaluates the includeWhen expressions of the resource.
+// It returns false and the first condition that evaluated to false if the
+// resource shouldn't be created.
+func (rt *ResourceGraphDefinitionRuntime) evaluateIncludeWhen(resourceID string) (bool, string, error) {
 	conditions := rt.resources[resourceID].GetIncludeWhenExpressions()
 	if len(conditions) == 0 {
-		return true, nil
+		return true, "", nil
 	}
 
 	// we should not expect errors here since we already compiled it
 	// in the dryRun
-	env, err := krocel.DefaultEnvironment(krocel.WithResourceIDs([]string{"schema"}))
+	env, err := rt.staticEnvironment()
 	if err != nil {
-		return false, nil
+		return false, "", nil
 	}
 
-	context := map[string]interface{}{
-		"schema": rt.instance.Unstructured().Object,
-	}
+	context := rt.newEvalContext()
 
 	for _, condition := range conditions {
 		// We should not expect an error here as well since we checked during dry-run
-		value, err := evaluateExpression(env, context, condition)
+		value, err := rt.evaluate(env, context, condition)
 		if err != nil {
-			return false, err
+			return false, "", err
 		}
-		// returning a reason here to point out which expression is not ready yet
-		if !value.(bool) {
-			return false, fmt.Errorf("Skipping resource creation due to condition %s", condition)
+		include, ok := value.(bool)
+		if !ok {
+			return false, "", fmt.Errorf("includeWhen expression %s returned %T, expected bool", condition, value)
+		}
+		if !include {
+			return false, condition, nil
 		}
 	}
-	return true, nil
+	return true, "", nil
+}
+
+// StateEqual returns true if the runtime and the other runtime hold
+// semantically equal resolved resources and resolved expression values.
+//
+// This is mainly useful for testing that Synchronize converges to the same
+// state regardless of the order in which resources are set.
+func (rt *ResourceGraphDefinitionRuntime) StateEqual(other *ResourceGraphDefinitionRuntime) bool {
+	if other == nil {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(rt.resolvedResources, other.resolvedResources) {
+		return false
+	}
+	if len(rt.expressionsCache) != len(other.expressionsCache) {
+		return false
+	}
+	for expr, state := range rt.expressionsCache {
+		otherState, ok := other.expressionsCache[expr]
+		if !ok || state.Resolved != otherState.Resolved {
+			return false
+		}
+		if !equality.Semantic.DeepEqual(state.ResolvedValue, otherState.ResolvedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluate evaluates an expression using the evaluator registered for its
+// prefix (see WithEvaluator), defaulting to CEL with the given environment.
+func (rt *ResourceGraphDefinitionRuntime) evaluate(env *cel.Env, context map[string]interface{}, expression string) (interface{}, error) {
+	if rt.compilationErrors == nil {
+		rt.compilationErrors = make(map[compilationErrorKey]error)
+	}
+	value, err := rt.evaluateWith(rt.newCELEvaluator(env, rt.compilationErrors), context, expression)
+	if err != nil {
+		rt.recordEvaluationError(expression, err)
+	}
+	return value, err
+}
+
+// newCELEvaluator returns a CEL evaluator using the given environment and
+// compilation errors cache.
+func (rt *ResourceGraphDefinitionRuntime) newCELEvaluator(env *cel.Env, compilationErrors map[compilationErrorKey]error) *celEvaluator {
+	return &celEvaluator{
+		env:                env,
+		compilationErrors:  compilationErrors,
+		featureEnvironment: rt.featureEnvironment,
+	}
+}
+
+// featureEnvironment returns the environment extended with the CEL
+// declarations gated behind the feature flags required by the expression,
+// or the environment itself if it doesn't require any. Extended environments
+// are cached, by base environment and set of feature flags.
+func (rt *ResourceGraphDefinitionRuntime) featureEnvironment(env *cel.Env, expression string) (*cel.Env, error) {
+	state, ok := rt.expressionsCache[expression]
+	if !ok || len(state.RequiredFeatures) == 0 {
+		return env, nil
+	}
+	features := slices.Clone(state.RequiredFeatures)
+	slices.Sort(features)
+	return rt.cachedEnvironment(environmentKey{base: env, name: "features:" + strings.Join(features, ",")}, func() (*cel.Env, error) {
+		gated, err := krocel.FeatureOptions(rt.featureFlags, features)
+		if err != nil {
+			return nil, err
+		}
+		return env.Extend(gated...)
+	})
+}
+
+// environmentKey identifies a cached environment, by name and by the
+// environment it extends, if any.
+type environmentKey struct {
+	base *cel.Env
+	name string
+}
+
+// cachedEnvironment returns the environment cached for the key, building and
+// caching it on the first call. Building errors are not cached. It's safe for
+// concurrent use, static expressions are evaluated concurrently.
+func (rt *ResourceGraphDefinitionRuntime) cachedEnvironment(key environmentKey, build func() (*cel.Env, error)) (*cel.Env, error) {
+	rt.environmentsLock.Lock()
+	defer rt.environmentsLock.Unlock()
+	if env, ok := rt.environments[key]; ok {
+		return env, nil
+	}
+	env, err := build()
+	if err != nil {
+		return nil, err
+	}
+	if rt.environments == nil {
+		rt.environments = make(map[environmentKey]*cel.Env)
+	}
+	rt.environments[key] = env
+	return env, nil
+}
+
+// staticEnvironment returns the environment of the expressions only reading
+// the context variables, e.g the static expressions, the includeWhen
+// conditions or the validation rules.
+func (rt *ResourceGraphDefinitionRuntime) staticEnvironment() (*cel.Env, error) {
+	return rt.cachedEnvironment(environmentKey{name: "static"}, func() (*cel.Env, error) {
+		return krocel.DefaultEnvironment(krocel.WithResourceIDs(contextVariableNames))
+	})
+}
+
+// markExpressionFailed marks the expression as failed, and notifies the
+// OnExpressionFailed callback the first time it happens.
+func (rt *ResourceGraphDefinitionRuntime) markExpressionFailed(state *expressionEvaluationState, err error) {
+	if state.Failed {
+		return
+	}
+	state.Failed = true
+	if rt.onExpressionFailed != nil {
+		rt.onExpressionFailed(state.Expression, err)
+	}
+}
+
+// evaluateWith evaluates the expression with the registered evaluator
+// matching its prefix, falling back to the given CEL evaluator.
+func (rt *ResourceGraphDefinitionRuntime) evaluateWith(
+	celEvaluator Evaluator,
+	context map[string]interface{},
+	expression string,
+) (interface{}, error) {
+	return rt.evaluateInTrace(rt.traceContext, celEvaluator, context, expression)
+}
+
+// evaluateInTrace implements evaluateWith, the evaluation span being a child
+// of the given trace context. The concurrent evaluations get it from their
+// caller, instead of reading the one of the runtime.
+func (rt *ResourceGraphDefinitionRuntime) evaluateInTrace(
+	traceContext context.Context,
+	celEvaluator Evaluator,
+	evalContext map[string]interface{},
+	expression string,
+) (interface{}, error) {
+	if rt.tracer != nil {
+		return rt.tracedEvaluation(traceContext, expression, func() (interface{}, error) {
+			return rt.dispatchEvaluation(celEvaluator, evalContext, expression)
+		})
+	}
+	return rt.dispatchEvaluation(celEvaluator, evalContext, expression)
+}
+
+// dispatchEvaluation evaluates the expression with the registered evaluator
+// matching its prefix, falling back to the given CEL evaluator.
+func (rt *ResourceGraphDefinitionRuntime) dispatchEvaluation(
+	celEvaluator Evaluator,
+	context map[string]interface{},
+	expression string,
+) (interface{}, error) {
+	if err := rt.takeInjectedError(expression); err != nil {
+		return nil, err
+	}
+	if prefix, body, found := strings.Cut(expression, ":"); found {
+		if evaluator, ok := rt.evaluators[prefix]; ok {
+			rt.evaluatorsLock.Lock()
+			defer rt.evaluatorsLock.Unlock()
+			return evaluator.Evaluate(body, context)
+		}
+	}
+	return celEvaluator.Evaluate(expression, context)
 }
 
 // evaluateExpression evaluates an CEL expression and returns a value if successful, or error
 func evaluateExpression(env *cel.Env, context map[string]interface{}, expression string) (interface{}, error) {
+	program, err := compileExpression(env, expression)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateProgram(program, context, expression)
+}
+
+// compileExpression compiles an expression into a CEL program.
+func compileExpression(env *cel.Env, expression string) (cel.Program, error) {
 	ast, issues := env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
+		// The CEL error is generic for unknown functions, point the user to
+		// the similar functions instead.
+		if err := krocel.UnknownFunctionError(env, expression); err != nil {
+			return nil, fmt.Errorf("failed compiling expression %s: %w", expression, err)
+		}
 		return nil, fmt.Errorf("failed compiling expression %s: %w", expression, issues.Err())
 	}
 	// Here as well
@@ -567,17 +1585,39 @@ func evaluateExpression(env *cel.Env, context map[string]interface{}, expression
 	if err != nil {
 		return nil, fmt.Errorf("failed programming expression %s: %w", expression, err)
 	}
+	return program, nil
+}
+
+// evaluateProgram evaluates a compiled expression against the given context.
+func evaluateProgram(program cel.Program, context map[string]interface{}, expression string) (interface{}, error) {
 	// We get an error here when the value field we're looking for is not yet defined
 	// For now leaving it as error, in the future when we see different scenarios
 	// of this error we can make some a reason, and others an error
 	val, _, err := program.Eval(context)
 	if err != nil {
+		if arithmeticErr := classifyArithmeticError(expression, err); arithmeticErr != nil {
+			return nil, arithmeticErr
+		}
 		return nil, fmt.Errorf("failed evaluating expression %s: %w", expression, err)
 	}
 
 	return krocel.GoNativeType(val)
 }
 
+// nestedMapOrEmpty returns the map found at the given fields path, or an
+// empty map if the path doesn't exist or doesn't point to a map.
+func nestedMapOrEmpty(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found {
+		return map[string]interface{}{}
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
 // containsAllElements checks if all elements in the inner slice are present
 // in the outer slice.
 func containsAllElements[T comparable](outer, inner []T) bool {
@@ -15,11 +15,16 @@ package runtime
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/google/cel-go/cel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -1416,6 +1421,47 @@ func Test_evaluateStaticVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "list spread",
+			instance: newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{
+						"extraArgs": []interface{}{"--verbose"},
+					},
+				}),
+			),
+			expressionsCache: map[string]*expressionEvaluationState{
+				"expr1": {
+					Expression: `schema.spec.extraArgs + ["--default"]`,
+					Kind:       variable.ResourceVariableKindStatic,
+					Resolved:   false,
+				},
+				"expr2": {
+					Expression: `[{"name": "A", "args": schema.spec.extraArgs + ["--default"]}]`,
+					Kind:       variable.ResourceVariableKindStatic,
+					Resolved:   false,
+				},
+			},
+			wantCache: map[string]*expressionEvaluationState{
+				"expr1": {
+					Expression:    `schema.spec.extraArgs + ["--default"]`,
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: []interface{}{"--verbose", "--default"},
+				},
+				"expr2": {
+					Expression: `[{"name": "A", "args": schema.spec.extraArgs + ["--default"]}]`,
+					Kind:       variable.ResourceVariableKindStatic,
+					Resolved:   true,
+					ResolvedValue: []interface{}{
+						map[string]interface{}{
+							"name": "A",
+							"args": []interface{}{"--verbose", "--default"},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "invalid expression",
 			instance: newTestResource(
@@ -1454,6 +1500,58 @@ func Test_evaluateStaticVariables(t *testing.T) {
 	}
 }
 
+func Test_evaluateStaticVariables_Parallel(t *testing.T) {
+	newCache := func(exprs ...string) map[string]*expressionEvaluationState {
+		cache := make(map[string]*expressionEvaluationState)
+		for _, expr := range exprs {
+			cache[expr] = &expressionEvaluationState{
+				Expression: expr,
+				Kind:       variable.ResourceVariableKindStatic,
+			}
+		}
+		return cache
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"value": int64(2)},
+	}))
+
+	var exprs []string
+	for i := 0; i < 50; i++ {
+		exprs = append(exprs, fmt.Sprintf("schema.spec.value * %d", i))
+	}
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			rt := &ResourceGraphDefinitionRuntime{
+				instance:                instance,
+				expressionsCache:        newCache(exprs...),
+				staticEvaluationWorkers: workers,
+			}
+			if err := rt.evaluateStaticVariables(); err != nil {
+				t.Fatalf("evaluateStaticVariables() error = %v", err)
+			}
+			for i, expr := range exprs {
+				got := rt.expressionsCache[expr]
+				if !got.Resolved || got.ResolvedValue != int64(2*i) {
+					t.Errorf("expression %q = %v (resolved %v), want %d", expr, got.ResolvedValue, got.Resolved, 2*i)
+				}
+			}
+
+			// The first failing expression, in lexical order, is reported.
+			for run := 0; run < 10; run++ {
+				rt := &ResourceGraphDefinitionRuntime{
+					instance:                instance,
+					expressionsCache:        newCache(append([]string{"b )", "a )"}, exprs...)...),
+					staticEvaluationWorkers: workers,
+				}
+				err := rt.evaluateStaticVariables()
+				if err == nil || !strings.Contains(err.Error(), "a )") {
+					t.Fatalf("evaluateStaticVariables() error = %v, want error for %q", err, "a )")
+				}
+			}
+		})
+	}
+}
 func Test_evaluateDynamicVariables(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -2072,6 +2170,47 @@ func Test_evaluateResourceExpressions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unrelated cache entries don't shadow resolved expressions",
+			resource: newTestResource(
+				withObject(map[string]interface{}{
+					"spec": map[string]interface{}{
+						"value": "${expr1}",
+					},
+				}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.value",
+							Expressions:          []string{"expr1"},
+							StandaloneExpression: true,
+						},
+					},
+				}),
+			),
+			expressions: map[string]*expressionEvaluationState{
+				"expr1": {
+					Expression:    "expr1",
+					Resolved:      true,
+					ResolvedValue: "resolved",
+				},
+				"stale": {
+					Expression:    "expr1",
+					Resolved:      false,
+					ResolvedValue: "stale",
+				},
+				"other": {
+					Expression:    "expr1",
+					Resolved:      true,
+					ResolvedValue: "other",
+				},
+			},
+			wantObj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"value": "resolved",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2253,6 +2392,57 @@ func Test_IsResourceReady(t *testing.T) {
 			want:       false,
 			wantReason: "expression test.status.healthy evaluated to false",
 		},
+		{
+			name: "custom message",
+			resource: newTestResource(
+				withReadyExpressions([]string{"test.status.ready"}),
+				withReadyMessages(map[string]string{
+					"test.status.ready": "waiting for test to become ready",
+				}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"ready": false,
+				},
+			},
+			want:       false,
+			wantReason: "waiting for test to become ready",
+		},
+		{
+			name: "custom message with expressions",
+			resource: newTestResource(
+				withReadyExpressions([]string{"test.status.readyReplicas == test.spec.replicas"}),
+				withReadyMessages(map[string]string{
+					"test.status.readyReplicas == test.spec.replicas": "${test.status.readyReplicas}/${test.spec.replicas} replicas ready",
+				}),
+			),
+			resolvedObject: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": 3,
+				},
+				"status": map[string]interface{}{
+					"readyReplicas": 1,
+				},
+			},
+			want:       false,
+			wantReason: "1/3 replicas ready",
+		},
+		{
+			name: "custom message failing to render",
+			resource: newTestResource(
+				withReadyExpressions([]string{"test.status.ready"}),
+				withReadyMessages(map[string]string{
+					"test.status.ready": "${test.status.missing} missing",
+				}),
+			),
+			resolvedObject: map[string]interface{}{
+				"status": map[string]interface{}{
+					"ready": false,
+				},
+			},
+			want:       false,
+			wantReason: "expression test.status.ready evaluated to false (failed rendering message: failed evaluating expression test.status.missing: no such key: missing)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -2280,6 +2470,119 @@ func Test_IsResourceReady(t *testing.T) {
 		})
 	}
 }
+func Test_IsResourceFullyRendered(t *testing.T) {
+	newResource := func() Resource {
+		return newTestResource(
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{"schema.spec.name"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "spec.cacheEndpoint",
+						Expressions:          []string{"cache.status.endpoint"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"cache"},
+				},
+			}),
+		)
+	}
+
+	tests := []struct {
+		name             string
+		expressionsCache map[string]*expressionEvaluationState
+		wantResolvable   bool
+		want             bool
+	}{
+		{
+			name: "all fields resolved",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: "myapp",
+				},
+				"cache.status.endpoint": {
+					Kind:          variable.ResourceVariableKindDynamic,
+					Resolved:      true,
+					ResolvedValue: "cache:6379",
+				},
+			},
+			wantResolvable: true,
+			want:           true,
+		},
+		{
+			name: "optional field resolved to null",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: "myapp",
+				},
+				"cache.status.endpoint": {
+					Kind:           variable.ResourceVariableKindDynamic,
+					Resolved:       true,
+					ResolvedToNull: true,
+				},
+			},
+			wantResolvable: true,
+			want:           false,
+		},
+		{
+			name: "field not resolved yet",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: "myapp",
+				},
+				"cache.status.endpoint": {
+					Kind: variable.ResourceVariableKindDynamic,
+				},
+			},
+			wantResolvable: false,
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := newResource()
+			rt := &ResourceGraphDefinitionRuntime{
+				resources:        map[string]Resource{"test": resource},
+				expressionsCache: tt.expressionsCache,
+				runtimeVariables: map[string][]*expressionEvaluationState{
+					"test": {
+						tt.expressionsCache["schema.spec.name"],
+						tt.expressionsCache["cache.status.endpoint"],
+					},
+				},
+			}
+
+			if got := rt.canProcessResource("test"); got != tt.wantResolvable {
+				t.Errorf("canProcessResource() = %v, want %v", got, tt.wantResolvable)
+			}
+			if got := rt.IsResourceFullyRendered("test"); got != tt.want {
+				t.Errorf("IsResourceFullyRendered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown resource", func(t *testing.T) {
+		rt := &ResourceGraphDefinitionRuntime{}
+		if rt.IsResourceFullyRendered("unknown") {
+			t.Error("IsResourceFullyRendered() = true, want false")
+		}
+	})
+}
+
 func Test_WantToCreateResource(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2526,6 +2829,59 @@ func Test_evaluateExpression(t *testing.T) {
 	}
 }
 
+func Test_evaluateCachesCompilationErrors(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: newTestResource(),
+		resources: map[string]Resource{
+			"test": newTestResource(),
+		},
+		resolvedResources: map[string]*unstructured.Unstructured{
+			"dep": {Object: map[string]interface{}{}},
+		},
+		expressionsCache: map[string]*expressionEvaluationState{
+			"dep.spec.value +": {
+				Expression:   "dep.spec.value +",
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"dep"},
+			},
+		},
+	}
+
+	firstErr := rt.evaluateDynamicVariables()
+	if firstErr == nil {
+		t.Fatal("evaluateDynamicVariables() expected error, got none")
+	}
+	cached, ok := rt.compilationErrors[compilationErrorKey{env: rt.dynamicEnvironments["dep"], expression: "dep.spec.value +"}]
+	if !ok {
+		t.Fatal("expected compilation error to be cached")
+	}
+
+	// A second attempt must return the cached error instead of compiling the
+	// expression again, which would produce a new error value.
+	secondErr := rt.evaluateDynamicVariables()
+	var evalErr *EvalError
+	if !errors.As(secondErr, &evalErr) {
+		t.Fatalf("evaluateDynamicVariables() error = %v, want *EvalError", secondErr)
+	}
+	if evalErr.Err != cached {
+		t.Errorf("expected cached compilation error %v, got %v", cached, evalErr.Err)
+	}
+
+	t.Run("undeclared references are not cached", func(t *testing.T) {
+		env, err := setupTestEnv([]string{"schema"})
+		if err != nil {
+			t.Fatalf("failed to create environment: %v", err)
+		}
+		_, err = rt.evaluate(env, map[string]interface{}{}, "other.spec.value")
+		if err == nil {
+			t.Fatal("evaluate() expected error, got none")
+		}
+		if _, ok := rt.compilationErrors[compilationErrorKey{env: env, expression: "other.spec.value"}]; ok {
+			t.Error("undeclared reference error should not be cached")
+		}
+	})
+}
+
 func Test_containsAllElements(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -2605,44 +2961,693 @@ func Test_containsAllElements(t *testing.T) {
 	}
 }
 
-type mockResource struct {
-	gvr              schema.GroupVersionResource
-	variables        []*variable.ResourceField
-	dependencies     []string
-	readyExpressions []string
-	conditions       []string
-	topLevelFields   []string
-	namespaced       bool
-	obj              *unstructured.Unstructured
-}
+func Test_StateEqual(t *testing.T) {
+	newRuntime := func(t *testing.T) *ResourceGraphDefinitionRuntime {
+		instance := newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{
+					"name": "myapp",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "status.endpoint",
+						Expressions:          []string{"service.spec.clusterIP"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"service"},
+				},
+			}),
+		)
+		configmap := newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "${schema.spec.name}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{"schema.spec.name"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+			}),
+		)
+		deployment := newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{
+					"configName": "${configmap.metadata.name}",
+				},
+			}),
+			withDependencies([]string{"configmap"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "spec.configName",
+						Expressions:          []string{"configmap.metadata.name"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"configmap"},
+				},
+			}),
+		)
+		service := newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{
+					"selector": "${deployment.metadata.name}",
+				},
+			}),
+			withDependencies([]string{"deployment"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "spec.selector",
+						Expressions:          []string{"deployment.metadata.name"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"deployment"},
+				},
+			}),
+		)
+
+		rt, err := NewResourceGraphDefinitionRuntime(
+			instance,
+			map[string]Resource{
+				"configmap":  configmap,
+				"deployment": deployment,
+				"service":    service,
+			},
+			[]string{"configmap", "deployment", "service"},
+		)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		return rt
+	}
 
-func newMockResource() *mockResource {
-	return &mockResource{
-		obj: &unstructured.Unstructured{
-			Object: make(map[string]interface{}),
+	observed := map[string]map[string]interface{}{
+		"configmap": {
+			"metadata": map[string]interface{}{"name": "myapp"},
+		},
+		"deployment": {
+			"metadata": map[string]interface{}{"name": "myapp-deployment"},
+			"spec":     map[string]interface{}{"configName": "myapp"},
+		},
+		"service": {
+			"metadata": map[string]interface{}{"name": "myapp-service"},
+			"spec": map[string]interface{}{
+				"selector":  "myapp-deployment",
+				"clusterIP": "10.0.0.1",
+			},
 		},
 	}
-}
 
-func (m *mockResource) GetGroupVersionResource() schema.GroupVersionResource {
-	return m.gvr
-}
+	synchronize := func(t *testing.T, rt *ResourceGraphDefinitionRuntime, order []string) {
+		for _, id := range order {
+			rt.SetResource(id, &unstructured.Unstructured{Object: observed[id]})
+			if _, err := rt.Synchronize(); err != nil {
+				t.Fatalf("Synchronize() error = %v", err)
+			}
+		}
+		for i := 0; i < 10; i++ {
+			cont, err := rt.Synchronize()
+			if err != nil {
+				t.Fatalf("Synchronize() error = %v", err)
+			}
+			if !cont {
+				return
+			}
+		}
+		t.Fatal("Synchronize() did not converge")
+	}
 
-func (m *mockResource) GetVariables() []*variable.ResourceField {
-	return m.variables
-}
+	reference := newRuntime(t)
+	synchronize(t, reference, []string{"configmap", "deployment", "service"})
 
-func (m *mockResource) GetDependencies() []string {
-	return m.dependencies
-}
+	ids := []string{"configmap", "deployment", "service"}
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		order := slices.Clone(ids)
+		r.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
 
-func (m *mockResource) GetReadyWhenExpressions() []string {
-	return m.readyExpressions
-}
+		rt := newRuntime(t)
+		synchronize(t, rt, order)
+		if !rt.StateEqual(reference) {
+			t.Errorf("StateEqual() = false for order %v, want true", order)
+		}
+	}
 
-func (m *mockResource) GetIncludeWhenExpressions() []string {
-	return m.conditions
-}
+	t.Run("different resolved resources", func(t *testing.T) {
+		rt := newRuntime(t)
+		synchronize(t, rt, ids)
+		rt.SetResource("service", &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"spec": map[string]interface{}{"clusterIP": "10.0.0.2"},
+			},
+		})
+		if rt.StateEqual(reference) {
+			t.Error("StateEqual() = true, want false")
+		}
+	})
+
+	t.Run("nil runtime", func(t *testing.T) {
+		if reference.StateEqual(nil) {
+			t.Error("StateEqual(nil) = true, want false")
+		}
+	})
+}
+
+func Test_InstanceLabelsPropagation(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "myapp",
+				"labels": map[string]interface{}{
+					"team": "platform",
+					"env":  "prod",
+				},
+				"annotations": map[string]interface{}{
+					"owner": "alice",
+				},
+			},
+			"spec": map[string]interface{}{
+				"name": "myapp",
+			},
+		}),
+	)
+	configmap := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "myapp",
+				"labels": map[string]interface{}{
+					"app":  "myapp",
+					"team": "${instance.metadata.labels.team}",
+				},
+				"annotations": "${instance.metadata.annotations}",
+			},
+			"data": map[string]interface{}{
+				"labelCount": "${size(instance.metadata.labels)}",
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.labels.team",
+					Expressions:          []string{"instance.metadata.labels.team"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "metadata.annotations",
+					Expressions:          []string{"instance.metadata.annotations"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "data.labelCount",
+					Expressions:          []string{"size(instance.metadata.labels)"},
+					StandaloneExpression: true,
+				},
+				Kind: variable.ResourceVariableKindStatic,
+			},
+		}),
+	)
+
+	rt, err := NewResourceGraphDefinitionRuntime(instance, map[string]Resource{"configmap": configmap}, []string{"configmap"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	got, state := rt.GetResource("configmap")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+	}
+	wantLabels := map[string]string{
+		"app":  "myapp",
+		"team": "platform",
+	}
+	if !reflect.DeepEqual(got.GetLabels(), wantLabels) {
+		t.Errorf("labels = %v, want %v", got.GetLabels(), wantLabels)
+	}
+	wantAnnotations := map[string]string{"owner": "alice"}
+	if !reflect.DeepEqual(got.GetAnnotations(), wantAnnotations) {
+		t.Errorf("annotations = %v, want %v", got.GetAnnotations(), wantAnnotations)
+	}
+	if count := got.Object["data"].(map[string]interface{})["labelCount"]; count != int64(2) {
+		t.Errorf("labelCount = %v, want 2", count)
+	}
+}
+
+func Test_IgnoredDependencyResolution(t *testing.T) {
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"cacheEnabled": false,
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.cacheName",
+					Expressions:          []string{"cache.metadata.name"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"cache"},
+			},
+		}),
+	)
+	cache := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "cache"},
+		}),
+		withConditions([]string{"schema.spec.cacheEnabled"}),
+	)
+	// app handles the absence of the cache.
+	app := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "app"},
+			"data": map[string]interface{}{
+				"cache": `${cache == null ? "none" : cache.metadata.name}`,
+			},
+		}),
+		withDependencies([]string{"cache"}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "data.cache",
+					Expressions:          []string{`cache == null ? "none" : cache.metadata.name`},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"cache"},
+			},
+		}),
+	)
+	// worker doesn't handle the absence of the cache.
+	worker := newTestResource(
+		withObject(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "worker"},
+			"data": map[string]interface{}{
+				"cache": "${cache.metadata.name}",
+			},
+		}),
+		withDependencies([]string{"cache"}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "data.cache",
+					Expressions:          []string{"cache.metadata.name"},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"cache"},
+			},
+		}),
+	)
+
+	topologicalOrder := []string{"cache", "app", "worker"}
+	rt, err := NewResourceGraphDefinitionRuntime(
+		instance,
+		map[string]Resource{"cache": cache, "app": app, "worker": worker},
+		topologicalOrder,
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// Mimic the instance controller reconciliation loop.
+	created := map[string]bool{}
+	for _, id := range topologicalOrder {
+		want, err := rt.WantToCreateResource(id)
+		if err != nil || !want {
+			rt.IgnoreResource(id)
+		} else {
+			obj, state := rt.GetResource(id)
+			if state != ResourceStateResolved {
+				t.Fatalf("GetResource(%s) state = %v, want %v", id, state, ResourceStateResolved)
+			}
+			rt.SetResource(id, obj)
+			created[id] = true
+		}
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+	}
+
+	wantCreated := map[string]bool{"app": true}
+	if !reflect.DeepEqual(created, wantCreated) {
+		t.Errorf("created resources = %v, want %v", created, wantCreated)
+	}
+	appObj, _ := rt.GetResource("app")
+	if got := appObj.Object["data"].(map[string]interface{})["cache"]; got != "none" {
+		t.Errorf("app data.cache = %v, want %q", got, "none")
+	}
+	status := rt.GetInstance().Object["status"].(map[string]interface{})
+	if got, ok := status["cacheName"]; !ok || got != nil {
+		t.Errorf("instance status.cacheName = %v, want null", got)
+	}
+	if cont, err := rt.Synchronize(); err != nil || cont {
+		t.Errorf("Synchronize() = %v, %v, want false, nil", cont, err)
+	}
+}
+
+func Test_OwnerReferences(t *testing.T) {
+	newInstance := func(namespace string) Resource {
+		return newTestResource(
+			withObject(map[string]interface{}{
+				"apiVersion": "kro.run/v1alpha1",
+				"kind":       "WebApp",
+				"metadata": map[string]interface{}{
+					"name":      "myapp",
+					"namespace": namespace,
+					"uid":       "1234",
+				},
+			}),
+		)
+	}
+	newConfigMap := func(namespaced bool) Resource {
+		return newTestResource(
+			withNamespaced(namespaced),
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "${instance.metadata.name + '-config'}",
+				},
+				"data": map[string]interface{}{
+					"owner": "${instance.kind + '/' + instance.metadata.uid}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{"instance.metadata.name + '-config'"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.owner",
+						Expressions:          []string{"instance.kind + '/' + instance.metadata.uid"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+			}),
+		)
+	}
+	wantOwner := metav1.OwnerReference{
+		APIVersion: "kro.run/v1alpha1",
+		Kind:       "WebApp",
+		Name:       "myapp",
+		UID:        "1234",
+		Controller: &[]bool{true}[0],
+	}
+
+	tests := []struct {
+		name       string
+		instance   Resource
+		resource   Resource
+		opts       []Option
+		wantOwners []metav1.OwnerReference
+	}{
+		{
+			name:     "owner references are not injected by default",
+			instance: newInstance("default"),
+			resource: newConfigMap(true),
+		},
+		{
+			name:       "owner reference injected",
+			instance:   newInstance("default"),
+			resource:   newConfigMap(true),
+			opts:       []Option{WithOwnerReferences()},
+			wantOwners: []metav1.OwnerReference{wantOwner},
+		},
+		{
+			name:     "cluster scoped resource with namespaced instance",
+			instance: newInstance("default"),
+			resource: newConfigMap(false),
+			opts:     []Option{WithOwnerReferences()},
+		},
+		{
+			name:       "cluster scoped resource with cluster scoped instance",
+			instance:   newInstance(""),
+			resource:   newConfigMap(false),
+			opts:       []Option{WithOwnerReferences()},
+			wantOwners: []metav1.OwnerReference{wantOwner},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := NewResourceGraphDefinitionRuntime(
+				tt.instance,
+				map[string]Resource{"configmap": tt.resource},
+				[]string{"configmap"},
+				tt.opts...,
+			)
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+
+			// GetResource is called on every reconciliation, make sure the
+			// owner reference is only injected once.
+			rt.GetResource("configmap")
+			obj, state := rt.GetResource("configmap")
+			if state != ResourceStateResolved {
+				t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+			}
+			if !reflect.DeepEqual(obj.GetOwnerReferences(), tt.wantOwners) {
+				t.Errorf("ownerReferences = %v, want %v", obj.GetOwnerReferences(), tt.wantOwners)
+			}
+			if obj.GetName() != "myapp-config" {
+				t.Errorf("name = %q, want %q", obj.GetName(), "myapp-config")
+			}
+			if owner := obj.Object["data"].(map[string]interface{})["owner"]; owner != "WebApp/1234" {
+				t.Errorf("data.owner = %v, want %q", owner, "WebApp/1234")
+			}
+		})
+	}
+}
+
+func Test_LazyExpressions(t *testing.T) {
+	newConsumer := func(expressions ...string) Resource {
+		var variables []*variable.ResourceField
+		data := map[string]interface{}{}
+		for i, expr := range expressions {
+			data[fmt.Sprintf("field%d", i)] = "${" + expr + "}"
+			variables = append(variables, &variable.ResourceField{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 fmt.Sprintf("data.field%d", i),
+					Expressions:          []string{expr},
+					StandaloneExpression: true,
+				},
+				Kind:         variable.ResourceVariableKindDynamic,
+				Dependencies: []string{"source"},
+			})
+		}
+		return newTestResource(
+			withObject(map[string]interface{}{"data": data}),
+			withDependencies([]string{"source"}),
+			withVariables(variables),
+		)
+	}
+
+	rt, err := NewResourceGraphDefinitionRuntime(
+		newTestResource(),
+		map[string]Resource{
+			"source":   newTestResource(),
+			"included": newConsumer("source.data.shared"),
+			"ignored":  newConsumer("source.data.shared", "source.data.expensive"),
+		},
+		[]string{"source", "included", "ignored"},
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	rt.IgnoreResource("ignored")
+	rt.SetResource("source", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"shared": "a", "expensive": "b"},
+	}})
+	rt.SetResource("included", &unstructured.Unstructured{Object: map[string]interface{}{}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	if !rt.expressionsCache["source.data.shared"].Resolved {
+		t.Error("expression used by an included resource should be evaluated")
+	}
+	if rt.expressionsCache["source.data.expensive"].Resolved {
+		t.Error("expression only used by an ignored resource should not be evaluated")
+	}
+	if cont, err := rt.Synchronize(); err != nil || cont {
+		t.Errorf("Synchronize() = %v, %v, want false, nil", cont, err)
+	}
+}
+
+func Test_newEvalContext(t *testing.T) {
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: newTestResource(
+			withObject(map[string]interface{}{
+				"spec": map[string]interface{}{"name": "myapp"},
+			}),
+		),
+	}
+
+	ctx := rt.newEvalContext()
+	want := map[string]interface{}{
+		"apiVersion": "",
+		"kind":       "",
+		"metadata": map[string]interface{}{
+			"name":        "",
+			"namespace":   "",
+			"uid":         "",
+			"labels":      map[string]interface{}{},
+			"annotations": map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(ctx["instance"], want) {
+		t.Errorf("newEvalContext()[instance] = %v, want %v", ctx["instance"], want)
+	}
+}
+
+func Test_ResourceGroupContext(t *testing.T) {
+	resources := map[string]Resource{
+		"dep": newTestResource(),
+		"configmap": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "${resourceGroup.name + '-' + schema.spec.name}",
+				},
+				"data": map[string]interface{}{
+					"provenance": "${resourceGroup.version + '/' + dep.spec.value}",
+				},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "metadata.name",
+						Expressions:          []string{"resourceGroup.name + '-' + schema.spec.name"},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				},
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.provenance",
+						Expressions:          []string{"resourceGroup.version + '/' + dep.spec.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		),
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"name": "app"},
+	}))
+
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"dep", "configmap"},
+		WithResourceGroup("webapp", "v1alpha1"))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": "ready"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	obj, state := rt.GetResource("configmap")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+	}
+	if got := obj.GetName(); got != "webapp-app" {
+		t.Errorf("static expression = %q, want %q", got, "webapp-app")
+	}
+	if got := obj.Object["data"].(map[string]interface{})["provenance"]; got != "v1alpha1/ready" {
+		t.Errorf("dynamic expression = %q, want %q", got, "v1alpha1/ready")
+	}
+}
+
+type mockResource struct {
+	gvr              schema.GroupVersionResource
+	variables        []*variable.ResourceField
+	dependencies     []string
+	readinessDeps    []string
+	readyExpressions []string
+	readyMessages    map[string]string
+	deletionPolicy   string
+	priority         string
+	conditions       []string
+	topLevelFields   []string
+	namespaced       bool
+	skipReadiness    bool
+	ignoreUpdateErrs bool
+	obj              *unstructured.Unstructured
+}
+
+func newMockResource() *mockResource {
+	return &mockResource{
+		obj: &unstructured.Unstructured{
+			Object: make(map[string]interface{}),
+		},
+	}
+}
+
+func (m *mockResource) GetGroupVersionResource() schema.GroupVersionResource {
+	return m.gvr
+}
+
+func (m *mockResource) GetVariables() []*variable.ResourceField {
+	return m.variables
+}
+
+func (m *mockResource) GetDependencies() []string {
+	return m.dependencies
+}
+
+func (m *mockResource) GetReadinessDependencies() []string {
+	return m.readinessDeps
+}
+
+func (m *mockResource) GetReadyWhenExpressions() []string {
+	return m.readyExpressions
+}
+
+func (m *mockResource) GetReadyWhenMessages() map[string]string {
+	return m.readyMessages
+}
+
+func (m *mockResource) GetDeletionPolicy() string {
+	return m.deletionPolicy
+}
+
+func (m *mockResource) GetPriority() string {
+	return m.priority
+}
+
+func (m *mockResource) GetIncludeWhenExpressions() []string {
+	return m.conditions
+}
 
 func (m *mockResource) GetTopLevelFields() []string {
 	return m.topLevelFields
@@ -2652,6 +3657,14 @@ func (m *mockResource) IsNamespaced() bool {
 	return m.namespaced
 }
 
+func (m *mockResource) GetSkipReadiness() bool {
+	return m.skipReadiness
+}
+
+func (m *mockResource) GetIgnoreUpdateErrors() bool {
+	return m.ignoreUpdateErrs
+}
+
 func (m *mockResource) Unstructured() *unstructured.Unstructured {
 	return m.obj
 }
@@ -2680,12 +3693,42 @@ func withDependencies(deps []string) mockResourceOption {
 	}
 }
 
+func withReadinessDependencies(deps []string) mockResourceOption {
+	return func(m *mockResource) {
+		m.readinessDeps = deps
+	}
+}
+
 func withReadyExpressions(exprs []string) mockResourceOption {
 	return func(m *mockResource) {
 		m.readyExpressions = exprs
 	}
 }
 
+func withReadyMessages(messages map[string]string) mockResourceOption {
+	return func(m *mockResource) {
+		m.readyMessages = messages
+	}
+}
+
+func withDeletionPolicy(policy string) mockResourceOption {
+	return func(m *mockResource) {
+		m.deletionPolicy = policy
+	}
+}
+
+func withSkipReadiness() mockResourceOption {
+	return func(m *mockResource) {
+		m.skipReadiness = true
+	}
+}
+
+func withPriority(priority string) mockResourceOption {
+	return func(m *mockResource) {
+		m.priority = priority
+	}
+}
+
 func withConditions(conditions []string) mockResourceOption {
 	return func(m *mockResource) {
 		m.conditions = conditions
@@ -2698,11 +3741,11 @@ func withTopLevelFields(fields []string) mockResourceOption {
 	}
 }
 
-/* func withNamespaced(namespaced bool) mockResourceOption {
+func withNamespaced(namespaced bool) mockResourceOption {
 	return func(m *mockResource) {
 		m.namespaced = namespaced
 	}
-} */
+}
 
 func withObject(obj map[string]interface{}) mockResourceOption {
 	return func(m *mockResource) {
@@ -2717,3 +3760,223 @@ func newTestResource(opts ...mockResourceOption) *mockResource {
 	}
 	return r
 }
+
+func Test_SetResourceNil(t *testing.T) {
+	resources := map[string]Resource{
+		"dep": newTestResource(),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${dep.spec.value}"},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{"dep.spec.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	setDep := func(value string) {
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": value},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+	}
+	assertConsumer := func(wantState ResourceState, wantValue string) {
+		t.Helper()
+		obj, state := rt.GetResource("consumer")
+		if state != wantState {
+			t.Fatalf("GetResource() state = %v, want %v", state, wantState)
+		}
+		if wantState != ResourceStateResolved {
+			return
+		}
+		if got := obj.Object["data"].(map[string]interface{})["value"]; got != wantValue {
+			t.Errorf("GetResource() data.value = %v, want %v", got, wantValue)
+		}
+	}
+
+	setDep("first")
+	assertConsumer(ResourceStateResolved, "first")
+
+	// The dependency is deleted externally.
+	rt.SetResource("dep", nil)
+	if _, ok := rt.resolvedResources["dep"]; ok {
+		t.Error("SetResource(nil) should forget the resource")
+	}
+	if rt.expressionsCache["dep.spec.value"].Resolved {
+		t.Error("SetResource(nil) should invalidate the dependent expressions")
+	}
+	assertConsumer(ResourceStateWaitingOnDependencies, "")
+
+	// Requeue: nothing can be resolved until the dependency is back.
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	assertConsumer(ResourceStateWaitingOnDependencies, "")
+
+	// The dependency is recreated.
+	setDep("second")
+	assertConsumer(ResourceStateResolved, "second")
+}
+
+func Test_ReconcileContext(t *testing.T) {
+	const (
+		staticExpr  = "(has(reconcileContext.clusterName) ? reconcileContext.clusterName : 'local') + '-' + schema.spec.name"
+		dynamicExpr = "has(reconcileContext.reconcileID) ? reconcileContext.reconcileID : dep.spec.value"
+	)
+	newRuntime := func(opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"dep": newTestResource(),
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "${" + staticExpr + "}"},
+					"data":     map[string]interface{}{"value": "${" + dynamicExpr + "}"},
+				}),
+				withDependencies([]string{"dep"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "metadata.name",
+							Expressions:          []string{staticExpr},
+							StandaloneExpression: true,
+						},
+						Kind: variable.ResourceVariableKindStatic,
+					},
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "data.value",
+							Expressions:          []string{dynamicExpr},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"dep"},
+					},
+				}),
+			),
+		}
+		instance := newTestResource(withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"name": "app"},
+		}))
+		rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"dep", "consumer"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": "fallback"},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+
+	rt := newRuntime(WithReconcileContext(map[string]interface{}{
+		"clusterName": "eu-west",
+		"reconcileID": "1234",
+	}))
+	consumer, state := rt.GetResource("consumer")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+	}
+	if got := consumer.GetName(); got != "eu-west-app" {
+		t.Errorf("metadata.name = %q, want %q", got, "eu-west-app")
+	}
+	if got, _, _ := unstructured.NestedString(consumer.Object, "data", "value"); got != "1234" {
+		t.Errorf("data.value = %q, want %q", got, "1234")
+	}
+
+	// Without reconcile context, the variable is an empty map.
+	rt = newRuntime()
+	if _, value, _ := rt.ExpressionState(staticExpr); value != "local-app" {
+		t.Errorf("%s = %v, want %q", staticExpr, value, "local-app")
+	}
+	if _, value, _ := rt.ExpressionState(dynamicExpr); value != "fallback" {
+		t.Errorf("%s = %v, want %q", dynamicExpr, value, "fallback")
+	}
+}
+
+func Test_ClusterInfo(t *testing.T) {
+	const (
+		staticExpr  = "schema.spec.name + '-' + (has(kroCluster.region) ? kroCluster.region : 'local')"
+		dynamicExpr = "has(kroCluster.provider) ? kroCluster.provider + '/' + dep.spec.value : dep.spec.value"
+	)
+	newRuntime := func(opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		resources := map[string]Resource{
+			"dep": newTestResource(),
+			"consumer": newTestResource(
+				withObject(map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "${" + staticExpr + "}"},
+					"data":     map[string]interface{}{"value": "${" + dynamicExpr + "}"},
+				}),
+				withDependencies([]string{"dep"}),
+				withVariables([]*variable.ResourceField{
+					staticField("metadata.name", staticExpr),
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "data.value",
+							Expressions:          []string{dynamicExpr},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{"dep"},
+					},
+				}),
+			),
+		}
+		instance := newTestResource(withObject(map[string]interface{}{
+			"spec": map[string]interface{}{"name": "app"},
+		}))
+		rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"dep", "consumer"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"value": "bucket"},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+
+	rt := newRuntime(WithClusterInfo(map[string]interface{}{
+		"name":     "prod-1",
+		"region":   "eu-west-1",
+		"provider": "aws",
+	}))
+	consumer, state := rt.GetResource("consumer")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+	}
+	if got := consumer.GetName(); got != "app-eu-west-1" {
+		t.Errorf("metadata.name = %q, want %q", got, "app-eu-west-1")
+	}
+	if got, _, _ := unstructured.NestedString(consumer.Object, "data", "value"); got != "aws/bucket" {
+		t.Errorf("data.value = %q, want %q", got, "aws/bucket")
+	}
+
+	// Without cluster info, the variable is an empty map.
+	rt = newRuntime()
+	if _, value, _ := rt.ExpressionState(staticExpr); value != "app-local" {
+		t.Errorf("%s = %v, want %q", staticExpr, value, "app-local")
+	}
+	if _, value, _ := rt.ExpressionState(dynamicExpr); value != "bucket" {
+		t.Errorf("%s = %v, want %q", dynamicExpr, value, "bucket")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/controller/instance/delta"
+	"github.com/kro-run/kro/pkg/runtime/resolver"
+)
+
+// OperationType is the type of API operation the controller would perform
+// on a resource.
+type OperationType string
+
+const (
+	// OperationCreate means that the resource isn't observed yet and would
+	// be created.
+	OperationCreate OperationType = "Create"
+	// OperationUpdate means that the observed resource differs from its
+	// desired state and would be updated.
+	OperationUpdate OperationType = "Update"
+	// OperationUnchanged means that the observed resource matches its
+	// desired state.
+	OperationUnchanged OperationType = "Unchanged"
+	// OperationSkip means that the resource is ignored by its conditions.
+	OperationSkip OperationType = "Skip"
+	// OperationPending means that the resource can't be rendered yet,
+	// because it's waiting on its dependencies.
+	OperationPending OperationType = "Pending"
+)
+
+// PlannedOperation is the API operation the controller would perform on a
+// resource.
+type PlannedOperation struct {
+	// ID is the id of the resource.
+	ID string
+	// Operation is the type of the operation.
+	Operation OperationType
+	// Hash is the hash of the rendered resource template, see ResourceHash.
+	// It is empty for skipped and pending resources.
+	Hash string
+	// Object is the desired state of the resource, see RenderResource. It is
+	// nil for skipped and pending resources.
+	Object *unstructured.Unstructured
+	// Diff holds the differences between the desired and observed states of
+	// updated resources.
+	Diff []delta.Difference
+}
+
+// SimulateApply reports, in topological order, the API operation that the
+// controller would perform on every resource given the observed objects set
+// with SetResource, without touching the cluster. e.g to preview the changes
+// of a new instance spec.
+func (rt *ResourceGraphDefinitionRuntime) SimulateApply() ([]PlannedOperation, error) {
+	operations := make([]PlannedOperation, 0, len(rt.topologicalOrder))
+	for _, id := range rt.topologicalOrder {
+		operation := PlannedOperation{ID: id}
+		if rt.ignoredByConditionsResources[id] {
+			operation.Operation = OperationSkip
+			operations = append(operations, operation)
+			continue
+		}
+		desired, state := rt.RenderResource(id)
+		if state != ResourceStateResolved {
+			operation.Operation = OperationPending
+			operations = append(operations, operation)
+			continue
+		}
+
+		hash, err := rt.ResourceHash(id)
+		if err != nil {
+			return nil, err
+		}
+		operation.Hash = hash
+		operation.Object = desired
+
+		observed, ok := rt.resolvedResources[id]
+		if !ok || observed == nil {
+			operation.Operation = OperationCreate
+			operations = append(operations, operation)
+			continue
+		}
+		diff, err := delta.Compare(desired, observed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare resource %s: %w", id, err)
+		}
+		if len(diff) == 0 {
+			operation.Operation = OperationUnchanged
+		} else {
+			operation.Operation = OperationUpdate
+			operation.Diff = diff
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// ResourceDiff returns the differences between the desired state of the
+// resource (see RenderResource) and its observed object. It fails with
+// ErrResourceNotResolved if the resource wasn't observed yet.
+func (rt *ResourceGraphDefinitionRuntime) ResourceDiff(id string) ([]delta.Difference, error) {
+	return rt.ResourceDiffWithOptions(id, nil)
+}
+
+// ResourceDiffWithOptions is like ResourceDiff, but ignores the fields at the
+// given paths (and their children) on top of the server managed metadata,
+// e.g "spec.replicas" when it's owned by an autoscaler. The paths use the
+// field descriptors syntax, e.g "spec.containers[0].image" or
+// `metadata.annotations["example.com/owner"]`.
+func (rt *ResourceGraphDefinitionRuntime) ResourceDiffWithOptions(id string, ignorePaths []string) ([]delta.Difference, error) {
+	observed, err := rt.MustGetResolved(id)
+	if err != nil {
+		return nil, err
+	}
+	desired, state := rt.RenderResource(id)
+	if state != ResourceStateResolved {
+		return nil, fmt.Errorf("resource %s can't be rendered: %s", id, state)
+	}
+	if len(ignorePaths) > 0 {
+		desired = &unstructured.Unstructured{Object: deepCopyValue(desired.Object).(map[string]interface{})}
+		observed = &unstructured.Unstructured{Object: deepCopyValue(observed.Object).(map[string]interface{})}
+		desiredResolver := resolver.NewResolver(desired.Object, nil)
+		observedResolver := resolver.NewResolver(observed.Object, nil)
+		for _, path := range ignorePaths {
+			if err := desiredResolver.RemoveValueAtPath(path); err != nil {
+				return nil, fmt.Errorf("invalid ignored path %s: %w", path, err)
+			}
+			if err := observedResolver.RemoveValueAtPath(path); err != nil {
+				return nil, fmt.Errorf("invalid ignored path %s: %w", path, err)
+			}
+		}
+	}
+	return delta.Compare(desired, observed)
+}
+
+// ResourceHash returns the hex encoded SHA-256 hash of the rendered template
+// of the resource. The hash only changes when the values set by kro change,
+// not when fields are added to the observed object, e.g its status.
+func (rt *ResourceGraphDefinitionRuntime) ResourceHash(id string) (string, error) {
+	if _, ok := rt.resources[id]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownResource, id)
+	}
+	if !rt.canProcessResource(id) {
+		return "", fmt.Errorf("resource %s can't be rendered: %s", id, ResourceStateWaitingOnDependencies)
+	}
+	// encoding/json sorts the map keys, which makes the encoding stable.
+	data, err := json.Marshal(rt.resources[id].Unstructured().Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode resource %s: %w", id, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/controller/instance/delta"
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_SimulateApply(t *testing.T) {
+	newConfigMap := func(name, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": name},
+			"data":       map[string]interface{}{"value": value},
+		}
+	}
+	resources := map[string]Resource{
+		"created":   newTestResource(withObject(newConfigMap("created", "v1"))),
+		"unchanged": newTestResource(withObject(newConfigMap("unchanged", "v1"))),
+		"updated":   newTestResource(withObject(newConfigMap("updated", "v2"))),
+		"skipped":   newTestResource(withObject(newConfigMap("skipped", "v1"))),
+		"pending": newTestResource(
+			withObject(newConfigMap("pending", "${created.data.value}")),
+			withDependencies([]string{"created"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{"created.data.value"},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"created"},
+				},
+			}),
+		),
+	}
+	order := []string{"created", "unchanged", "updated", "skipped", "pending"}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	observedUnchanged := newConfigMap("unchanged", "v1")
+	observedUnchanged["metadata"].(map[string]interface{})["resourceVersion"] = "42"
+	rt.SetResource("unchanged", &unstructured.Unstructured{Object: observedUnchanged})
+	rt.SetResource("updated", &unstructured.Unstructured{Object: newConfigMap("updated", "v1")})
+	rt.IgnoreResource("skipped")
+
+	operations, err := rt.SimulateApply()
+	if err != nil {
+		t.Fatalf("SimulateApply() error = %v", err)
+	}
+
+	got := make(map[string]OperationType)
+	for _, op := range operations {
+		got[op.ID] = op.Operation
+		if (op.Hash == "") != (op.Operation == OperationSkip || op.Operation == OperationPending) {
+			t.Errorf("operation %s on %s has hash %q", op.Operation, op.ID, op.Hash)
+		}
+	}
+	want := map[string]OperationType{
+		"created":   OperationCreate,
+		"unchanged": OperationUnchanged,
+		"updated":   OperationUpdate,
+		"skipped":   OperationSkip,
+		"pending":   OperationPending,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SimulateApply() operations = %v, want %v", got, want)
+	}
+
+	for _, op := range operations {
+		if op.ID != "updated" {
+			continue
+		}
+		wantDiff := []delta.Difference{{Path: "data.value", Desired: "v2", Observed: "v1"}}
+		if !reflect.DeepEqual(op.Diff, wantDiff) {
+			t.Errorf("SimulateApply() diff of updated = %v, want %v", op.Diff, wantDiff)
+		}
+	}
+}
+
+func Test_ResourceHash(t *testing.T) {
+	resources := map[string]Resource{
+		"configmap": newTestResource(withObject(map[string]interface{}{
+			"data": map[string]interface{}{"key1": "value", "key2": "value"},
+		})),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"configmap"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	hash, err := rt.ResourceHash("configmap")
+	if err != nil {
+		t.Fatalf("ResourceHash() error = %v", err)
+	}
+	// Observing the resource, e.g with a status, doesn't change the hash.
+	rt.SetResource("configmap", &unstructured.Unstructured{Object: map[string]interface{}{
+		"data":   map[string]interface{}{"key1": "value", "key2": "value"},
+		"status": map[string]interface{}{"phase": "Ready"},
+	}})
+	if again, _ := rt.ResourceHash("configmap"); again != hash {
+		t.Errorf("ResourceHash() = %s after observing the resource, want %s", again, hash)
+	}
+
+	if _, err := rt.ResourceHash("unknown"); err == nil {
+		t.Errorf("ResourceHash() of an unknown resource error = nil, want error")
+	}
+}
+
+func Test_ResourceDiffWithOptions(t *testing.T) {
+	newDeployment := func(replicas int64, image, team string) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":   "deployment",
+				"labels": map[string]interface{}{"team": team},
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"containers": []interface{}{
+					map[string]interface{}{"name": "main", "image": image},
+				},
+			},
+		}
+	}
+	resources := map[string]Resource{
+		"deployment": newTestResource(withObject(newDeployment(1, "nginx:1.0", "blue"))),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"deployment"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("deployment", &unstructured.Unstructured{Object: newDeployment(5, "nginx:2.0", "green")})
+
+	diff, err := rt.ResourceDiff("deployment")
+	if err != nil {
+		t.Fatalf("ResourceDiff() error = %v", err)
+	}
+	if len(diff) != 3 {
+		t.Errorf("ResourceDiff() = %v, want 3 differences", diff)
+	}
+
+	diff, err = rt.ResourceDiffWithOptions("deployment", []string{"spec.replicas", "spec.containers[0].image"})
+	if err != nil {
+		t.Fatalf("ResourceDiffWithOptions() error = %v", err)
+	}
+	want := []delta.Difference{{Path: "metadata.labels.team", Desired: "blue", Observed: "green"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("ResourceDiffWithOptions() = %v, want %v", diff, want)
+	}
+
+	// Ignoring paths must not alter the observed or rendered objects.
+	if diff, _ := rt.ResourceDiff("deployment"); len(diff) != 3 {
+		t.Errorf("ResourceDiff() after ResourceDiffWithOptions() = %v, want 3 differences", diff)
+	}
+
+	if _, err := rt.ResourceDiffWithOptions("deployment", []string{"spec.containers["}); err == nil {
+		t.Errorf("ResourceDiffWithOptions() with an invalid path error = nil, want error")
+	}
+}
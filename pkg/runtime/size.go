@@ -0,0 +1,61 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+// ResolvedValueBytes returns an estimate of the memory, in bytes, held by the
+// resolved values of the expressions cache. It is meant to be used for
+// monitoring controllers managing many instances resolving large values
+// (e.g big configmaps or embedded files).
+//
+// The estimate only accounts for the data itself (string contents, numbers,
+// map keys...) and not for the Go runtime overhead of the containers.
+func (rt *ResourceGraphDefinitionRuntime) ResolvedValueBytes() int {
+	total := 0
+	for _, state := range rt.expressionsCache {
+		if state.Resolved {
+			total += valueSize(state.ResolvedValue)
+		}
+	}
+	return total
+}
+
+// valueSize returns an estimate of the size, in bytes, of a value produced
+// by krocel.GoNativeType.
+func valueSize(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case []interface{}:
+		size := 0
+		for _, elem := range v {
+			size += valueSize(elem)
+		}
+		return size
+	case map[string]interface{}:
+		size := 0
+		for key, value := range v {
+			size += len(key) + valueSize(value)
+		}
+		return size
+	default:
+		// int64, uint64, float64 and any other scalar.
+		return 8
+	}
+}
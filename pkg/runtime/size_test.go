@@ -0,0 +1,87 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ResolvedValueBytes(t *testing.T) {
+	tests := []struct {
+		name             string
+		expressionsCache map[string]*expressionEvaluationState
+		want             int
+	}{
+		{
+			name: "empty cache",
+			want: 0,
+		},
+		{
+			name: "scalars",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"schema.spec.name": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: "myapp",
+				},
+				"schema.spec.replicas": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: int64(3),
+				},
+				"schema.spec.enabled": {
+					Kind:          variable.ResourceVariableKindStatic,
+					Resolved:      true,
+					ResolvedValue: true,
+				},
+			},
+			want: 5 + 8 + 1,
+		},
+		{
+			name: "nested values",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"configmap.data": {
+					Kind:     variable.ResourceVariableKindDynamic,
+					Resolved: true,
+					ResolvedValue: map[string]interface{}{
+						"config.yaml": "key: value",
+						"files":       []interface{}{"a", "bc", nil},
+					},
+				},
+			},
+			want: len("config.yaml") + len("key: value") + len("files") + 1 + 2,
+		},
+		{
+			name: "unresolved values are ignored",
+			expressionsCache: map[string]*expressionEvaluationState{
+				"deployment.status.readyReplicas": {
+					Kind:          variable.ResourceVariableKindDynamic,
+					ResolvedValue: "stale",
+				},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &ResourceGraphDefinitionRuntime{expressionsCache: tt.expressionsCache}
+			if got := rt.ResolvedValueBytes(); got != tt.want {
+				t.Errorf("ResolvedValueBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
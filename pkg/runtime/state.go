@@ -80,4 +80,21 @@ type expressionEvaluationState struct {
 	// if the expression hasn't been resolved yet. The type of this value
 	// depends on the expression and could be any valid Go type.
 	ResolvedValue interface{}
+
+	// ResolvedToNull indicates that the expression couldn't be evaluated
+	// because one of its dependencies is ignored by conditions, and that it
+	// was resolved to null instead.
+	ResolvedToNull bool
+
+	// IncompleteDataAttempts counts the consecutive evaluations that failed
+	// because the data of the dependencies was incomplete.
+	IncompleteDataAttempts int
+
+	// Failed indicates that the expression is in a terminal error state,
+	// either because of a hard evaluation error or because its data was
+	// still incomplete after the configured number of attempts.
+	Failed bool
+
+	// RequiredFeatures are the feature flags the expression opted in to.
+	RequiredFeatures []string
 }
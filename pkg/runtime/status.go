@@ -0,0 +1,65 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kro-run/kro/pkg/runtime/resolver"
+)
+
+// isStatusPath returns true if the path is a field of the instance status.
+func isStatusPath(path string) bool {
+	return strings.HasPrefix(path, "status.")
+}
+
+// ManagedStatusPaths returns the paths of the instance status fields owned by
+// the runtime, a.k.a the fields set by the instance expressions, sorted.
+func (rt *ResourceGraphDefinitionRuntime) ManagedStatusPaths() []string {
+	var paths []string
+	for _, variable := range rt.instance.GetVariables() {
+		if isStatusPath(variable.Path) && !slices.Contains(paths, variable.Path) {
+			paths = append(paths, variable.Path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// ManagedStatus returns the instance status restricted to the fields owned by
+// the runtime. Writing it with a merge patch, instead of the whole status,
+// preserves the fields written concurrently by other controllers. Managed
+// fields that aren't resolved yet keep the value of the observed instance, if
+// any, which is sent back as is.
+func (rt *ResourceGraphDefinitionRuntime) ManagedStatus() (map[string]interface{}, error) {
+	source := resolver.NewResolver(rt.instance.Unstructured().Object, nil)
+	managed := map[string]interface{}{}
+	target := resolver.NewResolver(managed, nil)
+	for _, path := range rt.ManagedStatusPaths() {
+		value, err := source.ValueAtPath(path)
+		if err != nil {
+			continue
+		}
+		if err := target.UpsertValueAtPath(path, value); err != nil {
+			return nil, fmt.Errorf("failed to set value at path %s: %w", path, err)
+		}
+	}
+	status, _ := managed["status"].(map[string]interface{})
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	return status, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ManagedStatus(t *testing.T) {
+	// The instance, as read from the cluster, has a status field written by
+	// another controller.
+	instance := newTestResource(
+		withObject(map[string]interface{}{
+			"status": map[string]interface{}{
+				"foreign": "owned by someone else",
+				"endpoint": map[string]interface{}{
+					"port":     int64(8080),
+					"protocol": "TCP",
+				},
+			},
+		}),
+		withVariables([]*variable.ResourceField{
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.ready",
+					Expressions:          []string{"expr1"},
+					StandaloneExpression: true,
+				},
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.endpoint.host",
+					Expressions:          []string{"expr2"},
+					StandaloneExpression: true,
+				},
+			},
+			{
+				FieldDescriptor: variable.FieldDescriptor{
+					Path:                 "status.pending",
+					Expressions:          []string{"expr3"},
+					StandaloneExpression: true,
+				},
+			},
+		}),
+	)
+	rt := &ResourceGraphDefinitionRuntime{
+		instance: instance,
+		expressionsCache: map[string]*expressionEvaluationState{
+			"expr1": {Expression: "expr1", Resolved: true, ResolvedValue: true},
+			"expr2": {Expression: "expr2", Resolved: true, ResolvedValue: "example.com"},
+			"expr3": {Expression: "expr3"},
+		},
+	}
+
+	wantPaths := []string{"status.endpoint.host", "status.pending", "status.ready"}
+	if got := rt.ManagedStatusPaths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("ManagedStatusPaths() = %v, want %v", got, wantPaths)
+	}
+
+	if err := rt.evaluateInstanceStatuses(); err != nil {
+		t.Fatalf("evaluateInstanceStatuses() error = %v", err)
+	}
+
+	// The foreign fields survive the status update.
+	wantObj := map[string]interface{}{
+		"foreign": "owned by someone else",
+		"ready":   true,
+		"endpoint": map[string]interface{}{
+			"host":     "example.com",
+			"port":     int64(8080),
+			"protocol": "TCP",
+		},
+	}
+	if got := rt.instance.Unstructured().Object["status"]; !reflect.DeepEqual(got, wantObj) {
+		t.Errorf("instance status = %v, want %v", got, wantObj)
+	}
+
+	// The managed status only holds the resolved fields owned by kro.
+	got, err := rt.ManagedStatus()
+	if err != nil {
+		t.Fatalf("ManagedStatus() error = %v", err)
+	}
+	wantManaged := map[string]interface{}{
+		"ready": true,
+		"endpoint": map[string]interface{}{
+			"host": "example.com",
+		},
+	}
+	if !reflect.DeepEqual(got, wantManaged) {
+		t.Errorf("ManagedStatus() = %v, want %v", got, wantManaged)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import "context"
+
+// ResolvedEventType is the type of a ResolvedEvent.
+type ResolvedEventType string
+
+const (
+	// ResolvedEventExpression is emitted when a dynamic expression resolves.
+	ResolvedEventExpression ResolvedEventType = "ExpressionResolved"
+	// ResolvedEventResource is emitted when the fields of a resource are
+	// rendered, a.k.a the resource can be created or updated.
+	ResolvedEventResource ResolvedEventType = "ResourceResolved"
+	// ResolvedEventError is emitted when the synchronization fails. It is
+	// always the last event of the stream.
+	ResolvedEventError ResolvedEventType = "Error"
+)
+
+// ResolvedEvent is a resolution event emitted by SynchronizeStream.
+type ResolvedEvent struct {
+	// Type is the type of the event.
+	Type ResolvedEventType
+	// ResourceID is the id of the resolved resource, for resource events.
+	ResourceID string
+	// Expression and Value are the resolved expression and its value, for
+	// expression events.
+	Expression string
+	Value      interface{}
+	// Err is the synchronization error, for error events.
+	Err error
+}
+
+// SynchronizeStream runs a Synchronize pass, emitting the resolution events
+// as they happen, instead of only returning once every resource is resolved.
+// The controller can act on the resolved resources right away, e.g create
+// them, which matters for large graphs.
+//
+// The channel is closed when the pass completes, fails (the error being
+// reported by a last ResolvedEventError event) or when the context is
+// cancelled. The events must be consumed for the pass to make progress, and
+// the runtime must not be used until the channel is closed.
+func (rt *ResourceGraphDefinitionRuntime) SynchronizeStream(ctx context.Context) <-chan ResolvedEvent {
+	events := make(chan ResolvedEvent)
+	send := func(event ResolvedEvent) error {
+		select {
+		case events <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	go func() {
+		defer close(events)
+
+		// Resources are rendered again on every pass, only report them once.
+		emittedResources := make(map[string]bool)
+		rt.resolvedEventHook = func(event ResolvedEvent) error {
+			if event.Type == ResolvedEventResource {
+				if emittedResources[event.ResourceID] {
+					return nil
+				}
+				emittedResources[event.ResourceID] = true
+			}
+			return send(event)
+		}
+		defer func() { rt.resolvedEventHook = nil }()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := rt.Synchronize(); err != nil && ctx.Err() == nil {
+			_ = send(ResolvedEvent{Type: ResolvedEventError, Err: err})
+		}
+	}()
+	return events
+}
+
+// emitResolvedEvent passes the event to the ongoing SynchronizeStream call,
+// if any. The returned error aborts the pass, when the stream is cancelled.
+func (rt *ResourceGraphDefinitionRuntime) emitResolvedEvent(event ResolvedEvent) error {
+	if rt.resolvedEventHook == nil {
+		return nil
+	}
+	return rt.resolvedEventHook(event)
+}
+
+// emitExpressionResolved emits the resolution event of the expression.
+func (rt *ResourceGraphDefinitionRuntime) emitExpressionResolved(state *expressionEvaluationState) error {
+	return rt.emitResolvedEvent(ResolvedEvent{
+		Type:       ResolvedEventExpression,
+		Expression: state.Expression,
+		Value:      state.ResolvedValue,
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// newConsumersRuntime returns a runtime with an observed "dep" resource, and a
+// consumer resource per expression, each one depending on "dep".
+func newConsumersRuntime(t *testing.T, exprs ...string) *ResourceGraphDefinitionRuntime {
+	t.Helper()
+	resources := map[string]Resource{"dep": newTestResource()}
+	order := []string{"dep"}
+	for i, expr := range exprs {
+		id := "consumer" + string(rune('A'+i))
+		resources[id] = newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${" + expr + "}"},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{expr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		)
+		order = append(order, id)
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": "hello", "count": int64(2)},
+	}})
+	return rt
+}
+
+// collectEvents consumes the stream until it's closed.
+func collectEvents(t *testing.T, events <-chan ResolvedEvent) []ResolvedEvent {
+	t.Helper()
+	var collected []ResolvedEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return collected
+			}
+			collected = append(collected, event)
+		case <-timeout:
+			t.Fatalf("stream not closed, got %v", collected)
+		}
+	}
+}
+
+func Test_SynchronizeStream(t *testing.T) {
+	rt := newConsumersRuntime(t, "dep.spec.value", "dep.spec.count * 2")
+	events := collectEvents(t, rt.SynchronizeStream(context.Background()))
+
+	gotExpressions := make(map[string]interface{})
+	gotResources := make(map[string]int)
+	for _, event := range events {
+		switch event.Type {
+		case ResolvedEventExpression:
+			gotExpressions[event.Expression] = event.Value
+		case ResolvedEventResource:
+			gotResources[event.ResourceID]++
+		default:
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+	if gotExpressions["dep.spec.value"] != "hello" || gotExpressions["dep.spec.count * 2"] != int64(4) {
+		t.Errorf("expression events = %v", gotExpressions)
+	}
+	for _, id := range []string{"dep", "consumerA", "consumerB"} {
+		if gotResources[id] != 1 {
+			t.Errorf("got %d resource events for %s, want 1", gotResources[id], id)
+		}
+	}
+
+	// The runtime is usable again once the stream is closed.
+	if value := rt.expressionsCache["dep.spec.value"].ResolvedValue; value != "hello" {
+		t.Errorf("expression dep.spec.value = %v, want hello", value)
+	}
+	if rt.resolvedEventHook != nil {
+		t.Errorf("resolvedEventHook is still set after the stream is closed")
+	}
+}
+
+func Test_SynchronizeStream_Error(t *testing.T) {
+	rt := newConsumersRuntime(t, "dep.spec.value + 1")
+	events := collectEvents(t, rt.SynchronizeStream(context.Background()))
+	if len(events) == 0 {
+		t.Fatalf("got no events, want an error event")
+	}
+	last := events[len(events)-1]
+	if last.Type != ResolvedEventError || last.Err == nil {
+		t.Errorf("last event = %+v, want an error event", last)
+	}
+}
+
+func Test_SynchronizeStream_Cancel(t *testing.T) {
+	rt := newConsumersRuntime(t, "dep.spec.value", "dep.spec.count * 2")
+	ctx, cancel := context.WithCancel(context.Background())
+	events := rt.SynchronizeStream(ctx)
+
+	// Stop consuming after the first event, the stream is closed anyway.
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no event received")
+	}
+	cancel()
+	collectEvents(t, events)
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// Test_ScaleSubresourceFields checks that the fields backing the scale
+// subresource (spec.replicas, status.replicas and the status label
+// selector) of the observed resources can be read by the expressions, for
+// built-in kinds, typed or not, and custom resources.
+func Test_ScaleSubresourceFields(t *testing.T) {
+	exprs := map[string]interface{}{
+		"deployment.status.replicas":                                             int64(3),
+		"deployment.spec.replicas - deployment.status.readyReplicas":             int64(1),
+		"deployment.status.replicas >= deployment.spec.replicas":                 true,
+		"worker.spec.replicas":                                                   int64(5),
+		"worker.status.replicas":                                                 int64(4),
+		"worker.status.selector":                                                 "app=worker",
+		"has(worker.status.desiredReplicas) ? worker.status.desiredReplicas : 0": int64(0),
+	}
+	field := func(path, expr string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"deployment", "worker"},
+		}
+	}
+	newRuntime := func(t *testing.T, opts ...Option) *ResourceGraphDefinitionRuntime {
+		t.Helper()
+		var fields []*variable.ResourceField
+		data := map[string]interface{}{}
+		i := 0
+		for expr := range exprs {
+			key := fmt.Sprintf("field%d", i)
+			data[key] = "${" + expr + "}"
+			fields = append(fields, field("data."+key, expr))
+			i++
+		}
+		resources := map[string]Resource{
+			"deployment": newTestResource(withReadyExpressions([]string{
+				"deployment.status.readyReplicas == deployment.spec.replicas",
+			})),
+			"worker": newTestResource(withReadyExpressions([]string{
+				"worker.status.replicas == worker.spec.replicas",
+			})),
+			"autoscaler": newTestResource(
+				withObject(map[string]interface{}{"data": data}),
+				withDependencies([]string{"deployment", "worker"}),
+				withVariables(fields),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"deployment", "worker", "autoscaler"}, opts...)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		rt.SetResource("deployment", &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "app"},
+			"spec":       map[string]interface{}{"replicas": int64(3)},
+			"status": map[string]interface{}{
+				"replicas":      int64(3),
+				"readyReplicas": int64(2),
+			},
+		}})
+		rt.SetResource("worker", &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Worker",
+			"metadata":   map[string]interface{}{"name": "worker"},
+			"spec":       map[string]interface{}{"replicas": int64(5)},
+			"status": map[string]interface{}{
+				"replicas": int64(4),
+				"selector": "app=worker",
+			},
+		}})
+		if _, err := rt.Synchronize(); err != nil {
+			t.Fatalf("Synchronize() error = %v", err)
+		}
+		return rt
+	}
+
+	for name, opts := range map[string][]Option{
+		"unstructured": nil,
+		"typed":        {WithTypedResource(appsv1.SchemeGroupVersion.WithKind("Deployment"), appsv1.Deployment{})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rt := newRuntime(t, opts...)
+			for expr, want := range exprs {
+				resolved, value, err := rt.ExpressionState(expr)
+				if err != nil || !resolved {
+					t.Errorf("ExpressionState(%q) = %v, %v, want resolved", expr, resolved, err)
+					continue
+				}
+				if value != want {
+					t.Errorf("%s = %v (%T), want %v (%T)", expr, value, value, want, want)
+				}
+			}
+
+			for id, wantReady := range map[string]bool{"deployment": false, "worker": false} {
+				ready, _, err := rt.IsResourceReady(id)
+				if err != nil {
+					t.Fatalf("IsResourceReady(%q) error = %v", id, err)
+				}
+				if ready != wantReady {
+					t.Errorf("IsResourceReady(%q) = %v, want %v", id, ready, wantReady)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TopologicalLevels returns the resources grouped by topological level: the
+// first level holds the resources without dependencies, and every other
+// level the resources depending on the previous levels. Resources of the same
+// level are independent, and sorted by id.
+func (rt *ResourceGraphDefinitionRuntime) TopologicalLevels() [][]string {
+	return groupByTopologicalLevel(rt.topologicalOrder, rt.resources)
+}
+
+// CreationOrder returns the resources in the order to create them: by
+// topological level, and within a level by decreasing priority (see
+// ResourceDescriptor.GetPriority), then by id. The priorities are evaluated
+// against the current state of the runtime, those that can't be evaluated
+// yet, e.g because they read resources that aren't resolved, count as 0.
+func (rt *ResourceGraphDefinitionRuntime) CreationOrder() []string {
+	order := make([]string, 0, len(rt.topologicalOrder))
+	for _, level := range rt.TopologicalLevels() {
+		priorities := make(map[string]int64, len(level))
+		for _, id := range level {
+			priorities[id] = rt.creationPriority(id)
+		}
+		// The levels are sorted by id already.
+		slices.SortStableFunc(level, func(a, b string) int {
+			return cmp.Compare(priorities[b], priorities[a])
+		})
+		order = append(order, level...)
+	}
+	return order
+}
+
+// creationPriority returns the evaluated priority of the resource, or 0 if it
+// can't be evaluated.
+func (rt *ResourceGraphDefinitionRuntime) creationPriority(id string) int64 {
+	priority := rt.resources[id].GetPriority()
+	if strings.Contains(priority, "${") {
+		rendered, err := rt.renderWithCurrentState(priority)
+		if err != nil {
+			return 0
+		}
+		priority = rendered
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(priority), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// ReconcileSubgraph returns the target resource and its transitive
+// dependencies, in topological order: the minimal set of resources to
+// reconcile for the target to be resolved. It fails with ErrUnknownResource
+// if the target isn't part of the graph.
+func (rt *ResourceGraphDefinitionRuntime) ReconcileSubgraph(target string) ([]string, error) {
+	if _, ok := rt.resources[target]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, target)
+	}
+
+	closure := map[string]bool{}
+	pending := []string{target}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if closure[id] {
+			continue
+		}
+		closure[id] = true
+		if resource, ok := rt.resources[id]; ok {
+			pending = append(pending, resource.GetDependencies()...)
+		}
+	}
+
+	subgraph := make([]string, 0, len(closure))
+	for _, id := range rt.topologicalOrder {
+		if closure[id] {
+			subgraph = append(subgraph, id)
+		}
+	}
+	return subgraph, nil
+}
+
+// CriticalPath returns the longest dependency chain of the graph, from a
+// resource without dependencies to the resource at the end of the chain. Its
+// length is the minimum number of steps to resolve the whole graph, since
+// every resource waits for its dependencies: breaking its dependencies is
+// what parallelizes the reconciliation. When several chains are the longest,
+// the one ending with the smallest resource id is returned, and within a
+// chain ties are broken by smallest dependency id.
+func (rt *ResourceGraphDefinitionRuntime) CriticalPath() []string {
+	// The topological order lists the dependencies first, so the longest
+	// chain ending with every resource can be computed in a single pass.
+	lengths := make(map[string]int, len(rt.topologicalOrder))
+	previous := make(map[string]string, len(rt.topologicalOrder))
+	for _, id := range rt.topologicalOrder {
+		lengths[id] = 1
+		deps := slices.Clone(rt.resources[id].GetDependencies())
+		slices.Sort(deps)
+		for _, dep := range deps {
+			length, ok := lengths[dep]
+			if ok && length+1 > lengths[id] {
+				lengths[id] = length + 1
+				previous[id] = dep
+			}
+		}
+	}
+
+	end := ""
+	for id, length := range lengths {
+		if end == "" || length > lengths[end] || (length == lengths[end] && id < end) {
+			end = id
+		}
+	}
+	if end == "" {
+		return nil
+	}
+	path := []string{end}
+	for id, ok := previous[end]; ok; id, ok = previous[id] {
+		path = append(path, id)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// sortTopologicalOrder returns the given topological order, with the
+// resources of the same level sorted by id. Independent resources can be
+// ordered in many ways, depending on how the graph was built, this makes
+// sure the rendering order is reproducible.
+func sortTopologicalOrder(order []string, resources map[string]Resource) []string {
+	sorted := make([]string, 0, len(order))
+	for _, level := range groupByTopologicalLevel(order, resources) {
+		sorted = append(sorted, level...)
+	}
+	return sorted
+}
+
+// groupByTopologicalLevel groups the resources of the given order by
+// topological level. The level of a resource is one more than the highest
+// level of its dependencies, dependencies outside of the order are ignored.
+func groupByTopologicalLevel(order []string, resources map[string]Resource) [][]string {
+	levels := make(map[string]int, len(order))
+	var levelOf func(id string, visiting map[string]bool) int
+	levelOf = func(id string, visiting map[string]bool) int {
+		if level, ok := levels[id]; ok {
+			return level
+		}
+		level := 0
+		if resource, ok := resources[id]; ok && !visiting[id] {
+			visiting[id] = true
+			// The readiness dependencies need to be processed first too.
+			deps := slices.Concat(resource.GetDependencies(), resource.GetReadinessDependencies())
+			for _, dep := range deps {
+				if slices.Contains(order, dep) {
+					level = max(level, levelOf(dep, visiting)+1)
+				}
+			}
+			delete(visiting, id)
+		}
+		levels[id] = level
+		return level
+	}
+
+	var grouped [][]string
+	for _, id := range order {
+		level := levelOf(id, map[string]bool{})
+		for len(grouped) <= level {
+			grouped = append(grouped, nil)
+		}
+		grouped[level] = append(grouped[level], id)
+	}
+	for _, level := range grouped {
+		slices.Sort(level)
+	}
+	return grouped
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_TopologicalLevels(t *testing.T) {
+	resources := map[string]Resource{
+		"vpc":          newTestResource(),
+		"bucket":       newTestResource(),
+		"subnetB":      newTestResource(withDependencies([]string{"vpc"})),
+		"subnetA":      newTestResource(withDependencies([]string{"vpc"})),
+		"cluster":      newTestResource(withDependencies([]string{"subnetA", "subnetB"})),
+		"bucketPolicy": newTestResource(withDependencies([]string{"bucket"})),
+	}
+	wantOrder := []string{"bucket", "vpc", "bucketPolicy", "subnetA", "subnetB", "cluster"}
+	wantLevels := [][]string{
+		{"bucket", "vpc"},
+		{"bucketPolicy", "subnetA", "subnetB"},
+		{"cluster"},
+	}
+
+	// Every valid topological order of the same graph must be normalized to
+	// the same order.
+	orders := [][]string{
+		{"vpc", "subnetB", "subnetA", "cluster", "bucket", "bucketPolicy"},
+		{"bucket", "vpc", "bucketPolicy", "subnetA", "subnetB", "cluster"},
+		{"vpc", "bucket", "subnetA", "bucketPolicy", "subnetB", "cluster"},
+	}
+	for _, order := range orders {
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+		if got := rt.TopologicalOrder(); !reflect.DeepEqual(got, wantOrder) {
+			t.Errorf("TopologicalOrder() for %v = %v, want %v", order, got, wantOrder)
+		}
+		if got := rt.TopologicalLevels(); !reflect.DeepEqual(got, wantLevels) {
+			t.Errorf("TopologicalLevels() for %v = %v, want %v", order, got, wantLevels)
+		}
+	}
+}
+
+func Test_ReconcileSubgraph(t *testing.T) {
+	resources := map[string]Resource{
+		"a":         newTestResource(),
+		"b":         newTestResource(withDependencies([]string{"a"})),
+		"c":         newTestResource(withDependencies([]string{"b"})),
+		"d":         newTestResource(withDependencies([]string{"c", "a"})),
+		"e":         newTestResource(withDependencies([]string{"d"})),
+		"unrelated": newTestResource(),
+		"sibling":   newTestResource(withDependencies([]string{"b"})),
+	}
+	order := []string{"a", "unrelated", "b", "sibling", "c", "d", "e"}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{name: "deep dependency chain", target: "e", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "middle of the chain", target: "c", want: []string{"a", "b", "c"}},
+		{name: "no dependencies", target: "unrelated", want: []string{"unrelated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rt.ReconcileSubgraph(tt.target)
+			if err != nil {
+				t.Fatalf("ReconcileSubgraph(%q) error = %v", tt.target, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReconcileSubgraph(%q) = %v, want %v", tt.target, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := rt.ReconcileSubgraph("missing"); !errors.Is(err, ErrUnknownResource) {
+		t.Errorf("ReconcileSubgraph(missing) error = %v, want %v", err, ErrUnknownResource)
+	}
+}
+
+func Test_CriticalPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]Resource
+		order     []string
+		want      []string
+	}{
+		{
+			name: "longest chain wins over shorter ones",
+			resources: map[string]Resource{
+				"vpc":      newTestResource(),
+				"subnet":   newTestResource(withDependencies([]string{"vpc"})),
+				"cluster":  newTestResource(withDependencies([]string{"subnet", "role"})),
+				"role":     newTestResource(),
+				"nodes":    newTestResource(withDependencies([]string{"cluster", "role"})),
+				"addon":    newTestResource(withDependencies([]string{"nodes"})),
+				"bucket":   newTestResource(),
+				"policy":   newTestResource(withDependencies([]string{"bucket", "role"})),
+				"endpoint": newTestResource(withDependencies([]string{"cluster"})),
+			},
+			order: []string{"vpc", "role", "bucket", "subnet", "policy", "cluster", "nodes", "endpoint", "addon"},
+			want:  []string{"vpc", "subnet", "cluster", "nodes", "addon"},
+		},
+		{
+			name: "ties are broken by id",
+			resources: map[string]Resource{
+				"a": newTestResource(),
+				"b": newTestResource(),
+				"c": newTestResource(withDependencies([]string{"b", "a"})),
+				"d": newTestResource(withDependencies([]string{"b"})),
+			},
+			order: []string{"a", "b", "c", "d"},
+			want:  []string{"a", "c"},
+		},
+		{
+			name: "independent resources",
+			resources: map[string]Resource{
+				"b": newTestResource(),
+				"a": newTestResource(),
+			},
+			order: []string{"b", "a"},
+			want:  []string{"a"},
+		},
+		{
+			name:      "empty graph",
+			resources: map[string]Resource{},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), tt.resources, tt.order)
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+			if got := rt.CriticalPath(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CriticalPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CreationOrder(t *testing.T) {
+	instance := newTestResource(withObject(map[string]interface{}{
+		"spec": map[string]interface{}{"critical": "database"},
+	}))
+	resources := map[string]Resource{
+		"cache":    newTestResource(withPriority("${schema.spec.critical == 'cache' ? 100 : 0}")),
+		"database": newTestResource(withPriority("${schema.spec.critical == 'database' ? 100 : 0}")),
+		"bucket":   newTestResource(withPriority("10")),
+		"queue":    newTestResource(),
+		// Not evaluable until the queue is resolved.
+		"worker": newTestResource(
+			withDependencies([]string{"queue"}),
+			withPriority("${queue.spec.priority}"),
+		),
+		"api": newTestResource(withDependencies([]string{"database"}), withPriority("-1")),
+		"web": newTestResource(withDependencies([]string{"cache"}), withPriority("invalid")),
+	}
+	order := []string{"bucket", "cache", "database", "queue", "api", "web", "worker"}
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, order)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	want := []string{"database", "bucket", "cache", "queue", "web", "worker", "api"}
+	if got := rt.CreationOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CreationOrder() = %v, want %v", got, want)
+	}
+
+	rt.SetResource("queue", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"priority": int64(5)},
+	}})
+	want = []string{"database", "bucket", "cache", "queue", "worker", "web", "api"}
+	if got := rt.CreationOrder(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CreationOrder() after resolving the queue = %v, want %v", got, want)
+	}
+	// The topological levels are left untouched.
+	if got := rt.TopologicalLevels()[0]; !reflect.DeepEqual(got, []string{"bucket", "cache", "database", "queue"}) {
+		t.Errorf("TopologicalLevels()[0] = %v, want sorted by id", got)
+	}
+}
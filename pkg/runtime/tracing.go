@@ -0,0 +1,95 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the runtime.
+const tracerName = "github.com/kro-run/kro/pkg/runtime"
+
+// Span names and attributes of the runtime traces.
+const (
+	synchronizeSpanName = "Synchronize"
+	evaluationSpanName  = "EvaluateExpression"
+
+	attributeRequeue           = "kro.requeue"
+	attributeExpression        = "kro.expression"
+	attributeExpressionKind    = "kro.expression.kind"
+	attributeEvaluationOutcome = "kro.expression.outcome"
+)
+
+// Outcomes of the expression evaluations, reported as span attributes.
+const (
+	evaluationOutcomeResolved   = "resolved"
+	evaluationOutcomeIncomplete = "incomplete"
+	evaluationOutcomeError      = "error"
+)
+
+// tracedSynchronize calls synchronize in a Synchronize span, parent of the
+// spans of the expressions evaluated during the call.
+func (rt *ResourceGraphDefinitionRuntime) tracedSynchronize() (bool, error) {
+	ctx, span := rt.tracer.Start(context.Background(), synchronizeSpanName)
+	rt.traceContext = ctx
+	defer func() {
+		rt.traceContext = nil
+		span.End()
+	}()
+
+	requeue, err := rt.synchronize()
+	span.SetAttributes(attribute.Bool(attributeRequeue, requeue))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return requeue, err
+}
+
+// tracedEvaluation calls evaluate in an expression evaluation span, child of
+// the given Synchronize span context if any.
+func (rt *ResourceGraphDefinitionRuntime) tracedEvaluation(
+	parent context.Context,
+	expression string,
+	evaluate func() (interface{}, error),
+) (interface{}, error) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	_, span := rt.tracer.Start(parent, evaluationSpanName)
+	defer span.End()
+
+	attributes := []attribute.KeyValue{attribute.String(attributeExpression, expression)}
+	if state, ok := rt.expressionsCache[expression]; ok {
+		attributes = append(attributes, attribute.String(attributeExpressionKind, string(state.Kind)))
+	}
+
+	value, err := evaluate()
+	switch {
+	case err == nil:
+		attributes = append(attributes, attribute.String(attributeEvaluationOutcome, evaluationOutcomeResolved))
+	case strings.Contains(err.Error(), "no such key"):
+		attributes = append(attributes, attribute.String(attributeEvaluationOutcome, evaluationOutcomeIncomplete))
+	default:
+		attributes = append(attributes, attribute.String(attributeEvaluationOutcome, evaluationOutcomeError))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attributes...)
+	return value, err
+}
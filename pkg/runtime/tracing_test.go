@@ -0,0 +1,106 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	const expr = "dep.spec.value"
+	resources := map[string]Resource{
+		"dep": newTestResource(),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{
+				"data": map[string]interface{}{"value": "${" + expr + "}"},
+			}),
+			withDependencies([]string{"dep"}),
+			withVariables([]*variable.ResourceField{
+				{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "data.value",
+						Expressions:          []string{expr},
+						StandaloneExpression: true,
+					},
+					Kind:         variable.ResourceVariableKindDynamic,
+					Dependencies: []string{"dep"},
+				},
+			}),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"dep", "consumer"},
+		WithTracerProvider(provider))
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	rt.SetResource("dep", &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"value": "hello"},
+	}})
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var synchronize, evaluation *tracetest.SpanStub
+	for i := range spans {
+		switch spans[i].Name {
+		case synchronizeSpanName:
+			synchronize = &spans[i]
+		case evaluationSpanName:
+			evaluation = &spans[i]
+		}
+	}
+	if synchronize == nil || evaluation == nil {
+		t.Fatalf("got spans %v, want a Synchronize and an EvaluateExpression span", spans)
+	}
+	if evaluation.Parent.SpanID() != synchronize.SpanContext.SpanID() {
+		t.Errorf("evaluation span parent = %v, want the Synchronize span %v",
+			evaluation.Parent.SpanID(), synchronize.SpanContext.SpanID())
+	}
+
+	got := make(map[attribute.Key]string)
+	for _, kv := range evaluation.Attributes {
+		got[kv.Key] = kv.Value.AsString()
+	}
+	want := map[attribute.Key]string{
+		attributeExpression:        expr,
+		attributeExpressionKind:    string(variable.ResourceVariableKindDynamic),
+		attributeEvaluationOutcome: evaluationOutcomeResolved,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("evaluation span attribute %s = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func Test_Tracing_Disabled(t *testing.T) {
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), map[string]Resource{}, []string{})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	if rt.tracer != nil {
+		t.Errorf("tracer = %v, want nil when no tracer provider is set", rt.tracer)
+	}
+}
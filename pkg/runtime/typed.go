@@ -0,0 +1,57 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// nativeTypes returns the Go struct types registered with WithTypedResource.
+func (rt *ResourceGraphDefinitionRuntime) nativeTypes() []reflect.Type {
+	types := make([]reflect.Type, 0, len(rt.typedKinds))
+	for _, t := range rt.typedKinds {
+		types = append(types, t)
+	}
+	return types
+}
+
+// observedValue returns the value exposing the observed resource to the
+// expressions, with the defaults of its kind applied (see WithDefaulter): a
+// typed object if a Go struct type is registered for its kind, and the
+// unstructured object otherwise. Typed objects are converted once, and cached
+// until the resource is set again.
+func (rt *ResourceGraphDefinitionRuntime) observedValue(id string, observed *unstructured.Unstructured) (interface{}, error) {
+	object := rt.defaultedObject(id, observed)
+	t, ok := rt.typedKinds[observed.GroupVersionKind()]
+	if !ok {
+		return object, nil
+	}
+	if typed, ok := rt.typedObjects[id]; ok {
+		return typed, nil
+	}
+
+	typed := reflect.New(t).Interface()
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(object, typed); err != nil {
+		return nil, fmt.Errorf("failed converting resource %s to %s: %w", id, t, err)
+	}
+	if rt.typedObjects == nil {
+		rt.typedObjects = make(map[string]interface{})
+	}
+	rt.typedObjects[id] = typed
+	return typed, nil
+}
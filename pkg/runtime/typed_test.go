@@ -0,0 +1,133 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+// newDeploymentConsumerRuntime returns a runtime with a deployment, and a
+// consumer using the given expressions reading the deployment.
+func newDeploymentConsumerRuntime(tb testing.TB, exprs []string, opts ...Option) *ResourceGraphDefinitionRuntime {
+	data := make(map[string]interface{}, len(exprs))
+	fields := make([]*variable.ResourceField, 0, len(exprs))
+	for i, expr := range exprs {
+		key := fmt.Sprintf("field%d", i)
+		data[key] = "${" + expr + "}"
+		fields = append(fields, &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "data." + key,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"deployment"},
+		})
+	}
+	resources := map[string]Resource{
+		"deployment": newTestResource(
+			withReadyExpressions([]string{"deployment.status.readyReplicas == deployment.spec.replicas"}),
+		),
+		"consumer": newTestResource(
+			withObject(map[string]interface{}{"data": data}),
+			withDependencies([]string{"deployment"}),
+			withVariables(fields),
+		),
+	}
+	rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"deployment", "consumer"}, opts...)
+	if err != nil {
+		tb.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	return rt
+}
+
+func newObservedDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":   "app",
+			"labels": map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "nginx:1.27"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(3)},
+	}}
+}
+
+func Test_TypedResources(t *testing.T) {
+	exprs := []string{
+		"deployment.spec.replicas",
+		"deployment.metadata.labels.app",
+		"deployment.spec.template.spec.containers.map(c, c.image)",
+		"deployment.spec.template.spec.containers[0]",
+	}
+	want := map[string]interface{}{
+		"field0": int64(3),
+		"field1": "web",
+		"field2": []interface{}{"nginx:1.27"},
+		"field3": map[string]interface{}{
+			"name":      "app",
+			"image":     "nginx:1.27",
+			"resources": map[string]interface{}{},
+		},
+	}
+
+	rt := newDeploymentConsumerRuntime(t, exprs,
+		WithTypedResource(appsv1.SchemeGroupVersion.WithKind("Deployment"), &appsv1.Deployment{}))
+	rt.SetResource("deployment", newObservedDeployment())
+	if _, err := rt.Synchronize(); err != nil {
+		t.Fatalf("Synchronize() error = %v", err)
+	}
+	if _, ok := rt.typedObjects["deployment"].(*appsv1.Deployment); !ok {
+		t.Fatalf("typedObjects[deployment] = %T, want *appsv1.Deployment", rt.typedObjects["deployment"])
+	}
+
+	obj, state := rt.GetResource("consumer")
+	if state != ResourceStateResolved {
+		t.Fatalf("GetResource() state = %v, want %v", state, ResourceStateResolved)
+	}
+	got := obj.Object["data"].(map[string]interface{})
+	for key, value := range want {
+		if !reflect.DeepEqual(got[key], value) {
+			t.Errorf("data.%s = %#v, want %#v", key, got[key], value)
+		}
+	}
+
+	ready, reason, err := rt.IsResourceReady("deployment")
+	if err != nil || !ready {
+		t.Errorf("IsResourceReady() = %v, %q, %v, want ready", ready, reason, err)
+	}
+
+	// Setting the resource again drops the typed conversion.
+	rt.SetResource("deployment", newObservedDeployment())
+	if _, ok := rt.typedObjects["deployment"]; ok {
+		t.Error("SetResource() should drop the cached typed object")
+	}
+}
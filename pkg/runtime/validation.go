@@ -0,0 +1,292 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"golang.org/x/exp/maps"
+
+	krocel "github.com/kro-run/kro/pkg/cel"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+)
+
+// ValidateRendered renders the given resource and validates it against the
+// provided OpenAPI schema, typically the schema of the target CRD. It returns
+// all the violations found, e.g wrong types or missing required fields, so
+// that they can be reported before the API server rejects the resource.
+func (rt *ResourceGraphDefinitionRuntime) ValidateRendered(resourceID string, schema *extv1.JSONSchemaProps) []error {
+	resource, ok := rt.resources[resourceID]
+	if !ok {
+		return []error{fmt.Errorf("resource %s not found", resourceID)}
+	}
+	if !rt.canProcessResource(resourceID) {
+		return []error{fmt.Errorf("resource %s is not rendered yet", resourceID)}
+	}
+
+	internalSchema := &apiextensions.JSONSchemaProps{}
+	if err := extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internalSchema, nil); err != nil {
+		return []error{fmt.Errorf("failed converting schema: %w", err)}
+	}
+	validator, _, err := validation.NewSchemaValidator(internalSchema)
+	if err != nil {
+		return []error{fmt.Errorf("failed creating schema validator: %w", err)}
+	}
+
+	var errs []error
+	for _, fieldErr := range validation.ValidateCustomResource(nil, resource.Unstructured().Object, validator) {
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
+
+// Validate checks the consistency of the whole runtime configuration, and is
+// meant to be called before the first Synchronize, e.g at admission time. It
+// runs all the checks below and returns all the errors found, grouped by
+// check, instead of stopping at the first one:
+//
+//   - the topological order lists every resource exactly once, after its
+//     dependencies.
+//   - the resource dependencies don't form cycles.
+//   - every expression compiles.
+//   - the expression dependencies are resources of the graph, declared as
+//     dependencies of the resources using them.
+//   - the readyWhen expressions return booleans.
+func (rt *ResourceGraphDefinitionRuntime) Validate() []error {
+	var errs []error
+	errs = append(errs, rt.validateTopologicalOrder()...)
+	errs = append(errs, rt.validateAcyclic()...)
+	errs = append(errs, rt.validateExpressions()...)
+	return errs
+}
+
+func (rt *ResourceGraphDefinitionRuntime) validateTopologicalOrder() []error {
+	var errs []error
+	positions := make(map[string]int, len(rt.topologicalOrder))
+	for i, id := range rt.topologicalOrder {
+		if _, ok := rt.resources[id]; !ok {
+			errs = append(errs, fmt.Errorf("topological order references unknown resource %s", id))
+			continue
+		}
+		if _, seen := positions[id]; seen {
+			errs = append(errs, fmt.Errorf("resource %s appears multiple times in the topological order", id))
+			continue
+		}
+		positions[id] = i
+	}
+
+	ids := maps.Keys(rt.resources)
+	slices.Sort(ids)
+	for _, id := range ids {
+		position, ok := positions[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("resource %s is missing from the topological order", id))
+			continue
+		}
+		for _, dep := range rt.resources[id].GetDependencies() {
+			if depPosition, ok := positions[dep]; ok && depPosition > position {
+				errs = append(errs, fmt.Errorf("resource %s comes before its dependency %s in the topological order", id, dep))
+			}
+		}
+	}
+	return errs
+}
+
+// validateAcyclic returns an error for every cycle of the resource
+// dependencies, reported once from its smallest resource id.
+func (rt *ResourceGraphDefinitionRuntime) validateAcyclic() []error {
+	ids := maps.Keys(rt.resources)
+	slices.Sort(ids)
+
+	var errs []error
+	reported := make(map[string]bool)
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(id string)
+	visit = func(id string) {
+		if i := slices.Index(path, id); i >= 0 {
+			cycle := append(slices.Clone(path[i:]), id)
+			// Rotate the cycle so that it starts from its smallest id, the
+			// same cycle can be reached from any of its resources.
+			start := slices.Index(cycle, slices.Min(cycle[:len(cycle)-1]))
+			cycle = slices.Concat(cycle[start:len(cycle)-1], cycle[:start+1])
+			key := strings.Join(cycle, ",")
+			if !reported[key] {
+				reported[key] = true
+				errs = append(errs, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> ")))
+			}
+			return
+		}
+		if visited[id] {
+			return
+		}
+		resource, ok := rt.resources[id]
+		if !ok {
+			return
+		}
+		path = append(path, id)
+		deps := slices.Clone(resource.GetDependencies())
+		slices.Sort(deps)
+		for _, dep := range deps {
+			visit(dep)
+		}
+		path = path[:len(path)-1]
+		visited[id] = true
+	}
+	for _, id := range ids {
+		visit(id)
+	}
+	return errs
+}
+
+// validateExpressions compiles the expressions of the resources and of the
+// instance, and checks their dependencies.
+func (rt *ResourceGraphDefinitionRuntime) validateExpressions() []error {
+	staticEnv, err := krocel.DefaultEnvironment(krocel.WithResourceIDs(contextVariableNames))
+	if err != nil {
+		return []error{fmt.Errorf("failed creating CEL environment: %w", err)}
+	}
+
+	var errs []error
+	compile := func(env *cel.Env, expression string) *cel.Ast {
+		if prefix, _, found := strings.Cut(expression, ":"); found {
+			// The alternative evaluators don't compile ahead of time.
+			if _, ok := rt.evaluators[prefix]; ok {
+				return nil
+			}
+		}
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			if _, err := compileExpression(env, expression); err != nil {
+				errs = append(errs, err)
+			}
+			return nil
+		}
+		return ast
+	}
+
+	ids := maps.Keys(rt.resources)
+	slices.Sort(ids)
+	for _, id := range append(ids, "instance") {
+		var declared []string
+		if resource, ok := rt.resources[id]; ok {
+			declared = resource.GetDependencies()
+			for _, expression := range resource.GetIncludeWhenExpressions() {
+				compile(staticEnv, expression)
+			}
+		}
+
+		for _, v := range rt.runtimeVariables[id] {
+			if !v.Kind.IsDynamic() {
+				compile(staticEnv, v.Expression)
+				continue
+			}
+			for _, dep := range v.Dependencies {
+				switch {
+				case rt.resources[dep] == nil:
+					errs = append(errs, fmt.Errorf("expression %s of %s references %w %s", v.Expression, id, ErrUnknownResource, dep))
+				case id != "instance" && dep != id && !slices.Contains(declared, dep):
+					errs = append(errs, fmt.Errorf("expression %s of %s references %s, which isn't one of its dependencies", v.Expression, id, dep))
+				}
+			}
+			env, err := rt.dynamicEnvironment(v.Dependencies)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed creating CEL environment of expression %s: %w", v.Expression, err))
+				continue
+			}
+			compile(env, v.Expression)
+		}
+
+		resource, ok := rt.resources[id]
+		if !ok || len(resource.GetReadyWhenExpressions()) == 0 {
+			continue
+		}
+		readyEnv, err := krocel.DefaultEnvironment(
+			krocel.WithResourceIDs([]string{id}),
+			krocel.WithNativeTypes(rt.nativeTypes()...),
+		)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed creating CEL environment of resource %s: %w", id, err))
+			continue
+		}
+		for _, expression := range resource.GetReadyWhenExpressions() {
+			ast := compile(readyEnv, expression)
+			if ast == nil {
+				continue
+			}
+			// Fields of the resources are dynamically typed, only the
+			// expressions known to return something else are rejected.
+			if t := ast.OutputType(); !t.IsAssignableType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+				errs = append(errs, fmt.Errorf("readyWhen expression %s of resource %s returns %s, expected bool", expression, id, t))
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateReferences checks that every resource referenced by the graph, as a
+// dependency or readiness dependency of a resource, or as a dependency of an
+// expression, is a resource of the graph, e.g to catch typos. It doesn't need
+// a runtime, and can be called at admission time, see WithReferenceValidation
+// to run it when constructing the runtime. The errors, one per unknown
+// reference, wrap ErrUnknownResource.
+func ValidateReferences(instance Resource, resources map[string]Resource) error {
+	return errors.Join(unknownReferences(instance, resources, nil)...)
+}
+
+// unknownReferences implements ValidateReferences, the given kind aliases are
+// known references as well.
+func unknownReferences(instance Resource, resources map[string]Resource, aliases map[string]string) []error {
+	known := func(id string) bool {
+		_, isResource := resources[id]
+		_, isAlias := aliases[id]
+		return isResource || isAlias
+	}
+
+	ids := maps.Keys(resources)
+	slices.Sort(ids)
+	var errs []error
+	for _, id := range append(ids, "instance") {
+		descriptor := instance
+		if id != "instance" {
+			descriptor = resources[id]
+		}
+		for _, dep := range descriptor.GetDependencies() {
+			if !known(dep) {
+				errs = append(errs, fmt.Errorf("%s depends on %w %s", id, ErrUnknownResource, dep))
+			}
+		}
+		for _, dep := range descriptor.GetReadinessDependencies() {
+			if !known(dep) {
+				errs = append(errs, fmt.Errorf("%s waits for the readiness of %w %s", id, ErrUnknownResource, dep))
+			}
+		}
+		for _, v := range descriptor.GetVariables() {
+			for _, dep := range v.Dependencies {
+				if !known(dep) {
+					errs = append(errs, fmt.Errorf("expression %s of %s references %w %s",
+						strings.Join(v.Expressions, ", "), id, ErrUnknownResource, dep))
+				}
+			}
+		}
+	}
+	return errs
+}
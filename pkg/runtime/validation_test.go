@@ -0,0 +1,253 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_ValidateRendered(t *testing.T) {
+	deploymentSchema := &extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type:     "object",
+				Required: []string{"replicas", "image"},
+				Properties: map[string]extv1.JSONSchemaProps{
+					"replicas": {Type: "integer"},
+					"image":    {Type: "string"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		instance map[string]interface{}
+		spec     map[string]interface{}
+		wantErrs []string
+	}{
+		{
+			name:     "valid render",
+			instance: map[string]interface{}{"replicas": int64(3), "image": "nginx"},
+			spec: map[string]interface{}{
+				"replicas": "${schema.spec.replicas}",
+				"image":    "${schema.spec.image}",
+			},
+		},
+		{
+			name:     "wrong type",
+			instance: map[string]interface{}{"replicas": "three", "image": "nginx"},
+			spec: map[string]interface{}{
+				"replicas": "${schema.spec.replicas}",
+				"image":    "${schema.spec.image}",
+			},
+			wantErrs: []string{`spec.replicas: Invalid value: "string": spec.replicas in body must be of type integer: "string"`},
+		},
+		{
+			name:     "missing required field and wrong type",
+			instance: map[string]interface{}{"replicas": "three"},
+			spec: map[string]interface{}{
+				"replicas": "${schema.spec.replicas}",
+			},
+			wantErrs: []string{
+				"spec.image: Required value",
+				`spec.replicas: Invalid value: "string": spec.replicas in body must be of type integer: "string"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var variables []*variable.ResourceField
+			for field := range tt.spec {
+				variables = append(variables, &variable.ResourceField{
+					FieldDescriptor: variable.FieldDescriptor{
+						Path:                 "spec." + field,
+						Expressions:          []string{"schema.spec." + field},
+						StandaloneExpression: true,
+					},
+					Kind: variable.ResourceVariableKindStatic,
+				})
+			}
+			rt, err := NewResourceGraphDefinitionRuntime(
+				newTestResource(withObject(map[string]interface{}{"spec": tt.instance})),
+				map[string]Resource{
+					"deployment": newTestResource(
+						withObject(map[string]interface{}{"spec": tt.spec}),
+						withVariables(variables),
+					),
+				},
+				[]string{"deployment"},
+			)
+			if err != nil {
+				t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+			}
+
+			errs := rt.ValidateRendered("deployment", deploymentSchema)
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			slices.Sort(got)
+			if strings.Join(got, "\n") != strings.Join(tt.wantErrs, "\n") {
+				t.Errorf("ValidateRendered() = %q, want %q", got, tt.wantErrs)
+			}
+		})
+	}
+
+	t.Run("resource not rendered", func(t *testing.T) {
+		rt := &ResourceGraphDefinitionRuntime{
+			resources: map[string]Resource{
+				"deployment": newTestResource(withDependencies([]string{"configmap"})),
+			},
+			runtimeVariables: map[string][]*expressionEvaluationState{
+				"configmap": {{Kind: variable.ResourceVariableKindDynamic}},
+			},
+		}
+		if errs := rt.ValidateRendered("deployment", deploymentSchema); len(errs) != 1 {
+			t.Errorf("ValidateRendered() = %v, want a single error", errs)
+		}
+	})
+}
+
+func Test_Validate(t *testing.T) {
+	dynamicVariable := func(path, expr string, deps ...string) *variable.ResourceField {
+		return &variable.ResourceField{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 path,
+				Expressions:          []string{expr},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: deps,
+		}
+	}
+
+	t.Run("valid runtime", func(t *testing.T) {
+		rt := newConsumersRuntime(t, "dep.spec.value", "dep.spec.count * 2")
+		if errs := rt.Validate(); len(errs) != 0 {
+			t.Errorf("Validate() = %v, want no errors", errs)
+		}
+	})
+
+	t.Run("every failure class is reported", func(t *testing.T) {
+		resources := map[string]Resource{
+			"a": newTestResource(withDependencies([]string{"b"})),
+			"b": newTestResource(withDependencies([]string{"a"})),
+			"c": newTestResource(
+				withVariables([]*variable.ResourceField{
+					dynamicVariable("data.value", "tripple(b.spec.value)", "b"),
+					dynamicVariable("data.other", "ghost.spec.value", "ghost"),
+				}),
+				withReadyExpressions([]string{"size(c.status.conditions)", "c.status.ready"}),
+			),
+		}
+		rt, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"a", "b", "unknown"})
+		if err != nil {
+			t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+
+		var got []string
+		for _, err := range rt.Validate() {
+			got = append(got, err.Error())
+		}
+		want := []string{
+			"topological order references unknown resource unknown",
+			"resource b comes before its dependency a in the topological order",
+			"resource c is missing from the topological order",
+			"dependency cycle: a -> b -> a",
+			"expression tripple(b.spec.value) of c references b, which isn't one of its dependencies",
+			"unknown function 'tripple'",
+			"expression ghost.spec.value of c references unknown resource ghost",
+			"readyWhen expression size(c.status.conditions) of resource c returns int, expected bool",
+		}
+		if len(got) != len(want) {
+			t.Fatalf("Validate() = %q, want %d errors", got, len(want))
+		}
+		for i := range want {
+			if !strings.Contains(got[i], want[i]) {
+				t.Errorf("Validate()[%d] = %q, want it to contain %q", i, got[i], want[i])
+			}
+		}
+	})
+}
+
+func Test_ValidateReferences(t *testing.T) {
+	newResources := func(dep string) map[string]Resource {
+		return map[string]Resource{
+			"database": newTestResource(),
+			"app": newTestResource(
+				withDependencies([]string{"database"}),
+				withVariables([]*variable.ResourceField{
+					{
+						FieldDescriptor: variable.FieldDescriptor{
+							Path:                 "spec.host",
+							Expressions:          []string{dep + ".status.host"},
+							StandaloneExpression: true,
+						},
+						Kind:         variable.ResourceVariableKindDynamic,
+						Dependencies: []string{dep},
+					},
+				}),
+			),
+		}
+	}
+
+	t.Run("valid references", func(t *testing.T) {
+		resources := newResources("database")
+		if err := ValidateReferences(newTestResource(), resources); err != nil {
+			t.Errorf("ValidateReferences() error = %v", err)
+		}
+		if _, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "app"}, WithReferenceValidation()); err != nil {
+			t.Errorf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+	})
+
+	t.Run("unknown references", func(t *testing.T) {
+		resources := newResources("databse")
+		resources["cache"] = newTestResource(
+			withDependencies([]string{"ghost"}),
+			withReadinessDependencies([]string{"phantom"}),
+		)
+		err := ValidateReferences(newTestResource(), resources)
+		if !errors.Is(err, ErrUnknownResource) {
+			t.Fatalf("ValidateReferences() error = %v, want %v", err, ErrUnknownResource)
+		}
+		for _, want := range []string{
+			"expression databse.status.host of app references unknown resource databse",
+			"cache depends on unknown resource ghost",
+			"cache waits for the readiness of unknown resource phantom",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("ValidateReferences() error = %v, want it to contain %q", err, want)
+			}
+		}
+
+		if _, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "app", "cache"}, WithReferenceValidation()); !errors.Is(err, ErrUnknownResource) {
+			t.Errorf("NewResourceGraphDefinitionRuntime() error = %v, want %v", err, ErrUnknownResource)
+		}
+		// The validation is opt-in.
+		if _, err := NewResourceGraphDefinitionRuntime(newTestResource(), resources, []string{"database", "app", "cache"}); err != nil {
+			t.Errorf("NewResourceGraphDefinitionRuntime() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RenderResult is the rendering of an instance spec variant, see
+// RenderVariants.
+type RenderResult struct {
+	// Spec is the instance spec of the variant.
+	Spec map[string]interface{}
+	// Resources are the rendered resources, see RenderResource, keyed by
+	// resource id. The resources ignored by conditions, or that can't be
+	// rendered yet, are not part of it.
+	Resources map[string]*unstructured.Unstructured
+	// Ignored are the ids of the resources ignored by conditions, in
+	// topological order.
+	Ignored []string
+	// Manifest is the multi-document YAML stream of the rendered resources,
+	// see RenderAll.
+	Manifest []byte
+}
+
+// templateResource is a resource whose object is replaced by a copy of its
+// template, used by the clones of the runtime.
+type templateResource struct {
+	Resource
+	obj *unstructured.Unstructured
+}
+
+// Unstructured implements Resource.
+func (r *templateResource) Unstructured() *unstructured.Unstructured {
+	return r.obj
+}
+
+// Clone returns a new runtime built from the same resource templates,
+// instance and options as the runtime, as they were before any evaluation.
+// The observed resources (see SetResource) and the resources ignored by
+// conditions are carried over, but the clone needs to be synchronized.
+func (rt *ResourceGraphDefinitionRuntime) Clone() (*ResourceGraphDefinitionRuntime, error) {
+	clone, err := rt.cloneWithInstance(rt.instanceTemplate)
+	if err != nil {
+		return nil, err
+	}
+	for id := range rt.ignoredByConditionsResources {
+		clone.IgnoreResource(id)
+	}
+	return clone, nil
+}
+
+// cloneWithInstance returns a clone of the runtime for the given instance
+// object, without the resources ignored by conditions.
+func (rt *ResourceGraphDefinitionRuntime) cloneWithInstance(instance map[string]interface{}) (*ResourceGraphDefinitionRuntime, error) {
+	clone, err := rt.newWithInstance(instance)
+	if err != nil {
+		return nil, err
+	}
+	for id, observed := range rt.resolvedResources {
+		clone.SetResource(id, &unstructured.Unstructured{Object: deepCopyValue(observed.Object).(map[string]interface{})})
+	}
+	return clone, nil
+}
+
+// newWithInstance returns a new runtime built from the resource templates and
+// options of the runtime, for the given instance object. Nothing is observed
+// by the new runtime.
+func (rt *ResourceGraphDefinitionRuntime) newWithInstance(instance map[string]interface{}) (*ResourceGraphDefinitionRuntime, error) {
+	resources := make(map[string]Resource, len(rt.resources))
+	for id, resource := range rt.resources {
+		resources[id] = &templateResource{
+			Resource: resource,
+			obj:      &unstructured.Unstructured{Object: deepCopyValue(rt.templates[id]).(map[string]interface{})},
+		}
+	}
+	return NewResourceGraphDefinitionRuntime(
+		&templateResource{
+			Resource: rt.instance,
+			obj:      &unstructured.Unstructured{Object: deepCopyValue(instance).(map[string]interface{})},
+		},
+		resources,
+		rt.topologicalOrder,
+		rt.options...,
+	)
+}
+
+// RenderVariants renders the resources of the instance for every given spec,
+// e.g to preview and compare A/B or canary variants of an instance. Every
+// variant is rendered by a clone of the runtime (see Clone) whose instance
+// spec is replaced by the variant spec: the includeWhen conditions are
+// evaluated, and the clone is synchronized until no more expressions can be
+// resolved, given the observed resources. The results are returned in the
+// order of the specs.
+func (rt *ResourceGraphDefinitionRuntime) RenderVariants(specs []map[string]interface{}) ([]RenderResult, error) {
+	results := make([]RenderResult, 0, len(specs))
+	for i, spec := range specs {
+		instance := deepCopyValue(rt.instanceTemplate).(map[string]interface{})
+		instance["spec"] = deepCopyValue(spec)
+		clone, err := rt.cloneWithInstance(instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the runtime of variant %d: %w", i, err)
+		}
+		result, err := clone.renderVariant(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render variant %d: %w", i, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// synchronizeUntilStable synchronizes the runtime until no more expressions
+// can be resolved, given the observed resources. Synchronize gives up after
+// the maximum number of iterations (see WithMaxSynchronizeIterations), with a
+// diagnostic of the unresolved expressions, see checkConvergence.
+func (rt *ResourceGraphDefinitionRuntime) synchronizeUntilStable() error {
+	for {
+		more, err := rt.Synchronize()
+		if err != nil {
+			if errors.Is(err, ErrMaxSynchronizeIterations) {
+				return err
+			}
+			// Incomplete data only means that the observed resources don't
+			// allow to resolve more expressions.
+			var evalErr *EvalError
+			if errors.As(err, &evalErr) && evalErr.IsIncompleteData {
+				return nil
+			}
+			return err
+		}
+		if !more || !rt.lastSynchronizeProgressed {
+			return nil
+		}
+	}
+}
+
+// renderVariant evaluates the conditions of the resources, synchronizes the
+// runtime and renders its resources.
+func (rt *ResourceGraphDefinitionRuntime) renderVariant(spec map[string]interface{}) (RenderResult, error) {
+	result := RenderResult{
+		Spec:      spec,
+		Resources: make(map[string]*unstructured.Unstructured),
+	}
+	for _, id := range rt.topologicalOrder {
+		if rt.areDependenciesIgnored(id) {
+			rt.IgnoreResource(id)
+			continue
+		}
+		included, _, err := rt.evaluateIncludeWhen(id)
+		if err != nil {
+			return RenderResult{}, err
+		}
+		if !included {
+			rt.IgnoreResource(id)
+		}
+	}
+
+	if err := rt.synchronizeUntilStable(); err != nil {
+		return RenderResult{}, err
+	}
+
+	for _, id := range rt.topologicalOrder {
+		if rt.ignoredByConditionsResources[id] {
+			result.Ignored = append(result.Ignored, id)
+			continue
+		}
+		if rendered, state := rt.RenderResource(id); state == ResourceStateResolved {
+			result.Resources[id] = rendered
+		}
+	}
+	manifest, err := rt.RenderAll()
+	if err != nil {
+		return RenderResult{}, err
+	}
+	result.Manifest = manifest
+	return result, nil
+}
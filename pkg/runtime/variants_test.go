@@ -0,0 +1,154 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package runtime
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+)
+
+func Test_RenderVariants(t *testing.T) {
+	resources := map[string]Resource{
+		"deployment": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "app"},
+				"spec": map[string]interface{}{
+					"replicas": "${schema.spec.replicas}",
+					"image":    "${'nginx:' + schema.spec.version}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				staticField("spec.replicas", "schema.spec.replicas"),
+				staticField("spec.image", "'nginx:' + schema.spec.version"),
+			}),
+		),
+		"canary": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "canary"},
+				"spec": map[string]interface{}{
+					"image": "${'nginx:' + schema.spec.version}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				staticField("spec.image", "'nginx:' + schema.spec.version"),
+			}),
+			withConditions([]string{"schema.spec.canary"}),
+		),
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "instance"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"version":  "1.0",
+			"canary":   false,
+		},
+	}))
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment", "canary"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	results, err := rt.RenderVariants([]map[string]interface{}{
+		{"replicas": int64(2), "version": "1.1", "canary": false},
+		{"replicas": int64(3), "version": "1.2", "canary": true},
+	})
+	if err != nil {
+		t.Fatalf("RenderVariants() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("RenderVariants() returned %d results, want 2", len(results))
+	}
+
+	stable, canary := results[0], results[1]
+	if got, _, _ := unstructured.NestedInt64(stable.Resources["deployment"].Object, "spec", "replicas"); got != 2 {
+		t.Errorf("stable variant replicas = %d, want 2", got)
+	}
+	if got, _, _ := unstructured.NestedInt64(canary.Resources["deployment"].Object, "spec", "replicas"); got != 3 {
+		t.Errorf("canary variant replicas = %d, want 3", got)
+	}
+	if got, _, _ := unstructured.NestedString(canary.Resources["canary"].Object, "spec", "image"); got != "nginx:1.2" {
+		t.Errorf("canary variant image = %q, want %q", got, "nginx:1.2")
+	}
+	if _, ok := stable.Resources["canary"]; ok {
+		t.Errorf("stable variant rendered the canary resource")
+	}
+	if !reflect.DeepEqual(stable.Ignored, []string{"canary"}) || len(canary.Ignored) != 0 {
+		t.Errorf("Ignored = %v and %v, want [canary] and []", stable.Ignored, canary.Ignored)
+	}
+	if !strings.Contains(string(canary.Manifest), "nginx:1.2") || strings.Contains(string(stable.Manifest), "nginx:1.2") {
+		t.Errorf("unexpected manifests:\n%s\n%s", stable.Manifest, canary.Manifest)
+	}
+
+	// The runtime itself is left untouched.
+	if got, _, _ := unstructured.NestedInt64(rt.resources["deployment"].Unstructured().Object, "spec", "replicas"); got != 1 {
+		t.Errorf("runtime replicas = %d, want 1", got)
+	}
+}
+
+func Test_Clone_SetInstance(t *testing.T) {
+	resources := map[string]Resource{
+		"deployment": newTestResource(
+			withObject(map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "${schema.metadata.name}"},
+				"spec": map[string]interface{}{
+					"image": "${'nginx:' + schema.spec.version}",
+				},
+			}),
+			withVariables([]*variable.ResourceField{
+				staticField("metadata.name", "schema.metadata.name"),
+				staticField("spec.image", "'nginx:' + schema.spec.version"),
+			}),
+		),
+	}
+	instance := newTestResource(withObject(map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "instance"},
+		"spec":     map[string]interface{}{"version": "1.0"},
+	}))
+	rt, err := NewResourceGraphDefinitionRuntime(instance, resources, []string{"deployment"})
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+
+	// The instance is updated after the runtime was created.
+	rt.SetInstance(&unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "updated"},
+		"spec":     map[string]interface{}{"version": "2.0"},
+	}})
+
+	clone, err := rt.Clone()
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if got := clone.GetInstance().GetName(); got != "updated" {
+		t.Errorf("clone instance name = %q, want %q", got, "updated")
+	}
+	deployment, _ := clone.GetResource("deployment")
+	if got, _, _ := unstructured.NestedString(deployment.Object, "spec", "image"); got != "nginx:2.0" {
+		t.Errorf("clone image = %q, want %q", got, "nginx:2.0")
+	}
+
+	// Variants only replace the spec of the updated instance.
+	results, err := rt.RenderVariants([]map[string]interface{}{{"version": "3.0"}})
+	if err != nil {
+		t.Fatalf("RenderVariants() error = %v", err)
+	}
+	if got := results[0].Resources["deployment"].GetName(); got != "updated" {
+		t.Errorf("variant name = %q, want %q", got, "updated")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeruntime
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// FakeRuntime wraps a ResourceGraphDefinitionRuntime and lets tests script
+// how resources show up in the cluster over time. e.g "at cycle 2, the
+// deployment gets status.readyReplicas=3".
+//
+// Every call to Step is a cycle: the resources scheduled for that cycle are
+// set in the runtime (a.k.a SetResource) and Synchronize is called right
+// after, mimicking what the instance controller does during a reconciliation.
+type FakeRuntime struct {
+	*runtime.ResourceGraphDefinitionRuntime
+
+	// cycle is the number of cycles executed so far.
+	cycle int
+	// lastCycle is the highest cycle that has a scheduled resource.
+	lastCycle int
+	// scheduled holds the resources to set in the runtime, keyed by cycle.
+	scheduled map[int][]scheduledResource
+}
+
+// scheduledResource is a resource observed state that will be set in the
+// runtime at a given cycle.
+type scheduledResource struct {
+	id  string
+	obj *unstructured.Unstructured
+}
+
+// New returns a new FakeRuntime wrapping the given runtime.
+func New(rt *runtime.ResourceGraphDefinitionRuntime) *FakeRuntime {
+	return &FakeRuntime{
+		ResourceGraphDefinitionRuntime: rt,
+		scheduled:                      make(map[int][]scheduledResource),
+	}
+}
+
+// At schedules the given object to be set as the observed state of the
+// resource at the given cycle. Cycles start at 1.
+func (f *FakeRuntime) At(cycle int, resourceID string, obj map[string]interface{}) *FakeRuntime {
+	f.scheduled[cycle] = append(f.scheduled[cycle], scheduledResource{
+		id:  resourceID,
+		obj: &unstructured.Unstructured{Object: obj},
+	})
+	if cycle > f.lastCycle {
+		f.lastCycle = cycle
+	}
+	return f
+}
+
+// Cycle returns the number of cycles executed so far.
+func (f *FakeRuntime) Cycle() int {
+	return f.cycle
+}
+
+// Step executes the next cycle. It returns the output of Synchronize.
+func (f *FakeRuntime) Step() (bool, error) {
+	f.cycle++
+	for _, r := range f.scheduled[f.cycle] {
+		f.SetResource(r.id, r.obj)
+	}
+	return f.Synchronize()
+}
+
+// Run executes cycles until the runtime is fully synchronized and all the
+// scheduled resources have been set. It returns an error if that doesn't
+// happen within maxCycles cycles. Note that readyWhen expressions are never
+// stored as resolved, runtimes with readiness conditions are driven with Step
+// instead.
+func (f *FakeRuntime) Run(maxCycles int) error {
+	for i := 0; i < maxCycles; i++ {
+		cont, err := f.Step()
+		if err != nil {
+			return fmt.Errorf("cycle %d: %w", f.cycle, err)
+		}
+		if !cont && f.cycle >= f.lastCycle {
+			return nil
+		}
+	}
+	return fmt.Errorf("runtime did not converge after %d cycles", maxCycles)
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeruntime
+
+import (
+	"testing"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// newTestRuntime returns a runtime of a config map and a deployment, the
+// deployment being ready when the given expressions are true.
+func newTestRuntime(t *testing.T, readyWhen ...string) *FakeRuntime {
+	instance := NewResource(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"name": "myapp",
+		},
+	})
+	instance.Variables = []*variable.ResourceField{
+		{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "status.availableReplicas",
+				Expressions:          []string{"deployment.status.availableReplicas"},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"deployment"},
+		},
+	}
+
+	configmap := NewResource(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "${schema.spec.name}",
+		},
+	})
+	configmap.Variables = []*variable.ResourceField{
+		{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "metadata.name",
+				Expressions:          []string{"schema.spec.name"},
+				StandaloneExpression: true,
+			},
+			Kind: variable.ResourceVariableKindStatic,
+		},
+	}
+
+	deployment := NewResource(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "myapp",
+		},
+		"spec": map[string]interface{}{
+			"configName": "${configmap.metadata.name}",
+		},
+	})
+	deployment.Dependencies = []string{"configmap"}
+	deployment.ReadyWhenExpressions = readyWhen
+	deployment.Variables = []*variable.ResourceField{
+		{
+			FieldDescriptor: variable.FieldDescriptor{
+				Path:                 "spec.configName",
+				Expressions:          []string{"configmap.metadata.name"},
+				StandaloneExpression: true,
+			},
+			Kind:         variable.ResourceVariableKindDynamic,
+			Dependencies: []string{"configmap"},
+		},
+	}
+
+	rt, err := runtime.NewResourceGraphDefinitionRuntime(
+		instance,
+		map[string]runtime.Resource{
+			"configmap":  configmap,
+			"deployment": deployment,
+		},
+		[]string{"configmap", "deployment"},
+	)
+	if err != nil {
+		t.Fatalf("NewResourceGraphDefinitionRuntime() error = %v", err)
+	}
+	return New(rt)
+}
+
+func TestFakeRuntime_StaticFlow(t *testing.T) {
+	f := newTestRuntime(t)
+
+	// Static variables are resolved at construction, before any cycle.
+	configmap, state := f.GetResource("configmap")
+	if state != runtime.ResourceStateResolved {
+		t.Fatalf("GetResource(configmap) state = %v, want %v", state, runtime.ResourceStateResolved)
+	}
+	if name := configmap.GetName(); name != "myapp" {
+		t.Errorf("configmap name = %q, want %q", name, "myapp")
+	}
+	if _, state := f.GetResource("deployment"); state != runtime.ResourceStateWaitingOnDependencies {
+		t.Errorf("GetResource(deployment) state = %v, want %v", state, runtime.ResourceStateWaitingOnDependencies)
+	}
+}
+
+func TestFakeRuntime_DynamicFlow(t *testing.T) {
+	f := newTestRuntime(t).
+		At(2, "configmap", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+		})
+
+	// The config map name is deterministic, the deployment can reference it
+	// before the config map is created.
+	for cycle := 1; cycle <= 2; cycle++ {
+		if _, err := f.Step(); err != nil {
+			t.Fatalf("Step() error = %v", err)
+		}
+		deployment, state := f.GetResource("deployment")
+		if state != runtime.ResourceStateResolved {
+			t.Fatalf("cycle %d: GetResource(deployment) state = %v, want %v", cycle, state, runtime.ResourceStateResolved)
+		}
+		configName := deployment.Object["spec"].(map[string]interface{})["configName"]
+		if configName != "myapp" {
+			t.Errorf("cycle %d: deployment spec.configName = %v, want %q", cycle, configName, "myapp")
+		}
+	}
+}
+
+func TestFakeRuntime_ReadinessFlow(t *testing.T) {
+	f := newTestRuntime(t, "deployment.status.availableReplicas == 3").
+		At(1, "configmap", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+		}).
+		At(2, "deployment", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+			"status":   map[string]interface{}{"availableReplicas": int64(1)},
+		}).
+		At(4, "deployment", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+			"status":   map[string]interface{}{"availableReplicas": int64(3)},
+		})
+
+	wantReady := map[int]bool{2: false, 3: false, 4: true}
+	for cycle := 1; cycle <= 4; cycle++ {
+		if _, err := f.Step(); err != nil {
+			t.Fatalf("cycle %d: Step() error = %v", cycle, err)
+		}
+		want, ok := wantReady[cycle]
+		if !ok {
+			continue
+		}
+		ready, _, err := f.IsResourceReady("deployment")
+		if err != nil {
+			t.Fatalf("cycle %d: IsResourceReady() error = %v", cycle, err)
+		}
+		if ready != want {
+			t.Errorf("cycle %d: IsResourceReady() = %v, want %v", cycle, ready, want)
+		}
+	}
+}
+
+func TestFakeRuntime_Run(t *testing.T) {
+	f := newTestRuntime(t).
+		At(1, "configmap", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+		}).
+		At(3, "deployment", map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "myapp"},
+			"status":   map[string]interface{}{"availableReplicas": int64(3)},
+		})
+
+	if err := f.Run(10); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if f.Cycle() < 3 {
+		t.Errorf("Cycle() = %d, want at least 3", f.Cycle())
+	}
+	status := f.GetInstance().Object["status"].(map[string]interface{})
+	if status["availableReplicas"] != int64(3) {
+		t.Errorf("instance status.availableReplicas = %v, want 3", status["availableReplicas"])
+	}
+
+	t.Run("does not converge", func(t *testing.T) {
+		f := newTestRuntime(t)
+		if err := f.Run(3); err == nil {
+			t.Error("Run() expected error, got none")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Kube Resource Orchestrator Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeruntime
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kro-run/kro/pkg/graph/variable"
+	"github.com/kro-run/kro/pkg/runtime"
+)
+
+// Compile time proof to ensure that Resource implements the runtime.Resource
+// interface.
+var _ runtime.Resource = &Resource{}
+
+// Resource is a minimal runtime.Resource implementation, meant to be used
+// to build runtimes in tests without going through the graph builder.
+type Resource struct {
+	GVR                    schema.GroupVersionResource
+	Variables              []*variable.ResourceField
+	Dependencies           []string
+	ReadinessDependencies  []string
+	ReadyWhenExpressions   []string
+	ReadyWhenMessages      map[string]string
+	DeletionPolicy         string
+	Priority               string
+	IncludeWhenExpressions []string
+	TopLevelFields         []string
+	Namespaced             bool
+	SkipReadiness          bool
+	IgnoreUpdateErrors     bool
+	Object                 *unstructured.Unstructured
+}
+
+// NewResource returns a new Resource with the given object.
+func NewResource(obj map[string]interface{}) *Resource {
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	return &Resource{
+		Object: &unstructured.Unstructured{Object: obj},
+	}
+}
+
+// GetGroupVersionResource implements runtime.ResourceDescriptor.
+func (r *Resource) GetGroupVersionResource() schema.GroupVersionResource {
+	return r.GVR
+}
+
+// GetVariables implements runtime.ResourceDescriptor.
+func (r *Resource) GetVariables() []*variable.ResourceField {
+	return r.Variables
+}
+
+// GetDependencies implements runtime.ResourceDescriptor.
+func (r *Resource) GetDependencies() []string {
+	return r.Dependencies
+}
+
+// GetReadinessDependencies implements runtime.ResourceDescriptor.
+func (r *Resource) GetReadinessDependencies() []string {
+	return r.ReadinessDependencies
+}
+
+// GetReadyWhenExpressions implements runtime.ResourceDescriptor.
+func (r *Resource) GetReadyWhenExpressions() []string {
+	return r.ReadyWhenExpressions
+}
+
+// GetReadyWhenMessages implements runtime.ResourceDescriptor.
+func (r *Resource) GetReadyWhenMessages() map[string]string {
+	return r.ReadyWhenMessages
+}
+
+// GetDeletionPolicy implements runtime.ResourceDescriptor.
+func (r *Resource) GetDeletionPolicy() string {
+	return r.DeletionPolicy
+}
+
+// GetPriority implements runtime.ResourceDescriptor.
+func (r *Resource) GetPriority() string {
+	return r.Priority
+}
+
+// GetIncludeWhenExpressions implements runtime.ResourceDescriptor.
+func (r *Resource) GetIncludeWhenExpressions() []string {
+	return r.IncludeWhenExpressions
+}
+
+// GetTopLevelFields implements runtime.ResourceDescriptor.
+func (r *Resource) GetTopLevelFields() []string {
+	return r.TopLevelFields
+}
+
+// IsNamespaced implements runtime.ResourceDescriptor.
+func (r *Resource) IsNamespaced() bool {
+	return r.Namespaced
+}
+
+// GetSkipReadiness implements runtime.ResourceDescriptor.
+func (r *Resource) GetSkipReadiness() bool {
+	return r.SkipReadiness
+}
+
+// GetIgnoreUpdateErrors implements runtime.ResourceDescriptor.
+func (r *Resource) GetIgnoreUpdateErrors() bool {
+	return r.IgnoreUpdateErrors
+}
+
+// Unstructured implements runtime.Resource.
+func (r *Resource) Unstructured() *unstructured.Unstructured {
+	return r.Object
+}
@@ -91,3 +91,96 @@ func WithResource(
 		})
 	}
 }
+
+// WithDeletionPolicy sets the deletion policy of the resource with the given id.
+func WithDeletionPolicy(id, policy string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		r.DeletionPolicy = policy
+	})
+}
+
+// WithPriority sets the creation priority of the resource with the given id.
+func WithPriority(id, priority string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		r.Priority = priority
+	})
+}
+
+// WithReadinessDependencies sets the resources that need to be ready before
+// the resource with the given id is processed.
+func WithReadinessDependencies(id string, dependencies ...string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		r.ReadinessDependencies = dependencies
+	})
+}
+
+// WithReadyWhenMessage sets the message reported when the given readyWhen
+// condition of the resource with the given id isn't met.
+func WithReadyWhenMessage(id, condition, message string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		if r.ReadyWhenMessages == nil {
+			r.ReadyWhenMessages = map[string]string{}
+		}
+		r.ReadyWhenMessages[condition] = message
+	})
+}
+
+// WithSkipReadiness skips the readiness checks of the resource with the given id.
+func WithSkipReadiness(id string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		r.SkipReadiness = true
+	})
+}
+
+// WithIgnoreUpdateErrors ignores the update failures of the resource with the
+// given id.
+func WithIgnoreUpdateErrors(id string) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		r.IgnoreUpdateErrors = true
+	})
+}
+
+// WithPatchField makes the expression of the given field of the resource with
+// the given id produce a patch of the given type ("json" or "merge").
+func WithPatchField(id, path, patchType string) ResourceGraphDefinitionOption {
+	return withField(id, path, func(f *krov1alpha1.Field) {
+		f.PatchType = patchType
+	})
+}
+
+// WithRequiredFeatures opts the expressions of the given field of the resource
+// with the given id in to the given feature flags.
+func WithRequiredFeatures(id, path string, flags ...string) ResourceGraphDefinitionOption {
+	return withField(id, path, func(f *krov1alpha1.Field) {
+		f.RequiredFeatures = flags
+	})
+}
+
+// withField applies the given function to the settings of the given field of
+// the resource with the given id, adding them if needed.
+func withField(id, path string, apply func(*krov1alpha1.Field)) ResourceGraphDefinitionOption {
+	return withResourceSettings(id, func(r *krov1alpha1.Resource) {
+		for i := range r.Fields {
+			if r.Fields[i].Path == path {
+				apply(&r.Fields[i])
+				return
+			}
+		}
+		r.Fields = append(r.Fields, krov1alpha1.Field{Path: path})
+		apply(&r.Fields[len(r.Fields)-1])
+	})
+}
+
+// withResourceSettings applies the given function to the resource with the
+// given id. The resource must be added first.
+func withResourceSettings(id string, apply func(*krov1alpha1.Resource)) ResourceGraphDefinitionOption {
+	return func(rgd *krov1alpha1.ResourceGraphDefinition) {
+		for _, r := range rgd.Spec.Resources {
+			if r.ID == id {
+				apply(r)
+				return
+			}
+		}
+		panic("unknown resource " + id)
+	}
+}
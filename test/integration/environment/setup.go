@@ -157,6 +157,7 @@ func (e *Environment) setupController() error {
 		e.ControllerConfig.AllowCRDDeletion,
 		dc,
 		e.GraphBuilder,
+		nil,
 	)
 
 	var err error